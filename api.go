@@ -0,0 +1,223 @@
+package lenses
+
+import (
+	"context"
+	"time"
+)
+
+// API describes the whole Lenses REST API that the `Client` implements, grouped by domain.
+//
+// Code that depends on the Lenses API should accept the smallest of the domain interfaces it needs,
+// i.e `TopicsAPI`, so it can be tested without a Lenses server,
+// the `lensestest` package provides a fake implementation for each one of them.
+type API interface {
+	SessionAPI
+	ConfigAPI
+	LSQLAPI
+	TopicsAPI
+	ProcessorsAPI
+	ConnectorsAPI
+	SchemaRegistryAPI
+	ACLAPI
+	QuotaAPI
+	AlertsAPI
+}
+
+var _ API = (*Client)(nil)
+
+// SessionAPI describes the authentication session of the client.
+type SessionAPI interface {
+	GetAccessToken() string
+	User() User
+	Logout() error
+	LogoutContext(ctx context.Context) error
+}
+
+// ConfigAPI describes the calls that read the Lenses box's license and configuration.
+type ConfigAPI interface {
+	GetLicenseInfo() (LicenseInfo, error)
+	GetLicenseInfoContext(ctx context.Context) (LicenseInfo, error)
+	GetConfig() (map[string]interface{}, error)
+	GetConfigContext(ctx context.Context) (map[string]interface{}, error)
+	GetConfigEntry(outPtr interface{}, keys ...string) error
+	GetConfigEntryContext(ctx context.Context, outPtr interface{}, keys ...string) error
+	GetExecutionMode() (ExecutionMode, error)
+	GetExecutionModeContext(ctx context.Context) (ExecutionMode, error)
+	GetConnectClusters() (clusters []ConnectCluster, err error)
+	GetConnectClustersContext(ctx context.Context) (clusters []ConnectCluster, err error)
+}
+
+// LSQLAPI describes the calls that validate, run and manage the LSQL queries.
+type LSQLAPI interface {
+	ValidateLSQL(sql string) (v LSQLValidation, err error)
+	ValidateLSQLContext(ctx context.Context, sql string) (v LSQLValidation, err error)
+	LSQL(sql string, withOffsets bool, statsEvery time.Duration, recordHandler LSQLRecordHandler, stopHandler LSQLStopHandler, stopErrHandler LSQLStopErrorHandler, statsHandler LSQLStatsHandler) error
+	LSQLContext(ctx context.Context, sql string, withOffsets bool, statsEvery time.Duration, recordHandler LSQLRecordHandler, stopHandler LSQLStopHandler, stopErrHandler LSQLStopErrorHandler, statsHandler LSQLStatsHandler) error
+	LSQLWait(sql string, withOffsets bool, statsEvery time.Duration) (records []LSQLRecord, stats LSQLStats, stop LSQLStop, err error)
+	LSQLWaitContext(ctx context.Context, sql string, withOffsets bool, statsEvery time.Duration) (records []LSQLRecord, stats LSQLStats, stop LSQLStop, err error)
+	Query(ctx context.Context, sql string, opts QueryOptions) (*Rows, error)
+	GetRunningQueries() ([]LSQLRunningQuery, error)
+	GetRunningQueriesContext(ctx context.Context) ([]LSQLRunningQuery, error)
+	CancelQuery(id int64) (bool, error)
+	CancelQueryContext(ctx context.Context, id int64) (bool, error)
+	CancelRunningQuery(sql string) (bool, error)
+	CancelRunningQueryContext(ctx context.Context, sql string) (bool, error)
+}
+
+// TopicsAPI describes the topics' calls.
+type TopicsAPI interface {
+	GetTopics() (topics []Topic, err error)
+	GetTopicsContext(ctx context.Context) (topics []Topic, err error)
+	GetTopicsNames() ([]string, error)
+	GetTopicsNamesContext(ctx context.Context) ([]string, error)
+	GetTopic(topicName string) (topic Topic, err error)
+	GetTopicContext(ctx context.Context, topicName string) (topic Topic, err error)
+	CreateTopic(topicName string, replication, partitions int, configs KV) error
+	CreateTopicContext(ctx context.Context, topicName string, replication, partitions int, configs KV) error
+	UpdateTopic(topicName string, configsSlice []KV) error
+	UpdateTopicContext(ctx context.Context, topicName string, configsSlice []KV) error
+	DeleteTopic(topicName string) error
+	DeleteTopicContext(ctx context.Context, topicName string) error
+}
+
+// ProcessorsAPI describes the LSQL processors' calls.
+type ProcessorsAPI interface {
+	GetProcessors() (ProcessorsResult, error)
+	GetProcessorsContext(ctx context.Context) (ProcessorsResult, error)
+	LookupProcessorIdentifier(id, name, clusterName, namespace string) (string, error)
+	LookupProcessorIdentifierContext(ctx context.Context, id, name, clusterName, namespace string) (string, error)
+	CreateProcessor(name string, sql string, runners int, clusterName, namespace, pipeline string) error
+	CreateProcessorContext(ctx context.Context, name string, sql string, runners int, clusterName, namespace, pipeline string) error
+	PauseProcessor(processorID string) error
+	PauseProcessorContext(ctx context.Context, processorID string) error
+	ResumeProcessor(processorID string) error
+	ResumeProcessorContext(ctx context.Context, processorID string) error
+	UpdateProcessorRunners(processorID string, numberOfRunners int) error
+	UpdateProcessorRunnersContext(ctx context.Context, processorID string, numberOfRunners int) error
+	DeleteProcessor(processorNameOrID string) error
+	DeleteProcessorContext(ctx context.Context, processorNameOrID string) error
+}
+
+// ConnectorsAPI describes the Kafka Connect calls, which are proxied by Lenses.
+type ConnectorsAPI interface {
+	GetConnectors(clusterName string) (names []string, err error)
+	GetConnectorsContext(ctx context.Context, clusterName string) (names []string, err error)
+	GetConnector(clusterName, name string) (connector Connector, err error)
+	GetConnectorContext(ctx context.Context, clusterName, name string) (connector Connector, err error)
+	GetConnectorConfig(clusterName, name string) (cfg ConnectorConfig, err error)
+	GetConnectorConfigContext(ctx context.Context, clusterName, name string) (cfg ConnectorConfig, err error)
+	GetConnectorStatus(clusterName, name string) (cs ConnectorStatus, err error)
+	GetConnectorStatusContext(ctx context.Context, clusterName, name string) (cs ConnectorStatus, err error)
+	CreateConnector(clusterName, name string, config ConnectorConfig) (connector Connector, err error)
+	CreateConnectorContext(ctx context.Context, clusterName, name string, config ConnectorConfig) (connector Connector, err error)
+	UpdateConnector(clusterName, name string, config ConnectorConfig) (connector Connector, err error)
+	UpdateConnectorContext(ctx context.Context, clusterName, name string, config ConnectorConfig) (connector Connector, err error)
+	PauseConnector(clusterName, name string) error
+	PauseConnectorContext(ctx context.Context, clusterName, name string) error
+	ResumeConnector(clusterName, name string) error
+	ResumeConnectorContext(ctx context.Context, clusterName, name string) error
+	RestartConnector(clusterName, name string) error
+	RestartConnectorContext(ctx context.Context, clusterName, name string) error
+	DeleteConnector(clusterName, name string) error
+	DeleteConnectorContext(ctx context.Context, clusterName, name string) error
+	GetConnectorTasks(clusterName, name string) (m []map[string]interface{}, err error)
+	GetConnectorTasksContext(ctx context.Context, clusterName, name string) (m []map[string]interface{}, err error)
+	GetConnectorTaskStatus(clusterName, name string, taskID int) (cst ConnectorStatusTask, err error)
+	GetConnectorTaskStatusContext(ctx context.Context, clusterName, name string, taskID int) (cst ConnectorStatusTask, err error)
+	RestartConnectorTask(clusterName, name string, taskID int) error
+	RestartConnectorTaskContext(ctx context.Context, clusterName, name string, taskID int) error
+	GetConnectorPlugins(clusterName string) (cp []ConnectorPlugin, err error)
+	GetConnectorPluginsContext(ctx context.Context, clusterName string) (cp []ConnectorPlugin, err error)
+}
+
+// SchemaRegistryAPI describes the Schema Registry calls, which are proxied by Lenses.
+type SchemaRegistryAPI interface {
+	GetSubjects() (subjects []string, err error)
+	GetSubjectsContext(ctx context.Context) (subjects []string, err error)
+	GetSubjectVersions(subject string) (versions []int, err error)
+	GetSubjectVersionsContext(ctx context.Context, subject string) (versions []int, err error)
+	DeleteSubject(subject string) (versions []int, err error)
+	DeleteSubjectContext(ctx context.Context, subject string) (versions []int, err error)
+	GetSchema(subjectID int) (string, error)
+	GetSchemaContext(ctx context.Context, subjectID int) (string, error)
+	GetLatestSchema(subject string) (Schema, error)
+	GetLatestSchemaContext(ctx context.Context, subject string) (Schema, error)
+	GetSchemaAtVersion(subject string, versionID int) (Schema, error)
+	GetSchemaAtVersionContext(ctx context.Context, subject string, versionID int) (Schema, error)
+	RegisterSchema(subject string, avroSchema string) (int, error)
+	RegisterSchemaContext(ctx context.Context, subject string, avroSchema string) (int, error)
+	DeleteSubjectVersion(subject string, versionID int) (int, error)
+	DeleteSubjectVersionContext(ctx context.Context, subject string, versionID int) (int, error)
+	DeleteLatestSubjectVersion(subject string) (int, error)
+	DeleteLatestSubjectVersionContext(ctx context.Context, subject string) (int, error)
+	GetGlobalCompatibilityLevel() (level CompatibilityLevel, err error)
+	GetGlobalCompatibilityLevelContext(ctx context.Context) (level CompatibilityLevel, err error)
+	UpdateGlobalCompatibilityLevel(level CompatibilityLevel) error
+	UpdateGlobalCompatibilityLevelContext(ctx context.Context, level CompatibilityLevel) error
+	GetSubjectCompatibilityLevel(subject string) (level CompatibilityLevel, err error)
+	GetSubjectCompatibilityLevelContext(ctx context.Context, subject string) (level CompatibilityLevel, err error)
+	UpdateSubjectCompatibilityLevel(subject string, level CompatibilityLevel) error
+	UpdateSubjectCompatibilityLevelContext(ctx context.Context, subject string, level CompatibilityLevel) error
+}
+
+// ACLAPI describes the Kafka ACLs' calls.
+type ACLAPI interface {
+	GetACLs() ([]ACL, error)
+	GetACLsContext(ctx context.Context) ([]ACL, error)
+	CreateOrUpdateACL(acl ACL) error
+	CreateOrUpdateACLContext(ctx context.Context, acl ACL) error
+	DeleteACL(acl ACL) error
+	DeleteACLContext(ctx context.Context, acl ACL) error
+}
+
+// QuotaAPI describes the Kafka quotas' calls.
+type QuotaAPI interface {
+	GetQuotas() ([]Quota, error)
+	GetQuotasContext(ctx context.Context) ([]Quota, error)
+	CreateOrUpdateQuotaForAllUsers(config QuotaConfig) error
+	CreateOrUpdateQuotaForAllUsersContext(ctx context.Context, config QuotaConfig) error
+	DeleteQuotaForAllUsers(propertiesToRemove ...string) error
+	DeleteQuotaForAllUsersContext(ctx context.Context, propertiesToRemove ...string) error
+	CreateOrUpdateQuotaForUser(user string, config QuotaConfig) error
+	CreateOrUpdateQuotaForUserContext(ctx context.Context, user string, config QuotaConfig) error
+	DeleteQuotaForUser(user string, propertiesToRemove ...string) error
+	DeleteQuotaForUserContext(ctx context.Context, user string, propertiesToRemove ...string) error
+	CreateOrUpdateQuotaForUserAllClients(user string, config QuotaConfig) error
+	CreateOrUpdateQuotaForUserAllClientsContext(ctx context.Context, user string, config QuotaConfig) error
+	DeleteQuotaForUserAllClients(user string, propertiesToRemove ...string) error
+	DeleteQuotaForUserAllClientsContext(ctx context.Context, user string, propertiesToRemove ...string) error
+	CreateOrUpdateQuotaForUserClient(user, clientID string, config QuotaConfig) error
+	CreateOrUpdateQuotaForUserClientContext(ctx context.Context, user, clientID string, config QuotaConfig) error
+	DeleteQuotaForUserClient(user, clientID string, propertiesToRemove ...string) error
+	DeleteQuotaForUserClientContext(ctx context.Context, user, clientID string, propertiesToRemove ...string) error
+	CreateOrUpdateQuotaForAllClients(config QuotaConfig) error
+	CreateOrUpdateQuotaForAllClientsContext(ctx context.Context, config QuotaConfig) error
+	DeleteQuotaForAllClients(propertiesToRemove ...string) error
+	DeleteQuotaForAllClientsContext(ctx context.Context, propertiesToRemove ...string) error
+	CreateOrUpdateQuotaForClient(clientID string, config QuotaConfig) error
+	CreateOrUpdateQuotaForClientContext(ctx context.Context, clientID string, config QuotaConfig) error
+	DeleteQuotaForClient(clientID string, propertiesToRemove ...string) error
+	DeleteQuotaForClientContext(ctx context.Context, clientID string, propertiesToRemove ...string) error
+}
+
+// AlertsAPI describes the alerts' and the alert settings' calls.
+type AlertsAPI interface {
+	GetAlerts() (alerts []Alert, err error)
+	GetAlertsContext(ctx context.Context) (alerts []Alert, err error)
+	RegisterAlert(alert Alert) error
+	RegisterAlertContext(ctx context.Context, alert Alert) error
+	GetAlertsLive(handler AlertHandler) error
+	GetAlertsLiveContext(ctx context.Context, handler AlertHandler) error
+	GetAlertSettings() (AlertSettings, error)
+	GetAlertSettingsContext(ctx context.Context) (AlertSettings, error)
+	GetAlertSetting(id int) (setting AlertSetting, err error)
+	GetAlertSettingContext(ctx context.Context, id int) (setting AlertSetting, err error)
+	EnableAlertSetting(id int) error
+	EnableAlertSettingContext(ctx context.Context, id int) error
+	GetAlertSettingConditions(id int) (AlertSettingConditions, error)
+	GetAlertSettingConditionsContext(ctx context.Context, id int) (AlertSettingConditions, error)
+	CreateOrUpdateAlertSettingCondition(alertSettingID int, condition string) error
+	CreateOrUpdateAlertSettingConditionContext(ctx context.Context, alertSettingID int, condition string) error
+	DeleteAlertSettingCondition(alertSettingID int, conditionUUID string) error
+	DeleteAlertSettingConditionContext(ctx context.Context, alertSettingID int, conditionUUID string) error
+}
@@ -1,9 +1,10 @@
 package lenses
 
 import (
-	"bufio"
 	"bytes"
 	"compress/gzip"
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,9 +12,8 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
-
-	"github.com/kataras/golog"
 )
 
 // Client is the lenses http client.
@@ -27,6 +27,38 @@ type Client struct {
 
 	// the client is created on the `lenses#OpenConnection` function, it can be customized via options.
 	client *http.Client
+	// tlsConfig is generated by the configuration's TLS fields, it's used by the client's transport.
+	tlsConfig *tls.Config
+
+	// authMu protects the token and the user, they are renewed
+	// when the token is expired and the configuration contains the user's credentials.
+	authMu sync.RWMutex
+	// loginMu makes sure that only one login is in-flight,
+	// no matter how many concurrent requests failed because of an expired token.
+	loginMu sync.Mutex
+	// tokenRefreshHandler is fired after a successful re-authentication, see `UsingTokenRefreshHandler`.
+	tokenRefreshHandler TokenRefreshHandler
+	// retryPolicy is used to send again the requests that failed because of transient failures, see `UsingRetryPolicy`.
+	retryPolicy *RetryPolicy
+	// interceptors are wrapping the requests sending, see `UsingInterceptors`.
+	interceptors []Interceptor
+
+	// hosts are the configuration's hosts, the requests are sent to the active one, see `ActiveHost`.
+	hosts         []string
+	activeHostIdx int32 // atomic.
+	failoverMu    sync.Mutex
+	healthProbe   HealthProbe
+
+	// logger defaults to a new golog instance, see `UsingLogger`.
+	logger Logger
+	// unredacted shows the secrets in the log messages, they are redacted by default, see `UsingUnredactedLogs`.
+	unredacted unredacted
+
+	// the limits of the records that the `LSQLWait` keeps in memory, see `UsingLSQLWaitLimits`.
+	lsqlWaitMaxRecords int
+	lsqlWaitMaxBytes   int64
+	// sseOpts are the options of the server-sent events streams, see `UsingSSEOptions`.
+	sseOpts SSEOptions
 }
 
 var noOpBuffer = new(bytes.Buffer)
@@ -68,6 +100,7 @@ const (
 
 // ErrCredentialsMissing fires on login, when credentials are missing or
 // are invalid or the specific user has no access to a specific action.
+// The API calls fire an `APIError` which can be checked against it using the `errors.Is`.
 var ErrCredentialsMissing = fmt.Errorf("client: credentials missing or invalid")
 
 type requestOption func(r *http.Request)
@@ -78,26 +111,24 @@ var schemaAPIOption = func(r *http.Request) {
 
 // ErrResourceNotFound is being fired from all API calls when a 404 not found error code is received.
 // It's a static error message of just `404`, therefore it can be used to add additional info messages based on the caller's action.
+// The API calls fire an `APIError` which can be checked against it using the `errors.Is`.
 var ErrResourceNotFound = fmt.Errorf("%d", http.StatusNotFound)
 
-func (c *Client) do(method, path, contentType string, send []byte, options ...requestOption) (*http.Response, error) {
-	if path[0] == '/' { // remove beginning slash, if any.
-		path = path[1:]
-	}
-
-	uri := c.config.Host + "/" + path
-
-	golog.Debugf("Client#do.req:\n\turi: %s:%s\n\tsend: %s", method, uri, string(send))
-
+// newRequest creates a new request based on the `Client#do`'s input,
+// it returns the request and the token that it was sent with, the token is used to renew the access on 401.
+func (c *Client) newRequest(ctx context.Context, method, path, uri, contentType string, send []byte, options ...requestOption) (*http.Request, string, error) {
 	req, err := http.NewRequest(method, uri, acquireBuffer(send))
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
+	// bind the caller's context, the request is aborted when the context is canceled or its deadline is exceeded.
+	req = req.WithContext(ctx)
 	// before sending requests here.
 
-	// set the token header.
-	if c.config.Token != "" {
-		req.Header.Set(xKafkaLensesTokenHeaderKey, c.config.Token)
+	// set the token header, the login does not need it.
+	token := c.GetAccessToken()
+	if token != "" && path != loginPath {
+		req.Header.Set(xKafkaLensesTokenHeaderKey, token)
 	}
 
 	// set the content type if any.
@@ -112,44 +143,61 @@ func (c *Client) do(method, path, contentType string, send []byte, options ...re
 		opt(req)
 	}
 
-	// here will print all the headers, including the token (because it may be useful for debugging)
-	// --so bug reporters should be careful here to invalidate the token after that.
-	golog.Debugf("Client#do.req.Headers: %#+v", req.Header)
-
-	// send the request and check the response for any connection & authorization errors here.
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
+	// the token and the login's payload are redacted, unless otherwise requested, see `UsingUnredactedLogs`.
+	body := string(send)
+	if path == loginPath {
+		body = c.unredacted.redact(body)
 	}
 
-	if !isAuthorized(resp) {
-		resp.Body.Close() // close the body here so we don't have leaks.
-		return nil, ErrCredentialsMissing
+	c.logger.Debug("Client#do.req",
+		F("uri", method+":"+c.unredacted.redactURI(uri)),
+		F("send", body),
+		F("headers", c.unredacted.redactHeader(req.Header)))
+
+	return req, token, nil
+}
+
+func (c *Client) do(ctx context.Context, op, method, path, contentType string, send []byte, options ...requestOption) (*http.Response, error) {
+	if path[0] == '/' { // remove beginning slash, if any.
+		path = path[1:]
 	}
 
-	if !isOK(resp) {
-		unescapedURI, _ := url.QueryUnescape(uri)
-		var errBody string
+	var resp *http.Response
 
-		if resp.StatusCode != http.StatusNotFound {
-			// if the status is not just a 404, then give the whole
-			// body to the error context.
-			b, err := c.readResponseBody(resp)
-			if err != nil {
-				errBody = "unable to read body: " + err.Error()
-			}
+	for replayed := false; ; replayed = true {
+		// send the request and check the response for any connection & authorization errors here.
+		var (
+			token string
+			err   error
+		)
+		resp, token, err = c.send(ctx, op, method, path, contentType, send, options...)
+		if err != nil {
+			return nil, err
+		}
 
-			errBody = "\n" + string(b)
-		} else {
-			defer resp.Body.Close()
+		if isAuthorized(resp) {
+			break
 		}
 
-		if !c.config.Debug && resp.StatusCode == http.StatusNotFound {
-			// if status code is 404, then we set a static error instead of a full message, so front-ends can check.
-			return nil, ErrResourceNotFound
+		// the token may be expired, login again and replay the request once,
+		// if that's not possible or the request failed with the renewed token as well
+		// then the credentials are invalid.
+		if replayed || !c.canReauthenticate(path) {
+			return nil, c.newAPIError(method, resp)
 		}
-		return nil, fmt.Errorf("client: (%s: %s) failed with status code %d%s",
-			method, unescapedURI, resp.StatusCode, errBody)
+
+		resp.Body.Close() // close the body here so we don't have leaks.
+
+		if err = c.reauthenticate(ctx, token); err != nil {
+			return nil, err
+		}
+
+		c.logger.Debug("Client#do: token renewed, replaying the request", F("uri", method+":"+c.unredacted.redactURI(path)))
+	}
+
+	if !isOK(resp) {
+		// give the whole response to the error, so callers can check the status code and the server's message.
+		return nil, c.newAPIError(method, resp)
 	}
 
 	return resp, nil
@@ -215,8 +263,12 @@ func (c *Client) readResponseBody(resp *http.Response) ([]byte, error) {
 
 	if c.config.Debug {
 		rawBodyString := string(body)
+		// the login's response contains the token.
+		if resp.Request != nil && strings.HasSuffix(resp.Request.URL.Path, "/"+loginPath) {
+			rawBodyString = c.unredacted.redact(rawBodyString)
+		}
 		// print both body and error, because both of them may be formated by the `readResponseBody`'s caller.
-		golog.Debugf("Client#do.resp:\n\tbody: %s\n\terror: %v", rawBodyString, err)
+		c.logger.Debug("Client#do.resp", F("body", rawBodyString), F("error", err))
 	}
 
 	// return the body.
@@ -234,14 +286,22 @@ func (c *Client) readJSON(resp *http.Response, valuePtr interface{}) error {
 
 // GetAccessToken returns the access token that
 // generated from the `OpenConnection` or given by the configuration.
+// The token may be renewed automatically if the configuration contains the user's credentials,
+// see `UsingTokenRefreshHandler` too.
 func (c *Client) GetAccessToken() string {
-	return c.config.Token
+	c.authMu.RLock()
+	token := c.config.Token
+	c.authMu.RUnlock()
+	return token
 }
 
 // User returns the User information from `/api/login`
 // received by `OpenConnection`.
 func (c *Client) User() User {
-	return c.user
+	c.authMu.RLock()
+	user := c.user
+	c.authMu.RUnlock()
+	return user
 } /* we don't expose the token value, unless otherwise requested*/
 
 const logoutPath = "api/logout?token="
@@ -249,12 +309,18 @@ const logoutPath = "api/logout?token="
 // Logout invalidates the token and revoke its access.
 // A new Client, using `OpenConnection`, should be created in order to continue after this call.
 func (c *Client) Logout() error {
-	if c.config.Token == "" {
+	return c.LogoutContext(context.Background())
+}
+
+// LogoutContext same as `Logout` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) LogoutContext(ctx context.Context) error {
+	token := c.GetAccessToken()
+	if token == "" {
 		return ErrCredentialsMissing
 	}
 
-	path := logoutPath + c.config.Token
-	resp, err := c.do(http.MethodGet, path, "", nil)
+	path := logoutPath + token
+	resp, err := c.do(ctx, "Logout", http.MethodGet, path, "", nil)
 	if err != nil {
 		return err
 	}
@@ -290,9 +356,14 @@ const licensePath = "/api/license"
 
 // GetLicenseInfo returns the license information for the connected lenses box.
 func (c *Client) GetLicenseInfo() (LicenseInfo, error) {
+	return c.GetLicenseInfoContext(context.Background())
+}
+
+// GetLicenseInfoContext same as `GetLicenseInfo` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetLicenseInfoContext(ctx context.Context) (LicenseInfo, error) {
 	var lc LicenseInfo
 
-	resp, err := c.do(http.MethodGet, licensePath, "", nil)
+	resp, err := c.do(ctx, "GetLicenseInfo", http.MethodGet, licensePath, "", nil)
 	if err != nil {
 		return lc, err
 	}
@@ -365,7 +436,12 @@ const (
 // To retrieve the execution mode of the box with safety,
 // see the `Client#GetExecutionMode` instead.
 func (c *Client) GetConfig() (map[string]interface{}, error) {
-	resp, err := c.do(http.MethodGet, configPath, "", nil, func(r *http.Request) {
+	return c.GetConfigContext(context.Background())
+}
+
+// GetConfigContext same as `GetConfig` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetConfigContext(ctx context.Context) (map[string]interface{}, error) {
+	resp, err := c.do(ctx, "GetConfig", http.MethodGet, configPath, "", nil, func(r *http.Request) {
 		r.Header.Set("Accept", "application/json, text/plain")
 	})
 
@@ -382,7 +458,12 @@ func (c *Client) GetConfig() (map[string]interface{}, error) {
 
 // GetConfigEntry reads the lenses back-end configuration and sets the value of a key, based on "keys", to the "outPtr".
 func (c *Client) GetConfigEntry(outPtr interface{}, keys ...string) error {
-	config, err := c.GetConfig()
+	return c.GetConfigEntryContext(context.Background(), outPtr, keys...)
+}
+
+// GetConfigEntryContext same as `GetConfigEntry` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetConfigEntryContext(ctx context.Context, outPtr interface{}, keys ...string) error {
+	config, err := c.GetConfigContext(ctx)
 	if err != nil || config == nil {
 		return fmt.Errorf("%s: cannot be extracted: unable to retrieve the config: %v", keys, err)
 	}
@@ -454,8 +535,13 @@ const executionModeKey = "lenses.sql.execution.mode"
 // GetExecutionMode returns the execution mode, if not error returned
 // then the possible values are: ExecutionModeInProc, ExecutionModeConnect or ExecutionModeKubernetes.
 func (c *Client) GetExecutionMode() (ExecutionMode, error) {
+	return c.GetExecutionModeContext(context.Background())
+}
+
+// GetExecutionModeContext same as `GetExecutionMode` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetExecutionModeContext(ctx context.Context) (ExecutionMode, error) {
 	var modeStr string
-	if err := c.GetConfigEntry(&modeStr, executionModeKey); err != nil {
+	if err := c.GetConfigEntryContext(ctx, &modeStr, executionModeKey); err != nil {
 		return ExecutionModeInvalid, err
 	}
 	return ExecutionMode(modeStr), nil
@@ -474,7 +560,12 @@ const connectClustersKey = "lenses.connect.clusters"
 
 // GetConnectClusters returns the `lenses.connect.clusters` key from the lenses configuration (`GetConfig`).
 func (c *Client) GetConnectClusters() (clusters []ConnectCluster, err error) {
-	err = c.GetConfigEntry(&clusters, connectClustersKey)
+	return c.GetConnectClustersContext(context.Background())
+}
+
+// GetConnectClustersContext same as `GetConnectClusters` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetConnectClustersContext(ctx context.Context) (clusters []ConnectCluster, err error) {
+	err = c.GetConfigEntryContext(ctx, &clusters, connectClustersKey)
 	return
 }
 
@@ -501,13 +592,18 @@ const validateLSQLPath = "api/sql/validation?sql="
 
 // ValidateLSQL validates but not executes a specific LSQL.
 func (c *Client) ValidateLSQL(sql string) (v LSQLValidation, err error) {
+	return c.ValidateLSQLContext(context.Background(), sql)
+}
+
+// ValidateLSQLContext same as `ValidateLSQL` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) ValidateLSQLContext(ctx context.Context, sql string) (v LSQLValidation, err error) {
 	if sql == "" {
 		err = errSQLEmpty
 		return
 	}
 
 	path := validateLSQLPath + url.QueryEscape(sql)
-	resp, respErr := c.do(http.MethodGet, path, contentTypeJSON, nil)
+	resp, respErr := c.do(ctx, "ValidateLSQL", http.MethodGet, path, contentTypeJSON, nil)
 	if respErr != nil {
 		err = respErr
 		return
@@ -527,6 +623,9 @@ type (
 		Offset    int    `json:"offset"`
 		Topic     string `json:"topic"`
 		Value     string `json:"value"` // represents a json object, in raw string.
+
+		// offset is the received offset, it keeps the offsets that do not fit to the 32-bit int, see `Offset64`.
+		offset int64
 	}
 
 	// LSQLStop the form of the stop record data that LSQL call returns once.
@@ -605,15 +704,12 @@ func (err LSQLError) Error() string {
 
 const lsqlPath = "api/sql/data?sql="
 
-var dataPrefix = []byte("data")
-
+// The payload of each event's data starts with its type:
 // 0- heartbeat. payload is just: "0"
 // 1- this represents a record (previousely it was 0).
 // 2- represents the end record (previousely it was 1)
 // 3- represents an error . it also represents the end (previousely it was 2)
 // 4- represents the stats record (previousely it was 3)
-var shiftN = len(dataPrefix) + 1 // data:0message, i.e [len(dataPrefix)] == ':' so +1 == '0'.
-
 const (
 	heartBeatPayloadType = '0'
 	recordPayloadType    = '1'
@@ -631,149 +727,107 @@ func (c *Client) LSQL(
 	stopHandler LSQLStopHandler,
 	stopErrHandler LSQLStopErrorHandler,
 	statsHandler LSQLStatsHandler) error {
+	return c.LSQLContext(context.Background(), sql, withOffsets, statsEvery, recordHandler, stopHandler, stopErrHandler, statsHandler)
+}
 
-	if sql == "" {
-		return errSQLEmpty
-	}
-
-	withStop := stopHandler != nil
-
-	statsEverySeconds := int(statsEvery.Seconds())
-	withStats := statsHandler != nil && statsEverySeconds > 1
+// QueryCancelError is returned by the `LSQLContext` when its context is done before the query is finished
+// and the query could not be canceled on the server side, it may keep running until its max.time.
+type QueryCancelError struct {
+	// Err is the `ErrRunningQueryNotFound` or the failure of the cancel request.
+	Err error
+}
 
-	path := lsqlPath + url.QueryEscape(sql)
-	// no need to use the url package for these, remember: we have already the ? on the `lsqlPath`.
-	if withOffsets {
-		path += "&offsets=true"
-	}
+func (err *QueryCancelError) Error() string {
+	return fmt.Sprintf("client: query could not be canceled on the server: %v", err.Err)
+}
 
-	if withStats {
-		path += fmt.Sprintf("&stats=%d", statsEverySeconds)
-	}
+// LSQLContext same as `LSQL` but it accepts a context.Context for cancellation and deadlines.
+// When the "ctx" is canceled before the query is finished, the query is canceled on the server side too, see `CancelRunningQuery`,
+// the error is the context's one if it was canceled, otherwise a `*QueryCancelError`.
+func (c *Client) LSQLContext(
+	ctx context.Context,
+	sql string, withOffsets bool, statsEvery time.Duration,
+	recordHandler LSQLRecordHandler,
+	stopHandler LSQLStopHandler,
+	stopErrHandler LSQLStopErrorHandler,
+	statsHandler LSQLStatsHandler) (err error) {
 
-	// it's sse, so accept text/event-stream and stream reading the response body, no
-	// external libraries needed, it is fairly simple.
-	resp, err := c.do(http.MethodGet, path, contentTypeJSON, nil, func(r *http.Request) {
-		r.Header.Add(acceptHeaderKey, "application/json, text/event-stream")
-	}, schemaAPIOption)
-	if err != nil {
-		return err
+	opts := QueryOptions{WithOffsets: withOffsets}
+	if statsHandler != nil {
+		opts.StatsEvery = statsEvery
 	}
 
-	defer resp.Body.Close()
-	reader, err := c.acquireResponseBodyStream(resp)
+	rows, err := c.Query(ctx, sql, opts)
 	if err != nil {
 		return err
 	}
-
-	streamReader := bufio.NewReader(reader)
-
-	for {
-		line, err := streamReader.ReadBytes('\n')
-		if err != nil {
-			if err == io.EOF {
-				return nil // we read until the the end, exit with no error here.
+	defer func() {
+		// the running query is canceled on the server side only when the "ctx" is done, i.e on interrupt,
+		// otherwise the handlers decide when to stop.
+		if ctx.Err() != nil {
+			if closeErr := rows.Close(); closeErr != nil {
+				err = &QueryCancelError{Err: closeErr}
 			}
-			return err // exit on first failure.
-		}
-
-		if len(line) < shiftN+1 { // even more +1 for the actual event.
-			// almost empty or totally invalid line,
-			// empty message maybe,
-			// we don't care, we ignore them at any way.
-			continue
+			return
 		}
 
-		if !bytes.HasPrefix(line, dataPrefix) {
-			return fmt.Errorf("client: see: fail to read the event, the incoming message has no %s prefix", string(dataPrefix))
-		}
-
-		messageType := line[shiftN] // we need the [0] here.
-		message := line[shiftN+1:]  // we need everything after the '0' here , so shiftN+1.
-
-		switch messageType {
-		case heartBeatPayloadType:
-			break // ignore.
-		case recordPayloadType:
-			record := LSQLRecord{}
-			if err = json.Unmarshal(message, &record); err != nil {
-				// exit on first error here as well.
-				return err
-			}
-
-			if err = recordHandler(record); err != nil {
-				return err
-			}
-			break
-		case stopPayloadType:
-			if !withStop {
-				return nil
-			}
+		rows.closeStream()
+	}()
 
-			stopMessage := LSQLStop{}
-			if err = json.Unmarshal(message, &stopMessage); err != nil {
-				return err
-			}
+	if statsHandler != nil {
+		rows.onStats = statsHandler
+	}
 
-			// And STOP.
-			return stopHandler(stopMessage)
-		case errPayloadType:
-			errMessage := LSQLError{}
-			if err = json.Unmarshal(message, &errMessage); err != nil {
-				return err
-			}
+	for rows.Next() {
+		if err = recordHandler(rows.Record()); err != nil {
+			return err
+		}
+	}
 
+	if err = rows.Err(); err != nil {
+		if errMessage, ok := err.(LSQLError); ok && stopErrHandler != nil {
 			// And STOP.
 			return stopErrHandler(errMessage)
-		case statsPayloadType:
-			// here we don't necessarily need to check
-			// for the existence of the stats handler
-			// because the server sends those stats only
-			// if the query param exists and it exists if the stats handler != nil,
-			// and we did that check already.
-			// BUT we make that check here too to prevent any future panics if back-end change.
-			if !withStats {
-				break
-			}
-
-			statsMessage := LSQLStats{}
-			if err = json.Unmarshal(message, &statsMessage); err != nil {
-				return err
-			}
+		}
 
-			if err = statsHandler(statsMessage); err != nil {
-				return err
-			}
+		return err
+	}
 
-			break
-		default:
-			return fmt.Errorf("client: sse: unknown event received: %s", string(line))
-		}
+	if stopHandler == nil || !rows.withStop {
+		return nil
 	}
+
+	// And STOP.
+	return stopHandler(rows.Stop())
 }
 
 // LSQLWait same as `LSQL` but waits until stop or error to return the query's results records, the stats and the stop information.
+// The records are kept in memory, therefore they are limited, see `UsingLSQLWaitLimits`,
+// when a limit is exceeded the records so far are returned along with the `ErrQueryLimitExceeded`.
+//
+// Prefer the `Query` for large results.
 func (c *Client) LSQLWait(sql string, withOffsets bool, statsEvery time.Duration) (records []LSQLRecord, stats LSQLStats, stop LSQLStop, err error) {
-	c.LSQL(sql, withOffsets, statsEvery,
-		func(r LSQLRecord) error {
-			records = append(records, r)
-			return nil
-		},
-		func(s LSQLStop) error {
-			stop = s
-			return nil
-		},
-		func(e LSQLError) error {
-			err = e
-			return nil
-		},
-		func(s LSQLStats) error {
-			stats = s
-			return nil
-		},
-	)
+	return c.LSQLWaitContext(context.Background(), sql, withOffsets, statsEvery)
+}
 
-	return
+// LSQLWaitContext same as `LSQLWait` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) LSQLWaitContext(ctx context.Context, sql string, withOffsets bool, statsEvery time.Duration) (records []LSQLRecord, stats LSQLStats, stop LSQLStop, err error) {
+	rows, err := c.Query(ctx, sql, QueryOptions{
+		WithOffsets: withOffsets,
+		StatsEvery:  statsEvery,
+		MaxRecords:  c.lsqlWaitMaxRecords,
+		MaxBytes:    c.lsqlWaitMaxBytes,
+	})
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		records = append(records, rows.Record())
+	}
+
+	return records, rows.Stats(), rows.Stop(), rows.Err()
 }
 
 const queriesPath = "api/sql/queries"
@@ -788,7 +842,12 @@ type LSQLRunningQuery struct {
 
 // GetRunningQueries returns a list of the current sql running queries.
 func (c *Client) GetRunningQueries() ([]LSQLRunningQuery, error) {
-	resp, err := c.do(http.MethodGet, queriesPath, "", nil)
+	return c.GetRunningQueriesContext(context.Background())
+}
+
+// GetRunningQueriesContext same as `GetRunningQueries` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetRunningQueriesContext(ctx context.Context) ([]LSQLRunningQuery, error) {
+	resp, err := c.do(ctx, "GetRunningQueries", http.MethodGet, queriesPath, "", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -801,8 +860,13 @@ func (c *Client) GetRunningQueries() ([]LSQLRunningQuery, error) {
 // CancelQuery stops a running query based on its ID.
 // It returns true whether it was cancelled otherwise false or/and error.
 func (c *Client) CancelQuery(id int64) (bool, error) {
+	return c.CancelQueryContext(context.Background(), id)
+}
+
+// CancelQueryContext same as `CancelQuery` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) CancelQueryContext(ctx context.Context, id int64) (bool, error) {
 	path := fmt.Sprintf(queriesPath+"/%d", id)
-	resp, err := c.do(http.MethodDelete, path, "", nil)
+	resp, err := c.do(ctx, "CancelQuery", http.MethodDelete, path, "", nil)
 	if err != nil {
 		return false, err
 	}
@@ -828,10 +892,15 @@ const topicsPath = "api/topics"
 
 // GetTopics returns the list of topics.
 func (c *Client) GetTopics() (topics []Topic, err error) {
+	return c.GetTopicsContext(context.Background())
+}
+
+// GetTopicsContext same as `GetTopics` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetTopicsContext(ctx context.Context) (topics []Topic, err error) {
 	// # List of topics
 	// GET /api/topics
 	// https://docs.confluent.io/current/kafka-rest/docs/api.html#get--topics (in that doc it says a list of topic names but it returns the full topics).
-	resp, respErr := c.do(http.MethodGet, topicsPath, "", nil)
+	resp, respErr := c.do(ctx, "GetTopics", http.MethodGet, topicsPath, "", nil)
 	if respErr != nil {
 		err = respErr
 		return
@@ -843,7 +912,12 @@ func (c *Client) GetTopics() (topics []Topic, err error) {
 
 // GetTopicsNames returns the list of topics' names.
 func (c *Client) GetTopicsNames() ([]string, error) {
-	topics, err := c.GetTopics()
+	return c.GetTopicsNamesContext(context.Background())
+}
+
+// GetTopicsNamesContext same as `GetTopicsNames` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetTopicsNamesContext(ctx context.Context) ([]string, error) {
+	topics, err := c.GetTopicsContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -873,6 +947,11 @@ type CreateTopicPayload struct {
 //
 // Read more at: http://lenses.stream/dev/lenses-apis/rest-api/index.html#create-topic
 func (c *Client) CreateTopic(topicName string, replication, partitions int, configs KV) error {
+	return c.CreateTopicContext(context.Background(), topicName, replication, partitions, configs)
+}
+
+// CreateTopicContext same as `CreateTopic` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) CreateTopicContext(ctx context.Context, topicName string, replication, partitions int, configs KV) error {
 	if topicName == "" {
 		return errRequired("topicName")
 	}
@@ -889,7 +968,7 @@ func (c *Client) CreateTopic(topicName string, replication, partitions int, conf
 		return err
 	}
 
-	resp, err := c.do(http.MethodPost, topicsPath, contentTypeJSON, send)
+	resp, err := c.do(ctx, "CreateTopic", http.MethodPost, topicsPath, contentTypeJSON, send)
 	if err != nil {
 		return err
 	}
@@ -904,12 +983,17 @@ const topicPath = topicsPath + "/%s"
 //
 // Read more at: http://lenses.stream/dev/lenses-apis/rest-api/index.html#delete-topic
 func (c *Client) DeleteTopic(topicName string) error {
+	return c.DeleteTopicContext(context.Background(), topicName)
+}
+
+// DeleteTopicContext same as `DeleteTopic` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) DeleteTopicContext(ctx context.Context, topicName string) error {
 	if topicName == "" {
 		return errRequired("topicName")
 	}
 
 	path := fmt.Sprintf(topicPath, topicName)
-	resp, err := c.do(http.MethodDelete, path, "", nil)
+	resp, err := c.do(ctx, "DeleteTopic", http.MethodDelete, path, "", nil)
 	if err != nil {
 		return err
 	}
@@ -931,6 +1015,11 @@ type UpdateTopicPayload struct {
 //
 // Read more at: http://lenses.stream/dev/lenses-apis/rest-api/index.html#update-topic-configuration
 func (c *Client) UpdateTopic(topicName string, configsSlice []KV) error {
+	return c.UpdateTopicContext(context.Background(), topicName, configsSlice)
+}
+
+// UpdateTopicContext same as `UpdateTopic` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) UpdateTopicContext(ctx context.Context, topicName string, configsSlice []KV) error {
 	if topicName == "" {
 		return errRequired("topicName")
 	}
@@ -943,7 +1032,7 @@ func (c *Client) UpdateTopic(topicName string, configsSlice []KV) error {
 	}
 
 	path := fmt.Sprintf(updateTopicConfigPath, topicName)
-	resp, err := c.do(http.MethodPut, path, contentTypeJSON, send)
+	resp, err := c.do(ctx, "UpdateTopic", http.MethodPut, path, contentTypeJSON, send)
 	if err != nil {
 		return err
 	}
@@ -1038,13 +1127,18 @@ type PartitionMessage struct {
 //
 // Read more at: http://lenses.stream/dev/lenses-apis/rest-api/index.html#get-topic-information
 func (c *Client) GetTopic(topicName string) (topic Topic, err error) {
+	return c.GetTopicContext(context.Background(), topicName)
+}
+
+// GetTopicContext same as `GetTopic` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetTopicContext(ctx context.Context, topicName string) (topic Topic, err error) {
 	if topicName == "" {
 		err = errRequired("topicName")
 		return
 	}
 
 	path := fmt.Sprintf(topicPath, topicName)
-	resp, respErr := c.do(http.MethodGet, path, "", nil)
+	resp, respErr := c.do(ctx, "GetTopic", http.MethodGet, path, "", nil)
 	if respErr != nil {
 		err = respErr
 		return
@@ -1070,6 +1164,11 @@ type CreateProcessorPayload struct {
 
 // CreateProcessor creates a new LSQL processor.
 func (c *Client) CreateProcessor(name string, sql string, runners int, clusterName, namespace, pipeline string) error {
+	return c.CreateProcessorContext(context.Background(), name, sql, runners, clusterName, namespace, pipeline)
+}
+
+// CreateProcessorContext same as `CreateProcessor` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) CreateProcessorContext(ctx context.Context, name string, sql string, runners int, clusterName, namespace, pipeline string) error {
 	if name == "" {
 		return errRequired("name")
 	}
@@ -1100,7 +1199,7 @@ func (c *Client) CreateProcessor(name string, sql string, runners int, clusterNa
 		return err
 	}
 
-	resp, err := c.do(http.MethodPost, processorsPath, contentTypeJSON, send)
+	resp, err := c.do(ctx, "CreateProcessor", http.MethodPost, processorsPath, contentTypeJSON, send)
 	if err != nil {
 		return err
 	}
@@ -1158,9 +1257,14 @@ type (
 
 // GetProcessors returns a list of all available LSQL processors.
 func (c *Client) GetProcessors() (ProcessorsResult, error) {
+	return c.GetProcessorsContext(context.Background())
+}
+
+// GetProcessorsContext same as `GetProcessors` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetProcessorsContext(ctx context.Context) (ProcessorsResult, error) {
 	var res ProcessorsResult
 
-	resp, err := c.do(http.MethodGet, processorsPath, "", nil)
+	resp, err := c.do(ctx, "GetProcessors", http.MethodGet, processorsPath, "", nil)
 	if err != nil {
 		return res, err
 	}
@@ -1178,11 +1282,16 @@ func (c *Client) GetProcessors() (ProcessorsResult, error) {
 // Fill the id or name in any case.
 // Fill the clusterName and namespace when in KUBERNETES execution mode.
 func (c *Client) LookupProcessorIdentifier(id, name, clusterName, namespace string) (string, error) {
+	return c.LookupProcessorIdentifierContext(context.Background(), id, name, clusterName, namespace)
+}
+
+// LookupProcessorIdentifierContext same as `LookupProcessorIdentifier` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) LookupProcessorIdentifierContext(ctx context.Context, id, name, clusterName, namespace string) (string, error) {
 	if name == "" && id == "" {
 		return "", fmt.Errorf("LookupProcessorIdentifier: name or id are missing")
 	}
 
-	mode, err := c.GetExecutionMode()
+	mode, err := c.GetExecutionModeContext(ctx)
 	if err != nil {
 		return "", err // unable to determinate the lenses execution mode.
 	}
@@ -1194,7 +1303,7 @@ func (c *Client) LookupProcessorIdentifier(id, name, clusterName, namespace stri
 			identifier = id
 		} else if name != "" {
 			// get the id by looping over all available processors.
-			result, err := c.GetProcessors()
+			result, err := c.GetProcessorsContext(ctx)
 			if err != nil {
 				return "", err
 			}
@@ -1236,12 +1345,17 @@ const processorPausePath = processorPath + "/pause"
 // PauseProcessor pauses a processor.
 // See `LookupProcessorIdentifier`.
 func (c *Client) PauseProcessor(processorID string) error {
+	return c.PauseProcessorContext(context.Background(), processorID)
+}
+
+// PauseProcessorContext same as `PauseProcessor` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) PauseProcessorContext(ctx context.Context, processorID string) error {
 	if processorID == "" {
 		return errRequired("processorID")
 	}
 
 	path := fmt.Sprintf(processorPath+"/pause", processorID)
-	resp, err := c.do(http.MethodPut, path, "", nil)
+	resp, err := c.do(ctx, "PauseProcessor", http.MethodPut, path, "", nil)
 	if err != nil {
 		return err
 	}
@@ -1253,12 +1367,17 @@ const processorResumePath = processorPath + "/resume"
 // ResumeProcessor resumes a processor.
 // See `LookupProcessorIdentifier`.
 func (c *Client) ResumeProcessor(processorID string) error {
+	return c.ResumeProcessorContext(context.Background(), processorID)
+}
+
+// ResumeProcessorContext same as `ResumeProcessor` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) ResumeProcessorContext(ctx context.Context, processorID string) error {
 	if processorID == "" {
 		return errRequired("processorID")
 	}
 
 	path := fmt.Sprintf(processorResumePath, processorID)
-	resp, err := c.do(http.MethodPut, path, "", nil)
+	resp, err := c.do(ctx, "ResumeProcessor", http.MethodPut, path, "", nil)
 	if err != nil {
 		return err
 	}
@@ -1270,6 +1389,11 @@ const processorUpdateRunnersPath = processorPath + "/scale/%d"
 // UpdateProcessorRunners scales a processor to "numberOfRunners".
 // See `LookupProcessorIdentifier`.
 func (c *Client) UpdateProcessorRunners(processorID string, numberOfRunners int) error {
+	return c.UpdateProcessorRunnersContext(context.Background(), processorID, numberOfRunners)
+}
+
+// UpdateProcessorRunnersContext same as `UpdateProcessorRunners` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) UpdateProcessorRunnersContext(ctx context.Context, processorID string, numberOfRunners int) error {
 	if processorID == "" {
 		return errRequired("processorID")
 	}
@@ -1279,7 +1403,7 @@ func (c *Client) UpdateProcessorRunners(processorID string, numberOfRunners int)
 	}
 
 	path := fmt.Sprintf(processorUpdateRunnersPath, processorID, numberOfRunners)
-	resp, err := c.do(http.MethodPut, path, "", nil)
+	resp, err := c.do(ctx, "UpdateProcessorRunners", http.MethodPut, path, "", nil)
 	if err != nil {
 		return err
 	}
@@ -1289,12 +1413,17 @@ func (c *Client) UpdateProcessorRunners(processorID string, numberOfRunners int)
 // DeleteProcessor removes a processor based on its name or the full id,
 // it depends on lenses execution mode, use the `LookupProcessorIdentifier`.
 func (c *Client) DeleteProcessor(processorNameOrID string) error {
+	return c.DeleteProcessorContext(context.Background(), processorNameOrID)
+}
+
+// DeleteProcessorContext same as `DeleteProcessor` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) DeleteProcessorContext(ctx context.Context, processorNameOrID string) error {
 	if processorNameOrID == "" {
 		return errRequired("processorNameOrID")
 	}
 
 	path := fmt.Sprintf(processorPath, processorNameOrID)
-	resp, err := c.do(http.MethodDelete, path, "", nil)
+	resp, err := c.do(ctx, "DeleteProcessor", http.MethodDelete, path, "", nil)
 	if err != nil {
 		return err
 	}
@@ -1347,6 +1476,11 @@ const connectorsPath = "/api/proxy-connect/%s/connectors"
 // Visit http://lenses.stream/dev/lenses-apis/rest-api/index.html#connector-api
 // and https://docs.confluent.io/current/connect/restapi.html for a deeper understanding.
 func (c *Client) GetConnectors(clusterName string) (names []string, err error) {
+	return c.GetConnectorsContext(context.Background(), clusterName)
+}
+
+// GetConnectorsContext same as `GetConnectors` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetConnectorsContext(ctx context.Context, clusterName string) (names []string, err error) {
 	if clusterName == "" {
 		err = errRequired("clusterName")
 		return
@@ -1355,7 +1489,7 @@ func (c *Client) GetConnectors(clusterName string) (names []string, err error) {
 	// # List active connectors
 	// GET /api/proxy-connect/(string: clusterName)/connectors
 	path := fmt.Sprintf(connectorsPath, clusterName)
-	resp, respErr := c.do(http.MethodGet, path, contentTypeJSON, nil)
+	resp, respErr := c.do(ctx, "GetConnectors", http.MethodGet, path, contentTypeJSON, nil)
 	if respErr != nil {
 		err = respErr
 		return
@@ -1415,6 +1549,11 @@ func (c *CreateUpdateConnectorPayload) ApplyAndValidateName() error {
 //
 // Look `UpdateConnector` too.
 func (c *Client) CreateConnector(clusterName, name string, config ConnectorConfig) (connector Connector, err error) {
+	return c.CreateConnectorContext(context.Background(), clusterName, name, config)
+}
+
+// CreateConnectorContext same as `CreateConnector` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) CreateConnectorContext(ctx context.Context, clusterName, name string, config ConnectorConfig) (connector Connector, err error) {
 	if clusterName == "" {
 		err = errRequired("clusterName")
 		return
@@ -1436,7 +1575,7 @@ func (c *Client) CreateConnector(clusterName, name string, config ConnectorConfi
 	// # Create new connector
 	// POST /api/proxy-connect/(string: clusterName)/connectors [CONNECTOR_CONFIG]
 	path := fmt.Sprintf(connectorsPath, clusterName)
-	resp, respErr := c.do(http.MethodPost, path, contentTypeJSON, send)
+	resp, respErr := c.do(ctx, "CreateConnector", http.MethodPost, path, contentTypeJSON, send)
 	if respErr != nil {
 		err = respErr
 		return
@@ -1452,6 +1591,11 @@ func (c *Client) CreateConnector(clusterName, name string, config ConnectorConfi
 // It returns information about the connector after the change has been made
 // and an indicator if that connector was created or just configuration update.
 func (c *Client) UpdateConnector(clusterName, name string, config ConnectorConfig) (connector Connector, err error) {
+	return c.UpdateConnectorContext(context.Background(), clusterName, name, config)
+}
+
+// UpdateConnectorContext same as `UpdateConnector` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) UpdateConnectorContext(ctx context.Context, clusterName, name string, config ConnectorConfig) (connector Connector, err error) {
 	if clusterName == "" {
 		err = errRequired("clusterName")
 		return
@@ -1471,7 +1615,7 @@ func (c *Client) UpdateConnector(clusterName, name string, config ConnectorConfi
 	// # Set connector config
 	// PUT /api/proxy-connect/(string: clusterName)/connectors/(string: name)/config
 	path := fmt.Sprintf(connectorPath+"/config", clusterName, name)
-	resp, respErr := c.do(http.MethodPut, path, contentTypeJSON, send)
+	resp, respErr := c.do(ctx, "UpdateConnector", http.MethodPut, path, contentTypeJSON, send)
 	if respErr != nil {
 		err = respErr
 		return
@@ -1488,6 +1632,11 @@ const connectorPath = connectorsPath + "/%s"
 // GetConnector returns the information about the connector.
 // See `Connector` type and read more at: https://docs.confluent.io/current/connect/restapi.html#get--connectors-(string-name)
 func (c *Client) GetConnector(clusterName, name string) (connector Connector, err error) {
+	return c.GetConnectorContext(context.Background(), clusterName, name)
+}
+
+// GetConnectorContext same as `GetConnector` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetConnectorContext(ctx context.Context, clusterName, name string) (connector Connector, err error) {
 	if clusterName == "" {
 		err = errRequired("clusterName")
 		return
@@ -1501,7 +1650,7 @@ func (c *Client) GetConnector(clusterName, name string) (connector Connector, er
 	// # Get information about a specific connector
 	// GET /api/proxy-connect/(string: clusterName)/connectors/(string: name)
 	path := fmt.Sprintf(connectorPath, clusterName, name)
-	resp, respErr := c.do(http.MethodGet, path, contentTypeJSON, nil)
+	resp, respErr := c.do(ctx, "GetConnector", http.MethodGet, path, contentTypeJSON, nil)
 	if respErr != nil {
 		err = respErr
 		return
@@ -1514,6 +1663,11 @@ func (c *Client) GetConnector(clusterName, name string) (connector Connector, er
 
 // GetConnectorConfig returns the configuration for the connector.
 func (c *Client) GetConnectorConfig(clusterName, name string) (cfg ConnectorConfig, err error) {
+	return c.GetConnectorConfigContext(context.Background(), clusterName, name)
+}
+
+// GetConnectorConfigContext same as `GetConnectorConfig` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetConnectorConfigContext(ctx context.Context, clusterName, name string) (cfg ConnectorConfig, err error) {
 	if clusterName == "" {
 		err = errRequired("clusterName")
 		return
@@ -1526,8 +1680,8 @@ func (c *Client) GetConnectorConfig(clusterName, name string) (cfg ConnectorConf
 
 	// # Get connector config
 	// GET /api/proxy-connect/(string: clusterName)/connectors/(string: name)/config
-	path := fmt.Sprintf(connectorPath, clusterName, name)
-	resp, respErr := c.do(http.MethodGet, path, contentTypeJSON, nil)
+	path := fmt.Sprintf(connectorPath+"/config", clusterName, name)
+	resp, respErr := c.do(ctx, "GetConnectorConfig", http.MethodGet, path, contentTypeJSON, nil)
 	if respErr != nil {
 		err = respErr
 		return
@@ -1583,6 +1737,11 @@ type (
 // failed or paused, which worker it is assigned to, error information if it has failed,
 // and the state of all its tasks.
 func (c *Client) GetConnectorStatus(clusterName, name string) (cs ConnectorStatus, err error) {
+	return c.GetConnectorStatusContext(context.Background(), clusterName, name)
+}
+
+// GetConnectorStatusContext same as `GetConnectorStatus` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetConnectorStatusContext(ctx context.Context, clusterName, name string) (cs ConnectorStatus, err error) {
 	if clusterName == "" {
 		err = errRequired("clusterName")
 		return
@@ -1596,7 +1755,7 @@ func (c *Client) GetConnectorStatus(clusterName, name string) (cs ConnectorStatu
 	// # Get connector status
 	// GET /api/proxy-connect/(string: clusterName)/connectors/(string: name)/status
 	path := fmt.Sprintf(connectorPath+"/status", clusterName, name)
-	resp, respErr := c.do(http.MethodGet, path, "", nil)
+	resp, respErr := c.do(ctx, "GetConnectorStatus", http.MethodGet, path, "", nil)
 	if respErr != nil {
 		err = respErr
 		return
@@ -1609,6 +1768,11 @@ func (c *Client) GetConnectorStatus(clusterName, name string) (cs ConnectorStatu
 // PauseConnector pauses the connector and its tasks, which stops message processing until the connector is resumed.
 // This call asynchronous and the tasks will not transition to PAUSED state at the same time.
 func (c *Client) PauseConnector(clusterName, name string) error {
+	return c.PauseConnectorContext(context.Background(), clusterName, name)
+}
+
+// PauseConnectorContext same as `PauseConnector` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) PauseConnectorContext(ctx context.Context, clusterName, name string) error {
 	if clusterName == "" {
 		return errRequired("clusterName")
 	}
@@ -1620,7 +1784,7 @@ func (c *Client) PauseConnector(clusterName, name string) error {
 	// # Pause a connector
 	// PUT /api/proxy-connect/(string: clusterName)/connectors/(string: name)/pause
 	path := fmt.Sprintf(connectorPath+"/pause", clusterName, name)
-	resp, err := c.do(http.MethodPut, path, "", nil) // the success status is 202 Accepted.
+	resp, err := c.do(ctx, "PauseConnector", http.MethodPut, path, "", nil) // the success status is 202 Accepted.
 	if err != nil {
 		return err
 	}
@@ -1631,6 +1795,11 @@ func (c *Client) PauseConnector(clusterName, name string) error {
 // ResumeConnector resumes a paused connector or do nothing if the connector is not paused.
 // This call asynchronous and the tasks will not transition to RUNNING state at the same time.
 func (c *Client) ResumeConnector(clusterName, name string) error {
+	return c.ResumeConnectorContext(context.Background(), clusterName, name)
+}
+
+// ResumeConnectorContext same as `ResumeConnector` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) ResumeConnectorContext(ctx context.Context, clusterName, name string) error {
 	if clusterName == "" {
 		return errRequired("clusterName")
 	}
@@ -1642,7 +1811,7 @@ func (c *Client) ResumeConnector(clusterName, name string) error {
 	// # Resume a paused connector
 	// PUT /api/proxy-connect/(string: clusterName)/connectors/(string: name)/resume
 	path := fmt.Sprintf(connectorPath+"/resume", clusterName, name)
-	resp, err := c.do(http.MethodPut, path, "", nil)
+	resp, err := c.do(ctx, "ResumeConnector", http.MethodPut, path, "", nil)
 	if err != nil {
 		return err
 	}
@@ -1653,6 +1822,11 @@ func (c *Client) ResumeConnector(clusterName, name string) error {
 // RestartConnector restarts the connector and its tasks.
 // It returns a 409 (Conflict) status code error if rebalance is in process.
 func (c *Client) RestartConnector(clusterName, name string) error {
+	return c.RestartConnectorContext(context.Background(), clusterName, name)
+}
+
+// RestartConnectorContext same as `RestartConnector` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) RestartConnectorContext(ctx context.Context, clusterName, name string) error {
 	if clusterName == "" {
 		return errRequired("clusterName")
 	}
@@ -1664,7 +1838,7 @@ func (c *Client) RestartConnector(clusterName, name string) error {
 	// # Restart a connector
 	// POST /api/proxy-connect/(string: clusterName)/connectors/(string: name)/restart
 	path := fmt.Sprintf(connectorPath+"/restart", clusterName, name)
-	resp, err := c.do(http.MethodPost, path, "", nil)
+	resp, err := c.do(ctx, "RestartConnector", http.MethodPost, path, "", nil)
 	if err != nil {
 		return err
 	}
@@ -1675,6 +1849,11 @@ func (c *Client) RestartConnector(clusterName, name string) error {
 // DeleteConnector deletes a connector, halting all tasks and deleting its configuration.
 // It return a 409 (Conflict) status code error if rebalance is in process.
 func (c *Client) DeleteConnector(clusterName, name string) error {
+	return c.DeleteConnectorContext(context.Background(), clusterName, name)
+}
+
+// DeleteConnectorContext same as `DeleteConnector` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) DeleteConnectorContext(ctx context.Context, clusterName, name string) error {
 	if clusterName == "" {
 		return errRequired("clusterName")
 	}
@@ -1686,7 +1865,7 @@ func (c *Client) DeleteConnector(clusterName, name string) error {
 	// # Remove a running connector
 	// DELETE /api/proxy-connect/(string: clusterName)/connectors/(string: name)
 	path := fmt.Sprintf(connectorPath, clusterName, name)
-	resp, err := c.do(http.MethodDelete, path, "", nil)
+	resp, err := c.do(ctx, "DeleteConnector", http.MethodDelete, path, "", nil)
 	if err != nil {
 		return err
 	}
@@ -1702,6 +1881,11 @@ const (
 // GetConnectorTasks returns a list of tasks currently running for the connector.
 // Read more at: https://docs.confluent.io/current/connect/restapi.html#get--connectors-(string-name)-tasks.
 func (c *Client) GetConnectorTasks(clusterName, name string) (m []map[string]interface{}, err error) {
+	return c.GetConnectorTasksContext(context.Background(), clusterName, name)
+}
+
+// GetConnectorTasksContext same as `GetConnectorTasks` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetConnectorTasksContext(ctx context.Context, clusterName, name string) (m []map[string]interface{}, err error) {
 	if clusterName == "" {
 		return nil, errRequired("clusterName")
 	}
@@ -1713,7 +1897,7 @@ func (c *Client) GetConnectorTasks(clusterName, name string) (m []map[string]int
 	// # Get list of connector tasks
 	// GET /api/proxy-connect/(string: clusterName)/connectors/(string: name)/tasks
 	path := fmt.Sprintf(tasksPath, clusterName, name)
-	resp, respErr := c.do(http.MethodGet, path, "", nil)
+	resp, respErr := c.do(ctx, "GetConnectorTasks", http.MethodGet, path, "", nil)
 	if respErr != nil {
 		err = respErr
 		return
@@ -1725,6 +1909,11 @@ func (c *Client) GetConnectorTasks(clusterName, name string) (m []map[string]int
 
 // GetConnectorTaskStatus returns a task’s status.
 func (c *Client) GetConnectorTaskStatus(clusterName, name string, taskID int) (cst ConnectorStatusTask, err error) {
+	return c.GetConnectorTaskStatusContext(context.Background(), clusterName, name, taskID)
+}
+
+// GetConnectorTaskStatusContext same as `GetConnectorTaskStatus` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetConnectorTaskStatusContext(ctx context.Context, clusterName, name string, taskID int) (cst ConnectorStatusTask, err error) {
 	if clusterName == "" {
 		err = errRequired("clusterName")
 		return
@@ -1738,7 +1927,7 @@ func (c *Client) GetConnectorTaskStatus(clusterName, name string, taskID int) (c
 	// # Get current status of a task
 	// GET /connectors/(string: name)/tasks/(int: taskid)/status in confluent
 	path := fmt.Sprintf(taskPath+"/status", clusterName, name, taskID)
-	resp, respErr := c.do(http.MethodGet, path, "", nil)
+	resp, respErr := c.do(ctx, "GetConnectorTaskStatus", http.MethodGet, path, "", nil)
 	if respErr != nil {
 		err = respErr
 		return
@@ -1750,6 +1939,11 @@ func (c *Client) GetConnectorTaskStatus(clusterName, name string, taskID int) (c
 
 // RestartConnectorTask restarts an individual task.
 func (c *Client) RestartConnectorTask(clusterName, name string, taskID int) error {
+	return c.RestartConnectorTaskContext(context.Background(), clusterName, name, taskID)
+}
+
+// RestartConnectorTaskContext same as `RestartConnectorTask` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) RestartConnectorTaskContext(ctx context.Context, clusterName, name string, taskID int) error {
 	if clusterName == "" {
 		return errRequired("clusterName")
 	}
@@ -1761,7 +1955,7 @@ func (c *Client) RestartConnectorTask(clusterName, name string, taskID int) erro
 	// # Restart a connector task
 	// POST /api/proxy-connect/(string: clusterName)/connectors/(string: name)/tasks/(int: taskid)/restart
 	path := fmt.Sprintf(taskPath+"/restart", clusterName, name, taskID)
-	resp, err := c.do(http.MethodPost, path, "", nil)
+	resp, err := c.do(ctx, "RestartConnectorTask", http.MethodPost, path, "", nil)
 	if err != nil {
 		return err
 	}
@@ -1786,6 +1980,11 @@ const pluginsPath = "/api/proxy-connect/%s/connector-plugins"
 // which means it is possible to see inconsistent results,
 // especially during a rolling upgrade if you add new connector jars.
 func (c *Client) GetConnectorPlugins(clusterName string) (cp []ConnectorPlugin, err error) {
+	return c.GetConnectorPluginsContext(context.Background(), clusterName)
+}
+
+// GetConnectorPluginsContext same as `GetConnectorPlugins` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetConnectorPluginsContext(ctx context.Context, clusterName string) (cp []ConnectorPlugin, err error) {
 	if clusterName == "" {
 		return nil, errRequired("clusterName")
 	}
@@ -1793,7 +1992,7 @@ func (c *Client) GetConnectorPlugins(clusterName string) (cp []ConnectorPlugin,
 	// # List available connector plugins
 	// GET /api/proxy-connect/(string: clusterName)/connector-plugins
 	path := fmt.Sprintf(pluginsPath, clusterName)
-	resp, respErr := c.do(http.MethodGet, path, "", nil)
+	resp, respErr := c.do(ctx, "GetConnectorPlugins", http.MethodGet, path, "", nil)
 	if respErr != nil {
 		err = respErr
 		return
@@ -1814,9 +2013,14 @@ const subjectsPath = "api/proxy-sr/subjects"
 // GetSubjects returns a list of the available subjects(schemas).
 // https://docs.confluent.io/current/schema-registry/docs/api.html#subjects
 func (c *Client) GetSubjects() (subjects []string, err error) {
+	return c.GetSubjectsContext(context.Background())
+}
+
+// GetSubjectsContext same as `GetSubjects` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetSubjectsContext(ctx context.Context) (subjects []string, err error) {
 	// # List all available subjects
 	// GET /api/proxy-sr/subjects
-	resp, respErr := c.do(http.MethodGet, subjectsPath, "", nil, schemaAPIOption)
+	resp, respErr := c.do(ctx, "GetSubjects", http.MethodGet, subjectsPath, "", nil, schemaAPIOption)
 	if respErr != nil {
 		err = respErr
 		return
@@ -1830,6 +2034,11 @@ const subjectPath = subjectsPath + "/%s"
 
 // GetSubjectVersions returns all the versions of a subject(schema) based on its name.
 func (c *Client) GetSubjectVersions(subject string) (versions []int, err error) {
+	return c.GetSubjectVersionsContext(context.Background(), subject)
+}
+
+// GetSubjectVersionsContext same as `GetSubjectVersions` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetSubjectVersionsContext(ctx context.Context, subject string) (versions []int, err error) {
 	if subject == "" {
 		err = errRequired("subject")
 		return
@@ -1838,7 +2047,7 @@ func (c *Client) GetSubjectVersions(subject string) (versions []int, err error)
 	// # List all versions of a particular subject
 	// GET /api/proxy-sr/subjects/(string: subject)/versions
 	path := fmt.Sprintf(subjectPath, subject+"/versions")
-	resp, respErr := c.do(http.MethodGet, path, "", nil, schemaAPIOption)
+	resp, respErr := c.do(ctx, "GetSubjectVersions", http.MethodGet, path, "", nil, schemaAPIOption)
 	if respErr != nil {
 		err = respErr
 		return
@@ -1852,6 +2061,11 @@ func (c *Client) GetSubjectVersions(subject string) (versions []int, err error)
 // It is recommended to use this API only when a topic needs to be recycled or in development environment.
 // Returns the versions of the schema deleted under this subject.
 func (c *Client) DeleteSubject(subject string) (versions []int, err error) {
+	return c.DeleteSubjectContext(context.Background(), subject)
+}
+
+// DeleteSubjectContext same as `DeleteSubject` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) DeleteSubjectContext(ctx context.Context, subject string) (versions []int, err error) {
 	if subject == "" {
 		err = errRequired("subject")
 		return
@@ -1859,7 +2073,7 @@ func (c *Client) DeleteSubject(subject string) (versions []int, err error) {
 
 	// DELETE /api/proxy-sr/subjects/(string: subject)
 	path := fmt.Sprintf(subjectPath, subject)
-	resp, respErr := c.do(http.MethodDelete, path, "", nil, schemaAPIOption)
+	resp, respErr := c.do(ctx, "DeleteSubject", http.MethodDelete, path, "", nil, schemaAPIOption)
 	if respErr != nil {
 		err = respErr
 		return
@@ -1878,10 +2092,15 @@ const schemaPath = "api/proxy-sr/schemas/ids/%d"
 // GetSchema returns the Auro schema string identified by the id.
 // id (int) – the globally unique identifier of the schema.
 func (c *Client) GetSchema(subjectID int) (string, error) {
+	return c.GetSchemaContext(context.Background(), subjectID)
+}
+
+// GetSchemaContext same as `GetSchema` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetSchemaContext(ctx context.Context, subjectID int) (string, error) {
 	// # Get the schema for a particular subject id
 	// GET /api/proxy-sr/schemas/ids/{int: id}
 	path := fmt.Sprintf(schemaPath, subjectID)
-	resp, err := c.do(http.MethodGet, path, "", nil, schemaAPIOption)
+	resp, err := c.do(ctx, "GetSchema", http.MethodGet, path, "", nil, schemaAPIOption)
 	if err != nil {
 		return "", err
 	}
@@ -1949,7 +2168,7 @@ func checkSchemaVersionID(versionID interface{}) error {
 // the version as integer and it will retrieve by a specific version.
 //
 // See `GetLatestSchema` and `GetSchemaAtVersion` instead.
-func (c *Client) getSubjectSchemaAtVersion(subject string, versionID interface{}) (s Schema, err error) {
+func (c *Client) getSubjectSchemaAtVersion(ctx context.Context, op, subject string, versionID interface{}) (s Schema, err error) {
 	if subject == "" {
 		err = errRequired("subject")
 		return
@@ -1962,7 +2181,7 @@ func (c *Client) getSubjectSchemaAtVersion(subject string, versionID interface{}
 	// # Get the schema at a particular version
 	// GET /api/proxy-sr/subjects/(string: subject)/versions/(versionId: "latest" | int)
 	path := fmt.Sprintf(subjectPath+"/versions/%v", subject, versionID)
-	resp, respErr := c.do(http.MethodGet, path, "", nil, schemaAPIOption)
+	resp, respErr := c.do(ctx, op, http.MethodGet, path, "", nil, schemaAPIOption)
 	if respErr != nil {
 		err = respErr
 		return
@@ -1975,13 +2194,23 @@ func (c *Client) getSubjectSchemaAtVersion(subject string, versionID interface{}
 // GetLatestSchema returns the latest version of a schema.
 // See `GetSchemaAtVersion` to retrieve a subject schema by a specific version.
 func (c *Client) GetLatestSchema(subject string) (Schema, error) {
-	return c.getSubjectSchemaAtVersion(subject, SchemaLatestVersion)
+	return c.GetLatestSchemaContext(context.Background(), subject)
+}
+
+// GetLatestSchemaContext same as `GetLatestSchema` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetLatestSchemaContext(ctx context.Context, subject string) (Schema, error) {
+	return c.getSubjectSchemaAtVersion(ctx, "GetLatestSchema", subject, SchemaLatestVersion)
 }
 
 // GetSchemaAtVersion returns a specific version of a schema.
 // See `GetLatestSchema` to retrieve the latest schema.
 func (c *Client) GetSchemaAtVersion(subject string, versionID int) (Schema, error) {
-	return c.getSubjectSchemaAtVersion(subject, versionID)
+	return c.GetSchemaAtVersionContext(context.Background(), subject, versionID)
+}
+
+// GetSchemaAtVersionContext same as `GetSchemaAtVersion` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetSchemaAtVersionContext(ctx context.Context, subject string, versionID int) (Schema, error) {
+	return c.getSubjectSchemaAtVersion(ctx, "GetSchemaAtVersion", subject, versionID)
 }
 
 type idOnlyJSON struct {
@@ -1993,6 +2222,11 @@ type idOnlyJSON struct {
 // this schema from the schemas resource and is different from
 // the schema’s version which is associated with that name.
 func (c *Client) RegisterSchema(subject string, avroSchema string) (int, error) {
+	return c.RegisterSchemaContext(context.Background(), subject, avroSchema)
+}
+
+// RegisterSchemaContext same as `RegisterSchema` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) RegisterSchemaContext(ctx context.Context, subject string, avroSchema string) (int, error) {
 	if subject == "" {
 		return 0, errRequired("subject")
 	}
@@ -2013,7 +2247,7 @@ func (c *Client) RegisterSchema(subject string, avroSchema string) (int, error)
 	// POST /api/proxy-sr/subjects/(string: subject)/versions
 
 	path := fmt.Sprintf(subjectPath+"/versions", subject)
-	resp, err := c.do(http.MethodPost, path, contentTypeSchemaJSON, send, schemaAPIOption)
+	resp, err := c.do(ctx, "RegisterSchema", http.MethodPost, path, contentTypeSchemaJSON, send, schemaAPIOption)
 	if err != nil {
 		return 0, err
 	}
@@ -2025,7 +2259,7 @@ func (c *Client) RegisterSchema(subject string, avroSchema string) (int, error)
 
 // deleteSubjectSchemaVersion deletes a specific version of the schema registered under this subject.
 // It's being used in `DeleteSchemaVersion` and `DeleteLatestSchemaVersion`.
-func (c *Client) deleteSubjectSchemaVersion(subject string, versionID interface{}) (int, error) {
+func (c *Client) deleteSubjectSchemaVersion(ctx context.Context, op, subject string, versionID interface{}) (int, error) {
 	if subject == "" {
 		return 0, errRequired("subject")
 	}
@@ -2037,7 +2271,7 @@ func (c *Client) deleteSubjectSchemaVersion(subject string, versionID interface{
 	// # Delete a particular version of a subject
 	// DELETE /api/proxy-sr/subjects/(string: subject)/versions/(versionId: version)
 	path := fmt.Sprintf(subjectPath+"/versions/%v", subject, versionID)
-	resp, err := c.do(http.MethodDelete, path, contentTypeSchemaJSON, nil, schemaAPIOption)
+	resp, err := c.do(ctx, op, http.MethodDelete, path, contentTypeSchemaJSON, nil, schemaAPIOption)
 	if err != nil {
 		return 0, err
 	}
@@ -2061,7 +2295,12 @@ func (c *Client) deleteSubjectSchemaVersion(subject string, versionID interface{
 //
 // See `DeleteLatestSubjectVersion` too.
 func (c *Client) DeleteSubjectVersion(subject string, versionID int) (int, error) {
-	return c.deleteSubjectSchemaVersion(subject, versionID)
+	return c.DeleteSubjectVersionContext(context.Background(), subject, versionID)
+}
+
+// DeleteSubjectVersionContext same as `DeleteSubjectVersion` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) DeleteSubjectVersionContext(ctx context.Context, subject string, versionID int) (int, error) {
+	return c.deleteSubjectSchemaVersion(ctx, "DeleteSubjectVersion", subject, versionID)
 }
 
 // DeleteLatestSubjectVersion deletes the latest version of the schema registered under this subject.
@@ -2075,7 +2314,12 @@ func (c *Client) DeleteSubjectVersion(subject string, versionID int) (int, error
 //
 // See `DeleteSubjectVersion` too.
 func (c *Client) DeleteLatestSubjectVersion(subject string) (int, error) {
-	return c.deleteSubjectSchemaVersion(subject, SchemaLatestVersion)
+	return c.DeleteLatestSubjectVersionContext(context.Background(), subject)
+}
+
+// DeleteLatestSubjectVersionContext same as `DeleteLatestSubjectVersion` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) DeleteLatestSubjectVersionContext(ctx context.Context, subject string) (int, error) {
+	return c.deleteSubjectSchemaVersion(ctx, "DeleteLatestSubjectVersion", subject, SchemaLatestVersion)
 }
 
 // CompatibilityLevel describes the valid compatibility levels' type, it's just a string.
@@ -2148,6 +2392,11 @@ const compatibilityLevelPath = "/api/proxy-sr/config"
 // If the master is not available, the client will get an error code indicating
 // that the forwarding has failed.
 func (c *Client) UpdateGlobalCompatibilityLevel(level CompatibilityLevel) error {
+	return c.UpdateGlobalCompatibilityLevelContext(context.Background(), level)
+}
+
+// UpdateGlobalCompatibilityLevelContext same as `UpdateGlobalCompatibilityLevel` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) UpdateGlobalCompatibilityLevelContext(ctx context.Context, level CompatibilityLevel) error {
 	lv := compatibilityPutOnlyJSON{
 		Compatibility: string(level),
 	}
@@ -2159,7 +2408,7 @@ func (c *Client) UpdateGlobalCompatibilityLevel(level CompatibilityLevel) error
 
 	// # Update global compatibility level
 	// PUT /api/proxy-sr/config
-	resp, err := c.do(http.MethodPut, compatibilityLevelPath, contentTypeSchemaJSON, send, schemaAPIOption)
+	resp, err := c.do(ctx, "UpdateGlobalCompatibilityLevel", http.MethodPut, compatibilityLevelPath, contentTypeSchemaJSON, send, schemaAPIOption)
 	if err != nil {
 		return err
 	}
@@ -2170,9 +2419,14 @@ func (c *Client) UpdateGlobalCompatibilityLevel(level CompatibilityLevel) error
 // GetGlobalCompatibilityLevel returns the global compatibility level,
 // "NONE", "FULL", "FORWARD" or "BACKWARD", as described at the `CompatibilityLevel` type.
 func (c *Client) GetGlobalCompatibilityLevel() (level CompatibilityLevel, err error) {
+	return c.GetGlobalCompatibilityLevelContext(context.Background())
+}
+
+// GetGlobalCompatibilityLevelContext same as `GetGlobalCompatibilityLevel` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetGlobalCompatibilityLevelContext(ctx context.Context) (level CompatibilityLevel, err error) {
 	// # Get global compatibility level
 	// GET /api/proxy-sr/config
-	resp, respErr := c.do(http.MethodGet, compatibilityLevelPath, "", nil, schemaAPIOption)
+	resp, respErr := c.do(ctx, "GetGlobalCompatibilityLevel", http.MethodGet, compatibilityLevelPath, "", nil, schemaAPIOption)
 	if respErr != nil {
 		err = respErr
 		return
@@ -2188,6 +2442,11 @@ const subjectCompatibilityLevelPath = compatibilityLevelPath + "/%s"
 
 // UpdateSubjectCompatibilityLevel modifies a specific subject(schema)'s compatibility level.
 func (c *Client) UpdateSubjectCompatibilityLevel(subject string, level CompatibilityLevel) error {
+	return c.UpdateSubjectCompatibilityLevelContext(context.Background(), subject, level)
+}
+
+// UpdateSubjectCompatibilityLevelContext same as `UpdateSubjectCompatibilityLevel` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) UpdateSubjectCompatibilityLevelContext(ctx context.Context, subject string, level CompatibilityLevel) error {
 	if subject == "" {
 		return errRequired("subject")
 	}
@@ -2208,7 +2467,7 @@ func (c *Client) UpdateSubjectCompatibilityLevel(subject string, level Compatibi
 	// # Change compatibility level of a subject
 	// PUT /api/proxy-sr/config/(string: subject)
 	path := fmt.Sprintf(subjectCompatibilityLevelPath, subject)
-	resp, err := c.do(http.MethodPut, path, contentTypeSchemaJSON, send, schemaAPIOption)
+	resp, err := c.do(ctx, "UpdateSubjectCompatibilityLevel", http.MethodPut, path, contentTypeSchemaJSON, send, schemaAPIOption)
 	if err != nil {
 		return err
 	}
@@ -2218,6 +2477,11 @@ func (c *Client) UpdateSubjectCompatibilityLevel(subject string, level Compatibi
 
 // GetSubjectCompatibilityLevel returns the compatibility level of a specific subject(schema) name.
 func (c *Client) GetSubjectCompatibilityLevel(subject string) (level CompatibilityLevel, err error) {
+	return c.GetSubjectCompatibilityLevelContext(context.Background(), subject)
+}
+
+// GetSubjectCompatibilityLevelContext same as `GetSubjectCompatibilityLevel` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetSubjectCompatibilityLevelContext(ctx context.Context, subject string) (level CompatibilityLevel, err error) {
 	if subject == "" {
 		err = errRequired("subject")
 		return
@@ -2226,7 +2490,7 @@ func (c *Client) GetSubjectCompatibilityLevel(subject string) (level Compatibili
 	// # Get compatibility level of a subject
 	// GET /api/proxy-sr/config/(string: subject)
 	path := fmt.Sprintf(subjectCompatibilityLevelPath, subject)
-	resp, respErr := c.do(http.MethodGet, path, "", nil, schemaAPIOption)
+	resp, respErr := c.do(ctx, "GetSubjectCompatibilityLevel", http.MethodGet, path, "", nil, schemaAPIOption)
 	if respErr != nil {
 		err = respErr
 		return
@@ -2368,6 +2632,11 @@ const aclPath = "/api/acl"
 //
 // Note that on the "host" input argument you should use IP addresses as domain names are not supported at the moment by Apache Kafka.
 func (c *Client) CreateOrUpdateACL(acl ACL) error {
+	return c.CreateOrUpdateACLContext(context.Background(), acl)
+}
+
+// CreateOrUpdateACLContext same as `CreateOrUpdateACL` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) CreateOrUpdateACLContext(ctx context.Context, acl ACL) error {
 	if err := acl.Validate(); err != nil {
 		return err
 	}
@@ -2377,7 +2646,7 @@ func (c *Client) CreateOrUpdateACL(acl ACL) error {
 		return err
 	}
 
-	resp, err := c.do(http.MethodPut, aclPath, contentTypeJSON, send)
+	resp, err := c.do(ctx, "CreateOrUpdateACL", http.MethodPut, aclPath, contentTypeJSON, send)
 	if err != nil {
 		return err
 	}
@@ -2388,7 +2657,12 @@ func (c *Client) CreateOrUpdateACL(acl ACL) error {
 
 // GetACLs returns all the available Apache Kafka Access Control Lists.
 func (c *Client) GetACLs() ([]ACL, error) {
-	resp, err := c.do(http.MethodGet, aclPath, "", nil)
+	return c.GetACLsContext(context.Background())
+}
+
+// GetACLsContext same as `GetACLs` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetACLsContext(ctx context.Context) ([]ACL, error) {
+	resp, err := c.do(ctx, "GetACLs", http.MethodGet, aclPath, "", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2400,6 +2674,11 @@ func (c *Client) GetACLs() ([]ACL, error) {
 
 // DeleteACL deletes an existing Apache Kafka Access Control List.
 func (c *Client) DeleteACL(acl ACL) error {
+	return c.DeleteACLContext(context.Background(), acl)
+}
+
+// DeleteACLContext same as `DeleteACL` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) DeleteACLContext(ctx context.Context, acl ACL) error {
 	if err := acl.Validate(); err != nil {
 		return err
 	}
@@ -2409,7 +2688,7 @@ func (c *Client) DeleteACL(acl ACL) error {
 		return err
 	}
 
-	resp, err := c.do(http.MethodDelete, aclPath, contentTypeJSON, send)
+	resp, err := c.do(ctx, "DeleteACL", http.MethodDelete, aclPath, contentTypeJSON, send)
 	if err != nil {
 		return err
 	}
@@ -2472,7 +2751,12 @@ const quotasPath = "/api/quotas"
 
 // GetQuotas returns a list of all available quotas.
 func (c *Client) GetQuotas() ([]Quota, error) {
-	resp, err := c.do(http.MethodGet, quotasPath, "", nil)
+	return c.GetQuotasContext(context.Background())
+}
+
+// GetQuotasContext same as `GetQuotas` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetQuotasContext(ctx context.Context) ([]Quota, error) {
+	resp, err := c.do(ctx, "GetQuotas", http.MethodGet, quotasPath, "", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2488,12 +2772,17 @@ const quotasPathAllUsers = quotasPath + "/users"
 // CreateOrUpdateQuotaForAllUsers sets the default quota for all users.
 // Read more at: http://lenses.stream/using-lenses/user-guide/quotas.html.
 func (c *Client) CreateOrUpdateQuotaForAllUsers(config QuotaConfig) error {
+	return c.CreateOrUpdateQuotaForAllUsersContext(context.Background(), config)
+}
+
+// CreateOrUpdateQuotaForAllUsersContext same as `CreateOrUpdateQuotaForAllUsers` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) CreateOrUpdateQuotaForAllUsersContext(ctx context.Context, config QuotaConfig) error {
 	send, err := json.Marshal(config)
 	if err != nil {
 		return err
 	}
 
-	resp, err := c.do(http.MethodPut, quotasPathAllUsers, contentTypeJSON, send)
+	resp, err := c.do(ctx, "CreateOrUpdateQuotaForAllUsers", http.MethodPut, quotasPathAllUsers, contentTypeJSON, send)
 	if err != nil {
 		return err
 	}
@@ -2506,12 +2795,17 @@ func (c *Client) CreateOrUpdateQuotaForAllUsers(config QuotaConfig) error {
 //
 // if "propertiesToRemove" is not passed or empty then the client will send all the available keys to be removed, see `DefaultQuotaConfigPropertiesToRemove` for more.
 func (c *Client) DeleteQuotaForAllUsers(propertiesToRemove ...string) error {
+	return c.DeleteQuotaForAllUsersContext(context.Background(), propertiesToRemove...)
+}
+
+// DeleteQuotaForAllUsersContext same as `DeleteQuotaForAllUsers` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) DeleteQuotaForAllUsersContext(ctx context.Context, propertiesToRemove ...string) error {
 	send, err := marshalQuotaConfigPropertiesToBeRemoved(propertiesToRemove)
 	if err != nil {
 		return err
 	}
 
-	resp, err := c.do(http.MethodDelete, quotasPathAllUsers, contentTypeJSON, send)
+	resp, err := c.do(ctx, "DeleteQuotaForAllUsers", http.MethodDelete, quotasPathAllUsers, contentTypeJSON, send)
 	if err != nil {
 		return err
 	}
@@ -2525,13 +2819,18 @@ const quotasPathUser = quotasPathAllUsers + "/%s"
 // CreateOrUpdateQuotaForUser sets a quota for a user.
 // Read more at: http://lenses.stream/using-lenses/user-guide/quotas.html.
 func (c *Client) CreateOrUpdateQuotaForUser(user string, config QuotaConfig) error {
+	return c.CreateOrUpdateQuotaForUserContext(context.Background(), user, config)
+}
+
+// CreateOrUpdateQuotaForUserContext same as `CreateOrUpdateQuotaForUser` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) CreateOrUpdateQuotaForUserContext(ctx context.Context, user string, config QuotaConfig) error {
 	send, err := json.Marshal(config)
 	if err != nil {
 		return err
 	}
 
 	path := fmt.Sprintf(quotasPathUser, user)
-	resp, err := c.do(http.MethodPut, path, contentTypeJSON, send)
+	resp, err := c.do(ctx, "CreateOrUpdateQuotaForUser", http.MethodPut, path, contentTypeJSON, send)
 	if err != nil {
 		return err
 	}
@@ -2542,13 +2841,18 @@ func (c *Client) CreateOrUpdateQuotaForUser(user string, config QuotaConfig) err
 // DeleteQuotaForUser deletes a quota for a user.
 // if "propertiesToRemove" is not passed or empty then the client will send all the available keys to be removed, see `DefaultQuotaConfigPropertiesToRemove` for more.
 func (c *Client) DeleteQuotaForUser(user string, propertiesToRemove ...string) error {
+	return c.DeleteQuotaForUserContext(context.Background(), user, propertiesToRemove...)
+}
+
+// DeleteQuotaForUserContext same as `DeleteQuotaForUser` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) DeleteQuotaForUserContext(ctx context.Context, user string, propertiesToRemove ...string) error {
 	send, err := marshalQuotaConfigPropertiesToBeRemoved(propertiesToRemove)
 	if err != nil {
 		return err
 	}
 
 	path := fmt.Sprintf(quotasPathUser, user)
-	resp, err := c.do(http.MethodDelete, path, contentTypeJSON, send)
+	resp, err := c.do(ctx, "DeleteQuotaForUser", http.MethodDelete, path, contentTypeJSON, send)
 	if err != nil {
 		return err
 	}
@@ -2562,13 +2866,18 @@ const quotasPathUserAllClients = quotasPathUser + "/clients"
 // CreateOrUpdateQuotaForUserAllClients sets a quota for a user for all clients.
 // Read more at: http://lenses.stream/using-lenses/user-guide/quotas.html.
 func (c *Client) CreateOrUpdateQuotaForUserAllClients(user string, config QuotaConfig) error {
+	return c.CreateOrUpdateQuotaForUserAllClientsContext(context.Background(), user, config)
+}
+
+// CreateOrUpdateQuotaForUserAllClientsContext same as `CreateOrUpdateQuotaForUserAllClients` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) CreateOrUpdateQuotaForUserAllClientsContext(ctx context.Context, user string, config QuotaConfig) error {
 	send, err := json.Marshal(config)
 	if err != nil {
 		return err
 	}
 
 	path := fmt.Sprintf(quotasPathUserAllClients, user)
-	resp, err := c.do(http.MethodPut, path, contentTypeJSON, send)
+	resp, err := c.do(ctx, "CreateOrUpdateQuotaForUserAllClients", http.MethodPut, path, contentTypeJSON, send)
 	if err != nil {
 		return err
 	}
@@ -2580,13 +2889,18 @@ func (c *Client) CreateOrUpdateQuotaForUserAllClients(user string, config QuotaC
 //
 // if "propertiesToRemove" is not passed or empty then the client will send all the available keys to be removed, see `DefaultQuotaConfigPropertiesToRemove` for more.
 func (c *Client) DeleteQuotaForUserAllClients(user string, propertiesToRemove ...string) error {
+	return c.DeleteQuotaForUserAllClientsContext(context.Background(), user, propertiesToRemove...)
+}
+
+// DeleteQuotaForUserAllClientsContext same as `DeleteQuotaForUserAllClients` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) DeleteQuotaForUserAllClientsContext(ctx context.Context, user string, propertiesToRemove ...string) error {
 	send, err := marshalQuotaConfigPropertiesToBeRemoved(propertiesToRemove)
 	if err != nil {
 		return err
 	}
 
 	path := fmt.Sprintf(quotasPathUserAllClients, user)
-	resp, err := c.do(http.MethodDelete, path, contentTypeJSON, send)
+	resp, err := c.do(ctx, "DeleteQuotaForUserAllClients", http.MethodDelete, path, contentTypeJSON, send)
 	if err != nil {
 		return err
 	}
@@ -2600,13 +2914,18 @@ const quotasPathUserClient = quotasPathUserAllClients + "/%s"
 // CreateOrUpdateQuotaForUserClient sets the quota for a user/client pair.
 // Read more at: http://lenses.stream/using-lenses/user-guide/quotas.html.
 func (c *Client) CreateOrUpdateQuotaForUserClient(user, clientID string, config QuotaConfig) error {
+	return c.CreateOrUpdateQuotaForUserClientContext(context.Background(), user, clientID, config)
+}
+
+// CreateOrUpdateQuotaForUserClientContext same as `CreateOrUpdateQuotaForUserClient` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) CreateOrUpdateQuotaForUserClientContext(ctx context.Context, user, clientID string, config QuotaConfig) error {
 	send, err := json.Marshal(config)
 	if err != nil {
 		return err
 	}
 
 	path := fmt.Sprintf(quotasPathUserClient, user, clientID)
-	resp, err := c.do(http.MethodPut, path, contentTypeJSON, send)
+	resp, err := c.do(ctx, "CreateOrUpdateQuotaForUserClient", http.MethodPut, path, contentTypeJSON, send)
 	if err != nil {
 		return err
 	}
@@ -2642,13 +2961,18 @@ func marshalQuotaConfigPropertiesToBeRemoved(propertiesToRemove []string) ([]byt
 //
 // if "propertiesToRemove" is not passed or empty then the client will send all the available keys to be removed, see `DefaultQuotaConfigPropertiesToRemove` for more.
 func (c *Client) DeleteQuotaForUserClient(user, clientID string, propertiesToRemove ...string) error {
+	return c.DeleteQuotaForUserClientContext(context.Background(), user, clientID, propertiesToRemove...)
+}
+
+// DeleteQuotaForUserClientContext same as `DeleteQuotaForUserClient` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) DeleteQuotaForUserClientContext(ctx context.Context, user, clientID string, propertiesToRemove ...string) error {
 	send, err := marshalQuotaConfigPropertiesToBeRemoved(propertiesToRemove)
 	if err != nil {
 		return err
 	}
 
 	path := fmt.Sprintf(quotasPathUserClient, user, clientID)
-	resp, err := c.do(http.MethodDelete, path, contentTypeJSON, send)
+	resp, err := c.do(ctx, "DeleteQuotaForUserClient", http.MethodDelete, path, contentTypeJSON, send)
 	if err != nil {
 		return err
 	}
@@ -2662,12 +2986,17 @@ const quotasPathAllClients = quotasPath + "/clients"
 // CreateOrUpdateQuotaForAllClients sets the default quota for all clients.
 // Read more at: http://lenses.stream/using-lenses/user-guide/quotas.html.
 func (c *Client) CreateOrUpdateQuotaForAllClients(config QuotaConfig) error {
+	return c.CreateOrUpdateQuotaForAllClientsContext(context.Background(), config)
+}
+
+// CreateOrUpdateQuotaForAllClientsContext same as `CreateOrUpdateQuotaForAllClients` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) CreateOrUpdateQuotaForAllClientsContext(ctx context.Context, config QuotaConfig) error {
 	send, err := json.Marshal(config)
 	if err != nil {
 		return err
 	}
 
-	resp, err := c.do(http.MethodPut, quotasPathAllClients, contentTypeJSON, send)
+	resp, err := c.do(ctx, "CreateOrUpdateQuotaForAllClients", http.MethodPut, quotasPathAllClients, contentTypeJSON, send)
 	if err != nil {
 		return err
 	}
@@ -2679,12 +3008,17 @@ func (c *Client) CreateOrUpdateQuotaForAllClients(config QuotaConfig) error {
 //
 // if "propertiesToRemove" is not passed or empty then the client will send all the available keys to be removed, see `DefaultQuotaConfigPropertiesToRemove` for more.
 func (c *Client) DeleteQuotaForAllClients(propertiesToRemove ...string) error {
+	return c.DeleteQuotaForAllClientsContext(context.Background(), propertiesToRemove...)
+}
+
+// DeleteQuotaForAllClientsContext same as `DeleteQuotaForAllClients` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) DeleteQuotaForAllClientsContext(ctx context.Context, propertiesToRemove ...string) error {
 	send, err := marshalQuotaConfigPropertiesToBeRemoved(propertiesToRemove)
 	if err != nil {
 		return err
 	}
 
-	resp, err := c.do(http.MethodDelete, quotasPathAllClients, contentTypeJSON, send)
+	resp, err := c.do(ctx, "DeleteQuotaForAllClients", http.MethodDelete, quotasPathAllClients, contentTypeJSON, send)
 	if err != nil {
 		return err
 	}
@@ -2698,13 +3032,18 @@ const quotasPathClient = quotasPathAllClients + "/%s"
 // CreateOrUpdateQuotaForClient sets the quota for a specific client.
 // Read more at: http://lenses.stream/using-lenses/user-guide/quotas.html.
 func (c *Client) CreateOrUpdateQuotaForClient(clientID string, config QuotaConfig) error {
+	return c.CreateOrUpdateQuotaForClientContext(context.Background(), clientID, config)
+}
+
+// CreateOrUpdateQuotaForClientContext same as `CreateOrUpdateQuotaForClient` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) CreateOrUpdateQuotaForClientContext(ctx context.Context, clientID string, config QuotaConfig) error {
 	send, err := json.Marshal(config)
 	if err != nil {
 		return err
 	}
 
 	path := fmt.Sprintf(quotasPathClient, clientID)
-	resp, err := c.do(http.MethodPut, path, contentTypeJSON, send)
+	resp, err := c.do(ctx, "CreateOrUpdateQuotaForClient", http.MethodPut, path, contentTypeJSON, send)
 	if err != nil {
 		return err
 	}
@@ -2716,13 +3055,18 @@ func (c *Client) CreateOrUpdateQuotaForClient(clientID string, config QuotaConfi
 //
 // if "propertiesToRemove" is not passed or empty then the client will send all the available keys to be removed, see `DefaultQuotaConfigPropertiesToRemove` for more.
 func (c *Client) DeleteQuotaForClient(clientID string, propertiesToRemove ...string) error {
+	return c.DeleteQuotaForClientContext(context.Background(), clientID, propertiesToRemove...)
+}
+
+// DeleteQuotaForClientContext same as `DeleteQuotaForClient` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) DeleteQuotaForClientContext(ctx context.Context, clientID string, propertiesToRemove ...string) error {
 	send, err := marshalQuotaConfigPropertiesToBeRemoved(propertiesToRemove)
 	if err != nil {
 		return err
 	}
 
 	path := fmt.Sprintf(quotasPathClient, clientID)
-	resp, err := c.do(http.MethodDelete, path, contentTypeJSON, send)
+	resp, err := c.do(ctx, "DeleteQuotaForClient", http.MethodDelete, path, contentTypeJSON, send)
 	if err != nil {
 		return err
 	}
@@ -2774,7 +3118,12 @@ const (
 //
 // Alert notifications are the result of an `AlertSetting` Condition being met on an `AlertSetting`.
 func (c *Client) GetAlertSettings() (AlertSettings, error) {
-	resp, err := c.do(http.MethodGet, alertSettingsPath, "", nil)
+	return c.GetAlertSettingsContext(context.Background())
+}
+
+// GetAlertSettingsContext same as `GetAlertSettings` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetAlertSettingsContext(ctx context.Context) (AlertSettings, error) {
+	resp, err := c.do(ctx, "GetAlertSettings", http.MethodGet, alertSettingsPath, "", nil)
 	if err != nil {
 		return AlertSettings{}, err
 	}
@@ -2786,8 +3135,13 @@ func (c *Client) GetAlertSettings() (AlertSettings, error) {
 
 // GetAlertSetting returns a specific alert setting based on its "id".
 func (c *Client) GetAlertSetting(id int) (setting AlertSetting, err error) {
+	return c.GetAlertSettingContext(context.Background(), id)
+}
+
+// GetAlertSettingContext same as `GetAlertSetting` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetAlertSettingContext(ctx context.Context, id int) (setting AlertSetting, err error) {
 	path := fmt.Sprintf(alertSettingPath, id)
-	resp, respErr := c.do(http.MethodGet, path, "", nil)
+	resp, respErr := c.do(ctx, "GetAlertSetting", http.MethodGet, path, "", nil)
 	if respErr != nil {
 		err = respErr
 		return
@@ -2799,8 +3153,13 @@ func (c *Client) GetAlertSetting(id int) (setting AlertSetting, err error) {
 
 // EnableAlertSetting enables a specific alert setting based on its "id".
 func (c *Client) EnableAlertSetting(id int) error {
+	return c.EnableAlertSettingContext(context.Background(), id)
+}
+
+// EnableAlertSettingContext same as `EnableAlertSetting` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) EnableAlertSettingContext(ctx context.Context, id int) error {
 	path := fmt.Sprintf(alertSettingPath, id)
-	resp, err := c.do(http.MethodPut, path, "", nil)
+	resp, err := c.do(ctx, "EnableAlertSetting", http.MethodPut, path, "", nil)
 	if err != nil {
 		return err
 	}
@@ -2813,8 +3172,13 @@ type AlertSettingConditions map[string]string
 
 // GetAlertSettingConditions returns alert setting's conditions as a map of strings.
 func (c *Client) GetAlertSettingConditions(id int) (AlertSettingConditions, error) {
+	return c.GetAlertSettingConditionsContext(context.Background(), id)
+}
+
+// GetAlertSettingConditionsContext same as `GetAlertSettingConditions` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetAlertSettingConditionsContext(ctx context.Context, id int) (AlertSettingConditions, error) {
 	path := fmt.Sprintf(alertSettingConditionsPath, id)
-	resp, err := c.do(http.MethodGet, path, "", nil)
+	resp, err := c.do(ctx, "GetAlertSettingConditions", http.MethodGet, path, "", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2861,6 +3225,11 @@ type (
 
 // RegisterAlert registers an Alert, returns an error on failure.
 func (c *Client) RegisterAlert(alert Alert) error {
+	return c.RegisterAlertContext(context.Background(), alert)
+}
+
+// RegisterAlertContext same as `RegisterAlert` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) RegisterAlertContext(ctx context.Context, alert Alert) error {
 	if alert.Labels.Severity == "" {
 		return errRequired("Labels.Severity")
 	}
@@ -2872,7 +3241,7 @@ func (c *Client) RegisterAlert(alert Alert) error {
 		return err
 	}
 
-	resp, err := c.do(http.MethodPost, alertsPath, contentTypeJSON, send)
+	resp, err := c.do(ctx, "RegisterAlert", http.MethodPost, alertsPath, contentTypeJSON, send)
 	if err != nil {
 		return err
 	}
@@ -2882,7 +3251,12 @@ func (c *Client) RegisterAlert(alert Alert) error {
 
 // GetAlerts returns the registered alerts.
 func (c *Client) GetAlerts() (alerts []Alert, err error) {
-	resp, respErr := c.do(http.MethodGet, alertsPath, "", nil)
+	return c.GetAlertsContext(context.Background())
+}
+
+// GetAlertsContext same as `GetAlerts` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetAlertsContext(ctx context.Context) (alerts []Alert, err error) {
+	resp, respErr := c.do(ctx, "GetAlerts", http.MethodGet, alertsPath, "", nil)
 	if respErr != nil {
 		err = respErr
 		return
@@ -2894,8 +3268,13 @@ func (c *Client) GetAlerts() (alerts []Alert, err error) {
 
 // CreateOrUpdateAlertSettingCondition sets a condition(expression text) for a specific alert setting.
 func (c *Client) CreateOrUpdateAlertSettingCondition(alertSettingID int, condition string) error {
+	return c.CreateOrUpdateAlertSettingConditionContext(context.Background(), alertSettingID, condition)
+}
+
+// CreateOrUpdateAlertSettingConditionContext same as `CreateOrUpdateAlertSettingCondition` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) CreateOrUpdateAlertSettingConditionContext(ctx context.Context, alertSettingID int, condition string) error {
 	path := fmt.Sprintf(alertSettingConditionsPath, alertSettingID)
-	resp, err := c.do(http.MethodPost, path, "text/plain", []byte(condition))
+	resp, err := c.do(ctx, "CreateOrUpdateAlertSettingCondition", http.MethodPost, path, "text/plain", []byte(condition))
 	if err != nil {
 		return err
 	}
@@ -2905,8 +3284,13 @@ func (c *Client) CreateOrUpdateAlertSettingCondition(alertSettingID int, conditi
 
 // DeleteAlertSettingCondition deletes a condition from an alert setting.
 func (c *Client) DeleteAlertSettingCondition(alertSettingID int, conditionUUID string) error {
+	return c.DeleteAlertSettingConditionContext(context.Background(), alertSettingID, conditionUUID)
+}
+
+// DeleteAlertSettingConditionContext same as `DeleteAlertSettingCondition` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) DeleteAlertSettingConditionContext(ctx context.Context, alertSettingID int, conditionUUID string) error {
 	path := fmt.Sprintf(alertSettingConditionPath, alertSettingID, conditionUUID)
-	resp, err := c.do(http.MethodDelete, path, "", nil)
+	resp, err := c.do(ctx, "DeleteAlertSettingCondition", http.MethodDelete, path, "", nil)
 	if err != nil {
 		return err
 	}
@@ -2914,60 +3298,66 @@ func (c *Client) DeleteAlertSettingCondition(alertSettingID int, conditionUUID s
 	return resp.Body.Close()
 }
 
-const (
-	alertsPathSSE       = "/api/sse/alerts"
-	alertsSSEDataPrefix = "data:"
-)
+const alertsPathSSE = "/api/sse/alerts"
 
 // AlertHandler is the type of func that can be registered to receive alerts via the `GetAlertsLive`.
 type AlertHandler func(Alert) error
 
 // GetAlertsLive receives alert notifications in real-time from the server via a Send Server Event endpoint.
+// When the server closes the stream or the connection fails, it reconnects based on the `SSEOptions#MaxReconnects`,
+// by default it returns, see `UsingSSEOptions` to keep it running across disconnects.
 func (c *Client) GetAlertsLive(handler AlertHandler) error {
-	resp, err := c.do(http.MethodGet, alertsPathSSE, contentTypeJSON, nil, func(r *http.Request) {
-		r.Header.Add(acceptHeaderKey, "application/json, text/event-stream")
-	}, schemaAPIOption)
-	if err != nil {
-		return err
-	}
+	return c.GetAlertsLiveContext(context.Background(), handler)
+}
 
-	defer resp.Body.Close()
-	reader, err := c.acquireResponseBodyStream(resp)
+// GetAlertsLiveContext same as `GetAlertsLive` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) GetAlertsLiveContext(ctx context.Context, handler AlertHandler) error {
+	const op = "GetAlertsLive"
+
+	stream, err := c.openSSE(ctx, op, alertsPathSSE, "")
 	if err != nil {
 		return err
 	}
 
-	streamReader := bufio.NewReader(reader)
-
+	reconnects := 0
 	for {
-		line, err := streamReader.ReadBytes('\n')
+		// the transport aborts the body read when the context is done,
+		// check it here too so a fast stream stops between the events as well.
+		if err = ctx.Err(); err != nil {
+			stream.Close()
+			return err
+		}
+
+		event, err := stream.Next()
 		if err != nil {
+			stream.Close()
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr // canceled by the caller, report that instead of the read error.
+			}
 			if err == io.EOF {
-				return nil // we read until the the end, exit with no error here.
+				err = nil // the server closed the stream, not an error if no reconnection.
 			}
-			return err // exit on first failure.
-		}
 
-		// ignore all except data: ..., heartbeats.
-		if len(line) < len(alertsSSEDataPrefix)+1 {
+			if stream, err = c.reconnectSSE(ctx, op, alertsPathSSE, stream, err, &reconnects); err != nil || stream == nil {
+				return err
+			}
 			continue
 		}
 
-		message := line[len(alertsSSEDataPrefix):] // we need everything after the 'data:'.
-
-		// it can return data:[empty here] when it stops, let's stop it
-		if len(message) < 2 {
-			return nil // stop here for now.
+		// the server can send empty data as heartbeat.
+		if len(bytes.TrimSpace(event.Data)) == 0 {
+			continue
 		}
+		reconnects = 0
 
 		alert := Alert{}
-
-		if err = json.Unmarshal(message, &alert); err != nil {
-			// exit on first error here as well.
-			return err
+		if err = json.Unmarshal(event.Data, &alert); err != nil {
+			stream.Close()
+			return err // exit on first error here as well.
 		}
 
 		if err = handler(alert); err != nil {
+			stream.Close()
 			return err // stop on first error by the caller.
 		}
 	}
@@ -0,0 +1,852 @@
+// Black-box testing for the client's API calls against a local HTTP server.
+package lenses_test
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/landoop/lenses-go"
+	"github.com/landoop/lenses-go/lensestest"
+)
+
+func openTestConnection(t *testing.T, handler http.Handler) (*lenses.Client, func()) {
+	srv := httptest.NewServer(handler)
+
+	client, err := lenses.OpenConnection(lenses.Configuration{Host: srv.URL, Token: "testtoken"})
+	if err != nil {
+		srv.Close()
+		t.Fatal(err)
+	}
+
+	return client, srv.Close
+}
+
+func TestClientContextDeadline(t *testing.T) {
+	client, teardown := openTestConnection(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer teardown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.GetTopicsContext(ctx); err == nil {
+		t.Fatalf("expected an error when the context deadline is exceeded")
+	}
+}
+
+func TestLSQLContextCancel(t *testing.T) {
+	var canceledPath atomic.Value
+	client, teardown := openTestConnection(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the query is canceled on the server side too, the one that started closest to the client's start time.
+		if strings.HasSuffix(r.URL.Path, "/api/sql/queries") {
+			now := time.Now().UnixNano() / int64(time.Millisecond)
+			fmt.Fprintf(w, `[{"id":1,"sql":"SELECT * FROM reddit_posts","ts":%d},{"id":2,"sql":"SELECT * FROM reddit_posts","ts":%d}]`, now, now+60000)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			canceledPath.Store(r.URL.Path)
+			fmt.Fprint(w, "true")
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data:1{\"topic\":\"reddit_posts\",\"value\":\"{}\"}\n")
+		w.(http.Flusher).Flush()
+
+		// heartbeats until the client goes away.
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(10 * time.Millisecond):
+				fmt.Fprint(w, "data:0\n")
+				w.(http.Flusher).Flush()
+			}
+		}
+	}))
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var records int
+	err := client.LSQLContext(ctx, "SELECT * FROM reddit_posts", false, 0, func(lenses.LSQLRecord) error {
+		records++
+		cancel()
+		return nil
+	}, nil, nil, nil)
+
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled but got: %v", err)
+	}
+
+	if records != 1 {
+		t.Fatalf("expected exactly one record but got %d", records)
+	}
+
+	if path, _ := canceledPath.Load().(string); !strings.HasSuffix(path, "/api/sql/queries/1") {
+		t.Fatalf("expected the query 1 to be canceled on the server side but got: %q", path)
+	}
+}
+
+func TestLSQLContextCancelNotFound(t *testing.T) {
+	var deletes int32
+	client, teardown := openTestConnection(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the running queries don't include ours, i.e it was started by another user.
+		if strings.HasSuffix(r.URL.Path, "/api/sql/queries") {
+			fmt.Fprint(w, `[{"id":1,"sql":"SELECT * FROM cc_payments","ts":0}]`)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			atomic.AddInt32(&deletes, 1)
+			fmt.Fprint(w, "true")
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data:1{\"topic\":\"reddit_posts\",\"value\":\"{}\"}\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := client.LSQLContext(ctx, "SELECT * FROM reddit_posts", false, 0, func(lenses.LSQLRecord) error {
+		cancel()
+		return nil
+	}, nil, nil, nil)
+
+	cancelErr, ok := err.(*lenses.QueryCancelError)
+	if !ok || cancelErr.Err != lenses.ErrRunningQueryNotFound {
+		t.Fatalf("expected the query cancel error of a not found query but got: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&deletes); got != 0 {
+		t.Fatalf("expected no query to be canceled but got %d", got)
+	}
+}
+
+func TestGetConnectorConfig(t *testing.T) {
+	client, teardown := openTestConnection(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the connector itself contains its config under the "config" field, the config endpoint returns the config only.
+		if !strings.HasSuffix(r.URL.Path, "/api/proxy-connect/dev/connectors/file-sink/config") {
+			fmt.Fprint(w, `{"name":"file-sink","config":{"tasks.max":"1"},"tasks":[]}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"connector.class":"org.apache.kafka.connect.file.FileStreamSinkConnector","tasks.max":"1"}`)
+	}))
+	defer teardown()
+
+	cfg, err := client.GetConnectorConfig("dev", "file-sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "org.apache.kafka.connect.file.FileStreamSinkConnector", cfg["connector.class"]; expected != got {
+		t.Fatalf("expected the connector.class %s but got %v", expected, got)
+	}
+}
+
+func TestClientReauthenticate(t *testing.T) {
+	var (
+		logins       int32
+		currentToken atomic.Value
+	)
+	currentToken.Store("") // no valid token until the first login.
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/login" {
+			n := atomic.AddInt32(&logins, 1)
+			tok := fmt.Sprintf("token%d", n)
+			currentToken.Store(tok)
+			fmt.Fprintf(w, `{"success":true,"token":"%s","user":{"name":"admin"}}`, tok)
+			return
+		}
+
+		if r.Header.Get("X-Kafka-Lenses-Token") != currentToken.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		fmt.Fprint(w, "[]")
+	}))
+	defer srv.Close()
+
+	var refreshed []string
+	client, err := lenses.OpenConnection(
+		lenses.Configuration{Host: srv.URL, User: "admin", Password: "admin", Token: "expiredtoken"},
+		lenses.UsingTokenRefreshHandler(func(tok string) { refreshed = append(refreshed, tok) }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// let the first login happen by one of the concurrent requests.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetTopics(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if expected, got := int32(1), atomic.LoadInt32(&logins); expected != got {
+		t.Fatalf("expected %d login but got %d", expected, got)
+	}
+
+	if expected, got := "token1", client.GetAccessToken(); expected != got {
+		t.Fatalf("expected token %s but got %s", expected, got)
+	}
+
+	if len(refreshed) != 1 || refreshed[0] != "token1" {
+		t.Fatalf("expected the refresh handler to be fired once with the new token but got: %v", refreshed)
+	}
+}
+
+func TestClientRetryPolicy(t *testing.T) {
+	var hits int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		fmt.Fprint(w, "[]")
+	}))
+	defer srv.Close()
+
+	client, err := lenses.OpenConnection(lenses.Configuration{Host: srv.URL, Token: "testtoken"},
+		lenses.UsingRetryPolicy(lenses.RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = client.GetTopics(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := int32(3), atomic.LoadInt32(&hits); expected != got {
+		t.Fatalf("expected %d attempts but got %d", expected, got)
+	}
+
+	// non-idempotent methods are not retried by default.
+	atomic.StoreInt32(&hits, 0)
+	if err = client.CreateTopic("topic", 1, 1, nil); err == nil {
+		t.Fatalf("expected an error on 503")
+	}
+
+	if expected, got := int32(1), atomic.LoadInt32(&hits); expected != got {
+		t.Fatalf("expected %d attempt but got %d", expected, got)
+	}
+}
+
+func TestClientRetryPolicyMaxBackoff(t *testing.T) {
+	var hits int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		fmt.Fprint(w, "[]")
+	}))
+	defer srv.Close()
+
+	client, err := lenses.OpenConnection(lenses.Configuration{Host: srv.URL, Token: "testtoken"},
+		lenses.UsingRetryPolicy(lenses.RetryPolicy{MinBackoff: time.Millisecond, MaxBackoff: 50 * time.Millisecond}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the hour of the "Retry-After" is capped to the max backoff.
+	start := time.Now()
+	if _, err = client.GetTopics(); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected the retry to wait up to the max backoff but it took %s", elapsed)
+	}
+
+	if expected, got := int32(2), atomic.LoadInt32(&hits); expected != got {
+		t.Fatalf("expected %d attempts but got %d", expected, got)
+	}
+}
+
+func TestClientAPIError(t *testing.T) {
+	client, teardown := openTestConnection(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"success":false,"message":"Topic reddit_posts does not exist"}`)
+	}))
+	defer teardown()
+
+	_, err := client.GetTopic("reddit_posts")
+	apiErr, ok := err.(*lenses.APIError)
+	if !ok {
+		t.Fatalf("expected an *APIError but got: %#+v", err)
+	}
+
+	if expected, got := http.StatusNotFound, apiErr.StatusCode; expected != got {
+		t.Fatalf("expected status code %d but got %d", expected, got)
+	}
+
+	if expected, got := "Topic reddit_posts does not exist", apiErr.Message; expected != got {
+		t.Fatalf("expected message '%s' but got '%s'", expected, got)
+	}
+
+	if !apiErr.Is(lenses.ErrResourceNotFound) || apiErr.Is(lenses.ErrCredentialsMissing) {
+		t.Fatalf("expected the error to match only the ErrResourceNotFound")
+	}
+}
+
+func TestClientInterceptors(t *testing.T) {
+	client, teardown := openTestConnection(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Request-Id") != "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		fmt.Fprint(w, "[]")
+	}))
+	defer teardown()
+
+	var calls []string
+	lenses.UsingInterceptors(
+		func(op string, req *http.Request, next lenses.RoundTripFunc) (*http.Response, error) {
+			calls = append(calls, "first:"+op)
+			req.Header.Set("X-Request-Id", "1")
+			return next(req)
+		},
+		func(op string, req *http.Request, next lenses.RoundTripFunc) (*http.Response, error) {
+			resp, err := next(req)
+			if err == nil {
+				calls = append(calls, fmt.Sprintf("second:%s:%d", op, resp.StatusCode))
+			}
+			return resp, err
+		},
+	)(client)
+
+	if _, err := client.GetTopics(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "[first:GetTopics second:GetTopics:200]", fmt.Sprintf("%v", calls); expected != got {
+		t.Fatalf("expected calls %s but got %s", expected, got)
+	}
+}
+
+type testLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *testLogger) log(msg string, fields ...lenses.Field) {
+	l.mu.Lock()
+	l.messages = append(l.messages, fmt.Sprintf("%s %v", msg, fields))
+	l.mu.Unlock()
+}
+
+func (l *testLogger) Debug(msg string, fields ...lenses.Field) { l.log(msg, fields...) }
+func (l *testLogger) Info(msg string, fields ...lenses.Field)  { l.log(msg, fields...) }
+func (l *testLogger) Warn(msg string, fields ...lenses.Field)  { l.log(msg, fields...) }
+func (l *testLogger) Error(msg string, fields ...lenses.Field) { l.log(msg, fields...) }
+
+func TestClientLoggerRedaction(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/login" {
+			fmt.Fprint(w, `{"success":true,"token":"secrettoken","user":{"name":"admin"}}`)
+			return
+		}
+
+		fmt.Fprint(w, "[]")
+	}))
+	defer srv.Close()
+
+	for _, unredacted := range []bool{false, true} {
+		logger := new(testLogger)
+		options := []lenses.ConnectionOption{lenses.UsingLogger(logger)}
+		if unredacted {
+			options = append(options, lenses.UsingUnredactedLogs())
+		}
+
+		client, err := lenses.OpenConnection(lenses.Configuration{Host: srv.URL, User: "admin", Password: "secretpass", Debug: true}, options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = client.GetTopics(); err != nil {
+			t.Fatal(err)
+		}
+
+		logs := fmt.Sprintf("%v", logger.messages)
+		for _, secret := range []string{"secrettoken", "secretpass"} {
+			if got := strings.Contains(logs, secret); got != unredacted {
+				t.Fatalf("[unredacted: %v] expected secret '%s' to be logged: %v but got: %v\n%s", unredacted, secret, unredacted, got, logs)
+			}
+		}
+	}
+}
+
+func TestClientTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "[]")
+	}))
+	defer srv.Close()
+
+	caFile, teardown := makeTestFile(t, "ca.pem")
+	defer teardown()
+	pem.Encode(caFile, &pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	tests := []struct {
+		config lenses.Configuration
+		ok     bool
+	}{
+		{lenses.Configuration{Host: srv.URL, Token: "testtoken"}, false},
+		{lenses.Configuration{Host: srv.URL, Token: "testtoken", CAFile: caFile.Name()}, true},
+		{lenses.Configuration{Host: srv.URL, Token: "testtoken", Insecure: true}, true},
+	}
+
+	for i, tt := range tests {
+		client, err := lenses.OpenConnection(tt.config)
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if _, err = client.GetTopics(); (err == nil) != tt.ok {
+			t.Fatalf("[%d] expected success: %v but got error: %v", i, tt.ok, err)
+		}
+	}
+
+	if _, err := (&lenses.Configuration{CertFile: caFile.Name()}).TLSConfig(); err == nil {
+		t.Fatalf("expected an error when the client certificate's key file is missing")
+	}
+}
+
+func TestClientFailover(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close() // connection refused.
+
+	var hits int32
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		fmt.Fprint(w, "[]")
+	}))
+	defer up.Close()
+
+	client, err := lenses.OpenConnection(lenses.Configuration{Host: down.URL, Hosts: []string{up.URL}, Token: "testtoken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err = client.GetTopics(); err != nil {
+			t.Fatal(err)
+		}
+
+		if expected, got := up.URL, client.ActiveHost(); expected != got {
+			t.Fatalf("expected active host %s but got %s", expected, got)
+		}
+	}
+
+	// 1 health probe and 2 calls, the second call goes directly to the active host.
+	if expected, got := int32(3), atomic.LoadInt32(&hits); expected != got {
+		t.Fatalf("expected %d requests to the healthy host but got %d", expected, got)
+	}
+}
+
+func TestClientFailoverNotIdempotent(t *testing.T) {
+	var firstHits, secondHits int32
+	first := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&firstHits, 1)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}))
+	defer first.Close()
+
+	second := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&secondHits, 1)
+	}))
+	defer second.Close()
+
+	client, err := lenses.OpenConnection(lenses.Configuration{Host: first.URL, Hosts: []string{second.URL}, Token: "testtoken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first host may have created the topic already, the POST should not be sent again to the second one.
+	if err = client.CreateTopic("reddit_posts", 1, 1, nil); err == nil {
+		t.Fatalf("expected the 500 to fail the request")
+	}
+
+	if got := atomic.LoadInt32(&secondHits); got != 0 {
+		t.Fatalf("expected no requests to the second host but got %d", got)
+	}
+
+	if expected, got := first.URL, client.ActiveHost(); expected != got {
+		t.Fatalf("expected the active host to stay %s but got %s", expected, got)
+	}
+
+	if expected, got := int32(1), atomic.LoadInt32(&firstHits); expected != got {
+		t.Fatalf("expected %d request to the first host but got %d", expected, got)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	srv := lensestest.NewServer()
+	defer srv.Close()
+
+	srv.AddRecords("reddit_posts",
+		lenses.LSQLRecord{Key: "1", Value: `{"title":"first"}`},
+		lenses.LSQLRecord{Key: "2", Value: `{"title":"second"}`},
+	)
+
+	client, err := lenses.OpenConnection(srv.Configuration())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := client.Query(context.Background(), "SELECT * FROM reddit_posts", lenses.QueryOptions{WithOffsets: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for rows.Next() {
+		keys = append(keys, rows.Record().Key)
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "1,2", strings.Join(keys, ","); expected != got {
+		t.Fatalf("expected keys %s but got %s", expected, got)
+	}
+
+	if stop := rows.Stop(); stop.TotalRecords != 2 || len(stop.Offsets) != 1 {
+		t.Fatalf("unexpected stop: %#+v", stop)
+	}
+
+	if err = rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// cancel the running query by its sql, the `Rows#Close` does that when the query is not finished yet.
+	srv.SetStreamDelay(time.Second)
+
+	if rows, err = client.Query(context.Background(), "SELECT * FROM reddit_posts", lenses.QueryOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var canceled bool
+	for i := 0; i < 50 && !canceled; i++ {
+		time.Sleep(10 * time.Millisecond)
+		if canceled, err = client.CancelRunningQuery(" SELECT * FROM reddit_posts"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !canceled {
+		t.Fatal("expected the running query to be canceled")
+	}
+
+	for rows.Next() {
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if stop := rows.Stop(); !stop.IsStopped {
+		t.Fatalf("expected a stopped query but got: %#+v", stop)
+	}
+
+	if err = rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// query failures are reported by the `Err`.
+	if rows, err = client.Query(context.Background(), "SELECT * FROM missing_topic", lenses.QueryOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		t.Fatal("expected no records")
+	}
+
+	if _, ok := rows.Err().(lenses.LSQLError); !ok {
+		t.Fatalf("expected a query error but got: %v", rows.Err())
+	}
+}
+
+func TestLSQLWaitLimits(t *testing.T) {
+	srv := lensestest.NewServer()
+	defer srv.Close()
+
+	srv.AddRecords("reddit_posts",
+		lenses.LSQLRecord{Key: "1", Value: `{"title":"first"}`},
+		lenses.LSQLRecord{Key: "2", Value: `{"title":"second"}`},
+		lenses.LSQLRecord{Key: "3", Value: `{"title":"third"}`},
+	)
+
+	client, err := lenses.OpenConnection(srv.Configuration(), lenses.UsingLSQLWaitLimits(2, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, _, _, err := client.LSQLWait("SELECT * FROM reddit_posts", false, 0)
+	if err != lenses.ErrQueryLimitExceeded {
+		t.Fatalf("expected the limit error but got: %v", err)
+	}
+
+	if expected, got := 2, len(records); expected != got {
+		t.Fatalf("expected %d records but got %d", expected, got)
+	}
+
+	if _, _, _, err = client.LSQLWait("SELECT * FROM missing_topic", false, 0); err == nil {
+		t.Fatal("expected the query error to be returned")
+	}
+}
+
+func TestLSQLRecordScan(t *testing.T) {
+	// the offset does not fit to the 32-bit int, it's kept as int64 by the decoding.
+	var record lenses.LSQLRecord
+	if err := json.Unmarshal([]byte(`{"topic":"reddit_posts","key":"post-1","offset":9007199254740993,`+
+		`"value":"{\"title\":\"first\",\"score\":12345678901234567890,\"ratio\":0.1000000000000000055511,\"author\":{\"name\":\"john\"}}"}`), &record); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := int64(9007199254740993), record.Offset64(); expected != got {
+		t.Fatalf("expected offset %d but got %d", expected, got)
+	}
+
+	var post struct {
+		ID     string      `lenses:"_key"`
+		Offset int64       `lenses:"_offset"`
+		Title  string      `json:"title"`
+		Score  json.Number `json:"score"`
+		Ratio  interface{} `json:"ratio"`
+		Author string      `lenses:"author.name"`
+	}
+
+	if err := record.Scan(&post); err != nil {
+		t.Fatal(err)
+	}
+
+	if post.ID != "post-1" || post.Offset != 9007199254740993 || post.Title != "first" || post.Author != "john" {
+		t.Fatalf("unexpected scan result: %#+v", post)
+	}
+
+	if expected, got := "12345678901234567890", post.Score.String(); expected != got {
+		t.Fatalf("expected score %s but got %s", expected, got)
+	}
+
+	if expected, got := json.Number("0.1000000000000000055511"), post.Ratio; expected != got {
+		t.Fatalf("expected ratio %v but got %v", expected, got)
+	}
+
+	raw, err := json.Marshal(record.Raw())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(raw), `"key":"post-1"`) || !strings.Contains(string(raw), `"author":{"name":"john"}`) ||
+		!strings.Contains(string(raw), `"offset":9007199254740993`) {
+		t.Fatalf("unexpected raw record: %s", raw)
+	}
+}
+
+func TestLSQLCheckpoint(t *testing.T) {
+	tests := []struct {
+		sql, expected string
+	}{
+		{"SELECT * FROM reddit_posts", "SELECT * FROM reddit_posts WHERE ((_partition = 0 AND _offset > 41) OR (_partition = 2 AND _offset > 7) OR (_partition != 0 AND _partition != 2))"},
+		{"SELECT * FROM reddit_posts LIMIT 10;", "SELECT * FROM reddit_posts WHERE ((_partition = 0 AND _offset > 41) OR (_partition = 2 AND _offset > 7) OR (_partition != 0 AND _partition != 2)) LIMIT 10;"},
+		{"SET max.time='1h'; SELECT * FROM reddit_posts where title = 'a where b' or score > 1 limit 10",
+			"SET max.time='1h'; SELECT * FROM reddit_posts where ((_partition = 0 AND _offset > 41) OR (_partition = 2 AND _offset > 7) OR (_partition != 0 AND _partition != 2)) AND (title = 'a where b' or score > 1) limit 10"},
+	}
+
+	for i, tt := range tests {
+		checkpoint := lenses.NewLSQLCheckpoint(tt.sql)
+		checkpoint.Track(lenses.LSQLRecord{Partition: 2, Offset: 7})
+		checkpoint.Track(lenses.LSQLRecord{Partition: 0, Offset: 41})
+		checkpoint.Track(lenses.LSQLRecord{Partition: 0, Offset: 40})
+
+		got, err := checkpoint.Rewrite()
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if got != tt.expected {
+			t.Fatalf("[%d] expected:\n%s\nbut got:\n%s", i, tt.expected, got)
+		}
+	}
+
+	srv := lensestest.NewServer()
+	defer srv.Close()
+
+	srv.AddRecords("reddit_posts",
+		lenses.LSQLRecord{Key: "1", Value: `{"title":"first"}`},
+		lenses.LSQLRecord{Key: "2", Value: `{"title":"second"}`},
+	)
+
+	client, err := lenses.OpenConnection(srv.Configuration())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "lenses-checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "checkpoint.json")
+
+	const sql = "SELECT * FROM reddit_posts"
+	// readKeys stops after the "max" records, if not zero, without calling the `Next` again.
+	readKeys := func(max int) string {
+		checkpoint, err := lenses.LoadLSQLCheckpoint(filename, sql)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := client.Query(context.Background(), sql, lenses.QueryOptions{Checkpoint: checkpoint})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		var keys []string
+		for rows.Next() {
+			keys = append(keys, rows.Record().Key)
+			if len(keys) == max {
+				break
+			}
+		}
+
+		if err = rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = checkpoint.Save(filename); err != nil {
+			t.Fatal(err)
+		}
+
+		return strings.Join(keys, ",")
+	}
+
+	if expected, got := "1", readKeys(1); expected != got {
+		t.Fatalf("expected the interrupted run to read %s but got %s", expected, got)
+	}
+
+	if expected, got := "1,2", readKeys(0); expected != got {
+		t.Fatalf("expected the first run to read %s again, its last record was not processed, but got %s", expected, got)
+	}
+
+	srv.AddRecords("reddit_posts", lenses.LSQLRecord{Key: "3", Value: `{"title":"third"}`})
+
+	if expected, got := "3", readKeys(0); expected != got {
+		t.Fatalf("expected the second run to continue with %s but got %s", expected, got)
+	}
+
+	if expected, got := "", readKeys(0); expected != got {
+		t.Fatalf("expected no new records but got %s", got)
+	}
+
+	if _, err = lenses.LoadLSQLCheckpoint(filename, "SELECT * FROM other_topic"); err == nil {
+		t.Fatal("expected the checkpoint of a different query to fail")
+	}
+}
+
+func TestGetAlertsLiveReconnect(t *testing.T) {
+	var connections int32
+	lastEventIDs := make(chan string, 1)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		switch atomic.AddInt32(&connections, 1) {
+		case 1:
+			// a multi-line data, an empty data heartbeat and the "\r\n" line endings.
+			fmt.Fprint(w, "\xEF\xBB\xBFretry: 10\r\n: heartbeat\r\n\r\nid: 1\r\ndata: {\"alertId\": 1,\r\ndata: \"endsAt\": \"first\"}\r\n\r\n")
+			fmt.Fprint(w, "data:\n\nid: 2\nevent: alert\ndata:{\"alertId\": 2}\n\n")
+		case 2:
+			lastEventIDs <- r.Header.Get("Last-Event-ID")
+			fmt.Fprint(w, "id: 3\rdata: {\"alertId\": 3}\r\r")
+		default:
+			// send nothing after the headers, the heartbeat timeout should stop the client.
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	})
+
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	client, err := lenses.OpenConnection(lenses.Configuration{Host: srv.URL, Token: "testtoken"},
+		lenses.UsingSSEOptions(lenses.SSEOptions{HeartbeatTimeout: 200 * time.Millisecond, MaxReconnects: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var alerts []string
+	err = client.GetAlertsLive(func(alert lenses.Alert) error {
+		alerts = append(alerts, fmt.Sprintf("%d:%s", alert.AlertID, alert.EndsAt))
+		return nil
+	})
+
+	if err != lenses.ErrSSEHeartbeatTimeout {
+		t.Fatalf("expected the heartbeat timeout error but got: %v", err)
+	}
+
+	if expected, got := "1:first,2:,3:", strings.Join(alerts, ","); expected != got {
+		t.Fatalf("expected alerts %s but got %s", expected, got)
+	}
+
+	if expected, got := "2", <-lastEventIDs; expected != got {
+		t.Fatalf("expected the reconnection to send the last event id %s but got %s", expected, got)
+	}
+
+	if expected, got := int32(3), atomic.LoadInt32(&connections); expected != got {
+		t.Fatalf("expected %d connections but got %d", expected, got)
+	}
+
+	// lines longer than the max line length fail.
+	client, closeSrv := openTestConnection(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "data: {\"alertId\": 1, \"endsAt\": \"%s\"}\n\n", strings.Repeat("a", 1024))
+	}))
+	defer closeSrv()
+
+	lenses.UsingSSEOptions(lenses.SSEOptions{MaxLineLength: 512})(client)
+	if err = client.GetAlertsLive(func(lenses.Alert) error { return nil }); err != lenses.ErrSSELineTooLong {
+		t.Fatalf("expected the line too long error but got: %v", err)
+	}
+}
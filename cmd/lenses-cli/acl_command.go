@@ -8,11 +8,11 @@ import (
 )
 
 func init() {
-	rootCmd.AddCommand(newGetACLsCommand())
-	rootCmd.AddCommand(newACLGroupCommand())
+	rootCmd.AddCommand(newGetACLsCommand(api))
+	rootCmd.AddCommand(newACLGroupCommand(api))
 }
 
-func newGetACLsCommand() *cobra.Command {
+func newGetACLsCommand(api lenses.ACLAPI) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:              "acls",
 		Short:            "Print the list of the available Apache Kafka Access Control Lists",
@@ -21,13 +21,13 @@ func newGetACLsCommand() *cobra.Command {
 	}
 
 	shouldPrintJSON(cmd, func() (interface{}, error) {
-		return client.GetACLs()
+		return api.GetACLs()
 	})
 
 	return cmd
 }
 
-func newACLGroupCommand() *cobra.Command {
+func newACLGroupCommand(api lenses.ACLAPI) *cobra.Command {
 	root := &cobra.Command{
 		Use:              "acl",
 		Short:            "Work with Apache Kafka Access Control List",
@@ -50,12 +50,12 @@ func newACLGroupCommand() *cobra.Command {
 	childrenFlagSet.StringVar(&acl.Host, "acl-host", "", "the acl host, can be empty to apply to all")
 	childrenFlagSet.Var(newVarFlag(&acl.Operation), "operation", "the allowed operation: All, Read, Write, Describe, Create, Delete, DescribeConfigs, AlterConfigs, ClusterAction, IdempotentWrite or Alter")
 
-	root.AddCommand(newCreateOrUpdateACLCommand(&acl, childrenFlagSet, childrenRequiredFlags))
-	root.AddCommand(newDeleteACLCommand(&acl, childrenFlagSet, childrenRequiredFlags))
+	root.AddCommand(newCreateOrUpdateACLCommand(api, &acl, childrenFlagSet, childrenRequiredFlags))
+	root.AddCommand(newDeleteACLCommand(api, &acl, childrenFlagSet, childrenRequiredFlags))
 	return root
 }
 
-func newCreateOrUpdateACLCommand(acl *lenses.ACL, childrenFlagSet *pflag.FlagSet, requiredFlags func() flags) *cobra.Command {
+func newCreateOrUpdateACLCommand(api lenses.ACLAPI, acl *lenses.ACL, childrenFlagSet *pflag.FlagSet, requiredFlags func() flags) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:              "set",
 		Aliases:          []string{"create", "update"}, // acl create or acl update or acl set.
@@ -63,7 +63,7 @@ func newCreateOrUpdateACLCommand(acl *lenses.ACL, childrenFlagSet *pflag.FlagSet
 		Example:          exampleString(`acl set --resourceType="Topic" --resourceName="transactions" --principal="principalType:principalName" --permissionType="Allow" --acl-host="*" --operation="Read"`),
 		TraverseChildren: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := client.CreateOrUpdateACL(*acl); err != nil {
+			if err := api.CreateOrUpdateACL(*acl); err != nil {
 				return err
 			}
 
@@ -80,14 +80,14 @@ func newCreateOrUpdateACLCommand(acl *lenses.ACL, childrenFlagSet *pflag.FlagSet
 	return cmd
 }
 
-func newDeleteACLCommand(acl *lenses.ACL, childrenFlagSet *pflag.FlagSet, requiredFlags func() flags) *cobra.Command {
+func newDeleteACLCommand(api lenses.ACLAPI, acl *lenses.ACL, childrenFlagSet *pflag.FlagSet, requiredFlags func() flags) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:              "delete",
 		Short:            "Delete an Apache Kafka Access Control List",
 		Example:          exampleString(`acl delete ./acl_to_be_deleted.json or .yml or acl delete --resourceType="Topic" --resourceName="transactions" --principal="principalType:principalName" --permissionType="Allow" --acl-host="*" --operation="Read"`),
 		TraverseChildren: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := client.DeleteACL(*acl); err != nil {
+			if err := api.DeleteACL(*acl); err != nil {
 				errResourceNotFoundMessage = "unable to delete, acl does not exist"
 				return err
 			}
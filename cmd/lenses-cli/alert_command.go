@@ -10,11 +10,11 @@ import (
 )
 
 func init() {
-	rootCmd.AddCommand(newGetAlertsCommand())
-	rootCmd.AddCommand(newAlertGroupCommand())
+	rootCmd.AddCommand(newGetAlertsCommand(api))
+	rootCmd.AddCommand(newAlertGroupCommand(api))
 }
 
-func newGetAlertsCommand() *cobra.Command {
+func newGetAlertsCommand(api lenses.AlertsAPI) *cobra.Command {
 	var sse bool
 
 	cmd := &cobra.Command{
@@ -29,10 +29,12 @@ func newGetAlertsCommand() *cobra.Command {
 					return printJSON(cmd, alert)
 				}
 
-				return client.GetAlertsLive(handler)
+				// keep listening across disconnects, until Ctrl+C.
+				applyClientOptions(api, lenses.UsingSSEOptions(lenses.SSEOptions{MaxReconnects: -1}))
+				return api.GetAlertsLive(handler)
 			}
 
-			alerts, err := client.GetAlerts()
+			alerts, err := api.GetAlerts()
 			if err != nil {
 				return err
 			}
@@ -48,7 +50,7 @@ func newGetAlertsCommand() *cobra.Command {
 	return cmd
 }
 
-func newAlertGroupCommand() *cobra.Command {
+func newAlertGroupCommand(api lenses.AlertsAPI) *cobra.Command {
 	root := &cobra.Command{
 		Use:              "alert",
 		Short:            "Work with alerts",
@@ -58,15 +60,15 @@ func newAlertGroupCommand() *cobra.Command {
 	}
 
 	root.AddCommand(
-		newRegisterAlertCommand(),
-		newGetAlertSettingsCommand(),
-		newAlertSettingGroupCommand(),
+		newRegisterAlertCommand(api),
+		newGetAlertSettingsCommand(api),
+		newAlertSettingGroupCommand(api),
 	)
 
 	return root
 }
 
-func newRegisterAlertCommand() *cobra.Command {
+func newRegisterAlertCommand(api lenses.AlertsAPI) *cobra.Command {
 	var alert lenses.Alert
 
 	cmd := &cobra.Command{
@@ -84,7 +86,7 @@ func newRegisterAlertCommand() *cobra.Command {
 				return err
 			}
 
-			err := client.RegisterAlert(alert)
+			err := api.RegisterAlert(alert)
 			if err != nil {
 				return err
 			}
@@ -110,7 +112,7 @@ func newRegisterAlertCommand() *cobra.Command {
 	return cmd
 }
 
-func newGetAlertSettingsCommand() *cobra.Command {
+func newGetAlertSettingsCommand(api lenses.AlertsAPI) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:              "settings",
 		Short:            "Print all alert settings",
@@ -118,7 +120,7 @@ func newGetAlertSettingsCommand() *cobra.Command {
 		TraverseChildren: true,
 		SilenceErrors:    true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			settings, err := client.GetAlertSettings()
+			settings, err := api.GetAlertSettings()
 			if err != nil {
 				return err
 			}
@@ -132,7 +134,7 @@ func newGetAlertSettingsCommand() *cobra.Command {
 	return cmd
 }
 
-func newAlertSettingGroupCommand() *cobra.Command {
+func newAlertSettingGroupCommand(api lenses.AlertsAPI) *cobra.Command {
 	var (
 		id         int
 		mustEnable bool
@@ -148,14 +150,14 @@ func newAlertSettingGroupCommand() *cobra.Command {
 			errResourceNotFoundMessage = fmt.Sprintf("alert setting with id %d does not exist", id)
 
 			if mustEnable {
-				if err := client.EnableAlertSetting(id); err != nil {
+				if err := api.EnableAlertSetting(id); err != nil {
 					return err
 				}
 
 				return echo(cmd, "Alert setting %d enabled", id)
 			}
 
-			settings, err := client.GetAlertSetting(id)
+			settings, err := api.GetAlertSetting(id)
 			if err != nil {
 				return err
 			}
@@ -172,13 +174,13 @@ func newAlertSettingGroupCommand() *cobra.Command {
 	canBeSilent(root)
 	canPrintJSON(root)
 
-	root.AddCommand(newGetAlertSettingConditionsCommand())
-	root.AddCommand(newAlertSettingConditionGroupCommand())
+	root.AddCommand(newGetAlertSettingConditionsCommand(api))
+	root.AddCommand(newAlertSettingConditionGroupCommand(api))
 
 	return root
 }
 
-func newGetAlertSettingConditionsCommand() *cobra.Command {
+func newGetAlertSettingConditionsCommand(api lenses.AlertsAPI) *cobra.Command {
 	var alertID int
 
 	cmd := &cobra.Command{
@@ -188,7 +190,7 @@ func newGetAlertSettingConditionsCommand() *cobra.Command {
 		TraverseChildren: true,
 		SilenceErrors:    true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			conds, err := client.GetAlertSettingConditions(alertID)
+			conds, err := api.GetAlertSettingConditions(alertID)
 			if err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("unable to retrieve conditions, alert setting with id %d does not exist", alertID)
 				return err
@@ -207,7 +209,7 @@ func newGetAlertSettingConditionsCommand() *cobra.Command {
 	return cmd
 }
 
-func newAlertSettingConditionGroupCommand() *cobra.Command {
+func newAlertSettingConditionGroupCommand(api lenses.AlertsAPI) *cobra.Command {
 	rootSub := &cobra.Command{
 		Use:              "condition",
 		Short:            "Work with an alert setting's condition",
@@ -216,8 +218,8 @@ func newAlertSettingConditionGroupCommand() *cobra.Command {
 		SilenceErrors:    true,
 	}
 
-	rootSub.AddCommand(newCreateOrUpdateAlertSettingConditionCommand())
-	rootSub.AddCommand(newDeleteAlertSettingConditionCommand())
+	rootSub.AddCommand(newCreateOrUpdateAlertSettingConditionCommand(api))
+	rootSub.AddCommand(newDeleteAlertSettingConditionCommand(api))
 
 	return rootSub
 }
@@ -227,7 +229,7 @@ type alertSettingConditionPayload struct {
 	Condition string `json:"condition" yaml:"Condition"`
 }
 
-func newCreateOrUpdateAlertSettingConditionCommand() *cobra.Command {
+func newCreateOrUpdateAlertSettingConditionCommand(api lenses.AlertsAPI) *cobra.Command {
 	var cond alertSettingConditionPayload
 
 	cmd := &cobra.Command{
@@ -242,7 +244,7 @@ func newCreateOrUpdateAlertSettingConditionCommand() *cobra.Command {
 				return err
 			}
 
-			err := client.CreateOrUpdateAlertSettingCondition(cond.AlertID, cond.Condition)
+			err := api.CreateOrUpdateAlertSettingCondition(cond.AlertID, cond.Condition)
 			if err != nil {
 				return err
 			}
@@ -260,7 +262,7 @@ func newCreateOrUpdateAlertSettingConditionCommand() *cobra.Command {
 	return cmd
 }
 
-func newDeleteAlertSettingConditionCommand() *cobra.Command {
+func newDeleteAlertSettingConditionCommand(api lenses.AlertsAPI) *cobra.Command {
 	var (
 		alertID       int
 		conditionUUID string
@@ -273,7 +275,7 @@ func newDeleteAlertSettingConditionCommand() *cobra.Command {
 		TraverseChildren: true,
 		SilenceErrors:    true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			err := client.DeleteAlertSettingCondition(alertID, conditionUUID)
+			err := api.DeleteAlertSettingCondition(alertID, conditionUUID)
 			if err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("unable to delete condition, alert setting with id %d or condition with UUID '%s' does not exist", alertID, conditionUUID)
 				return err
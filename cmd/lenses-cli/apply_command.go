@@ -0,0 +1,377 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/landoop/lenses-go"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(newApplyCommand(api))
+}
+
+// errDrift is returned by the `apply --dry-run` when the live state does not match the manifests,
+// the process exits with a non-zero code so it can be used by the CI.
+var errDrift = errors.New("drift detected: the live state does not match the manifests")
+
+func newApplyCommand(api lenses.API) *cobra.Command {
+	var (
+		paths  []string
+		dryRun bool
+		prune  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Create, update or delete resources to match the manifests of the given files or directories",
+		Long: "Loads the manifests, resources with a 'kind' field (" + strings.Join(resourceKindNames(), ", ") + "), " +
+			"computes a plan against the live state, prints it and then applies it.",
+		Example:          exampleString(`apply -f ./manifests/ or apply -f topics.yml -f connectors.yml --dry-run or apply -f ./manifests/ --prune`),
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkRequiredFlags(cmd, flags{"file": strings.Join(paths, ",")}); err != nil {
+				return err
+			}
+
+			manifests, err := loadManifests(paths)
+			if err != nil {
+				return err
+			}
+
+			p, err := newPlan(api, manifests, prune)
+			if err != nil {
+				return err
+			}
+
+			if err = p.print(cmd.OutOrStdout()); err != nil {
+				return err
+			}
+
+			if dryRun {
+				if p.hasChanges() {
+					return errDrift
+				}
+
+				return nil
+			}
+
+			return p.apply(cmd, api)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&paths, "file", "f", nil, "--file=./manifests/ the manifest files or directories, can be repeated")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the plan without applying it, exits with a non-zero code if there are changes")
+	cmd.Flags().BoolVar(&prune, "prune", false, "delete the resources that are not in the manifests, only for the kinds that the manifests contain")
+	canBeSilent(cmd)
+
+	return cmd
+}
+
+type planAction string
+
+const (
+	actionCreate  planAction = "create"
+	actionUpdate  planAction = "update"
+	actionReplace planAction = "replace"
+	actionDelete  planAction = "delete"
+)
+
+var planActionSymbols = map[planAction]string{
+	actionCreate:  "+",
+	actionUpdate:  "~",
+	actionReplace: "-/+",
+	actionDelete:  "-",
+}
+
+// fieldDiff is a change of a resource's field, the field is the json path of the field, i.e "configs.cleanup.policy".
+type fieldDiff struct {
+	field, from, to string
+}
+
+type planChange struct {
+	action planAction
+	kind   *resourceKind
+	id     string
+	// desired is the resource of the manifest, nil on delete.
+	desired interface{}
+	// live is the resource on the server, nil on create.
+	live interface{}
+	diff []fieldDiff
+}
+
+type plan struct {
+	changes   []planChange
+	unchanged int
+}
+
+// newPlan computes the changes that are required for the live state to match the manifests,
+// only the kinds that the manifests contain are read from the server.
+func newPlan(api lenses.API, manifests []manifest, prune bool) (*plan, error) {
+	desiredByKind := make(map[*resourceKind]map[string]manifest)
+	for _, m := range manifests {
+		if m.Kind.normalize != nil {
+			m.Kind.normalize(m.Resource)
+		}
+
+		desired, ok := desiredByKind[m.Kind]
+		if !ok {
+			desired = make(map[string]manifest)
+			desiredByKind[m.Kind] = desired
+		}
+
+		id := m.Kind.id(m.Resource)
+		if prev, exists := desired[id]; exists {
+			return nil, fmt.Errorf("duplicate %s '%s' in %s and %s", m.Kind.name, id, prev.Source, m.Source)
+		}
+		desired[id] = m
+	}
+
+	p := new(plan)
+
+	for _, kind := range resourceKinds {
+		desired, ok := desiredByKind[kind]
+		if !ok {
+			continue
+		}
+
+		live, err := listResources(api, kind)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range sortedResourceIDs(desired) {
+			resource := desired[id].Resource
+			liveResource, exists := live[id]
+			if !exists {
+				p.changes = append(p.changes, planChange{action: actionCreate, kind: kind, id: id, desired: resource})
+				continue
+			}
+
+			diff, err := diffResources(liveResource, resource)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(diff) == 0 {
+				p.unchanged++
+				continue
+			}
+
+			action := actionUpdate
+			if kind.update == nil || changesAny(diff, kind.immutable) {
+				if !kind.replaceable && kind.update != nil {
+					return nil, fmt.Errorf("%s '%s': the fields %s cannot be updated", kind.name, id, strings.Join(kind.immutable, ", "))
+				}
+				action = actionReplace
+			}
+
+			p.changes = append(p.changes, planChange{action: action, kind: kind, id: id, desired: resource, live: liveResource, diff: diff})
+		}
+
+		if !prune {
+			continue
+		}
+
+		for _, id := range sortedResourceIDs(live) {
+			if _, exists := desired[id]; !exists {
+				p.changes = append(p.changes, planChange{action: actionDelete, kind: kind, id: id, live: live[id]})
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// listResources returns the normalized resources of the kind that exist on the server, by their identifier.
+func listResources(api lenses.API, kind *resourceKind) (map[string]interface{}, error) {
+	resources, err := kind.list(api)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve the %s resources: %v", kind.name, err)
+	}
+
+	byID := make(map[string]interface{}, len(resources))
+	for _, resource := range resources {
+		if kind.normalize != nil {
+			kind.normalize(resource)
+		}
+
+		byID[kind.id(resource)] = resource
+	}
+
+	return byID, nil
+}
+
+func sortedResourceIDs(resources interface{}) []string {
+	var ids []string
+	switch m := resources.(type) {
+	case map[string]manifest:
+		for id := range m {
+			ids = append(ids, id)
+		}
+	case map[string]interface{}:
+		for id := range m {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+	return ids
+}
+
+// diffResources returns the fields of the "desired" that are different than the "live" ones,
+// the fields that are missing or empty in the "desired" are not compared, i.e the topic's default configs.
+func diffResources(live, desired interface{}) ([]fieldDiff, error) {
+	liveFields, err := flattenResource(live)
+	if err != nil {
+		return nil, err
+	}
+
+	desiredFields, err := flattenResource(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(desiredFields))
+	for field := range desiredFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var diff []fieldDiff
+	for _, field := range fields {
+		to := desiredFields[field]
+		if to == "" {
+			continue
+		}
+
+		if from := liveFields[field]; from != to {
+			diff = append(diff, fieldDiff{field: field, from: from, to: to})
+		}
+	}
+
+	return diff, nil
+}
+
+// flattenResource returns the json fields of the resource, the nested ones are joined with a dot,
+// i.e {"configs": {"cleanup.policy": "compact"}} results to {"configs.cleanup.policy": "compact"}.
+func flattenResource(resource interface{}) (map[string]string, error) {
+	generic, err := toGeneric(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]string)
+	var flatten func(prefix string, v interface{})
+	flatten = func(prefix string, v interface{}) {
+		if m, ok := v.(map[string]interface{}); ok {
+			for key, value := range m {
+				if prefix != "" {
+					key = prefix + "." + key
+				}
+				flatten(key, value)
+			}
+			return
+		}
+
+		fields[prefix] = formatCell(v)
+	}
+	flatten("", generic)
+
+	return fields, nil
+}
+
+func changesAny(diff []fieldDiff, fields []string) bool {
+	for _, d := range diff {
+		for _, field := range fields {
+			if d.field == field {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (p *plan) hasChanges() bool {
+	return len(p.changes) > 0
+}
+
+func (p *plan) count(action planAction) (n int) {
+	for _, change := range p.changes {
+		if change.action == action {
+			n++
+		}
+	}
+
+	return
+}
+
+func (p *plan) print(w io.Writer) error {
+	if !p.hasChanges() {
+		_, err := fmt.Fprintf(w, "No changes, the live state matches the manifests (%d unchanged).\n", p.unchanged)
+		return err
+	}
+
+	for _, change := range p.changes {
+		fmt.Fprintf(w, "%s %s %s\n", planActionSymbols[change.action], change.kind.name, change.id)
+		for _, d := range change.diff {
+			fmt.Fprintf(w, "    %s: %q => %q\n", d.field, d.from, d.to)
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "Plan: %d to create, %d to update, %d to replace, %d to delete, %d unchanged.\n",
+		p.count(actionCreate), p.count(actionUpdate), p.count(actionReplace), p.count(actionDelete), p.unchanged)
+	return err
+}
+
+// apply creates, updates and replaces the resources in the order of their kinds, see `resourceKinds`,
+// and then deletes the pruned ones in the reverse order, i.e the processors before their topics.
+func (p *plan) apply(cmd *cobra.Command, api lenses.API) error {
+	var deletes []planChange
+
+	for _, change := range p.changes {
+		var err error
+
+		switch change.action {
+		case actionCreate:
+			err = change.kind.create(api, change.desired)
+		case actionUpdate:
+			err = change.kind.update(api, change.desired)
+		case actionReplace:
+			if err = change.kind.delete(api, change.live); err == nil {
+				err = change.kind.create(api, change.desired)
+			}
+		case actionDelete:
+			deletes = append(deletes, change)
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("unable to %s %s '%s': %v", change.action, change.kind.name, change.id, err)
+		}
+
+		if err = echo(cmd, "%s %s %sd", change.kind.name, change.id, change.action); err != nil {
+			return err
+		}
+	}
+
+	for i := len(deletes) - 1; i >= 0; i-- {
+		change := deletes[i]
+		if err := change.kind.delete(api, change.live); err != nil {
+			return fmt.Errorf("unable to delete %s '%s': %v", change.kind.name, change.id, err)
+		}
+
+		if err := echo(cmd, "%s %s deleted", change.kind.name, change.id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/landoop/lenses-go"
+	"github.com/landoop/lenses-go/lensestest"
+)
+
+const testManifestTopics = `kind: Topic
+Name: reddit_posts
+Partitions: 3
+Configs:
+  cleanup.policy: compact
+---
+kind: Topic
+Name: reddit_comments
+`
+
+const testManifestConnector = `[{
+  "kind": "Connector",
+  "ClusterName": "dev",
+  "Name": "file-sink",
+  "Config": {"connector.class": "org.apache.kafka.connect.file.FileStreamSinkConnector", "tasks.max": "1"}
+}]`
+
+func TestApplyCommand(t *testing.T) {
+	srv := lensestest.NewServer()
+	defer srv.Close()
+
+	client, err := lenses.OpenConnection(srv.Configuration())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = client.CreateTopic("unmanaged", 1, 1, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "lenses-cli-apply")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFile := func(name, contents string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("topics.yml", testManifestTopics)
+	writeFile("connectors.json", testManifestConnector)
+
+	apply := func(args ...string) (string, error) {
+		out := new(bytes.Buffer)
+		cmd := newApplyCommand(client)
+		cmd.SetOutput(out)
+		cmd.SetArgs(append([]string{"-f", dir}, args...))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := apply("--dry-run")
+	if err != errDrift {
+		t.Fatalf("expected the drift error but got: %v", err)
+	}
+
+	if expected := "+ Topic reddit_comments\n+ Topic reddit_posts\n+ Connector dev/file-sink\nPlan: 3 to create"; !strings.HasPrefix(out, expected) {
+		t.Fatalf("expected plan:\n%s\nbut got:\n%s", expected, out)
+	}
+
+	if _, err = apply(); err != nil {
+		t.Fatal(err)
+	}
+
+	topic, err := client.GetTopic("reddit_posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if topic.Partitions != 3 {
+		t.Fatalf("expected 3 partitions but got %d", topic.Partitions)
+	}
+
+	if out, err = apply("--dry-run"); err != nil {
+		t.Fatalf("expected no drift but got: %v\n%s", err, out)
+	}
+
+	// update the connector's config and prune the unmanaged topic.
+	writeFile("connectors.json", strings.Replace(testManifestConnector, `"tasks.max": "1"`, `"tasks.max": "2"`, 1))
+
+	if out, err = apply("--prune"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out, "~ Connector dev/file-sink\n    Config.tasks.max: \"1\" => \"2\"\n") || !strings.Contains(out, "- Topic unmanaged\n") {
+		t.Fatalf("unexpected plan:\n%s", out)
+	}
+
+	connector, err := client.GetConnector("dev", "file-sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := connector.Config["tasks.max"]; got != "2" {
+		t.Fatalf("expected the connector's tasks.max to be updated but got: %v", got)
+	}
+
+	if _, err = client.GetTopic("unmanaged"); err == nil {
+		t.Fatal("expected the unmanaged topic to be pruned")
+	}
+}
+
+// testManifestSchema is a schema manifest in the `lenses.Schema` form, with the registry's read-only fields.
+const testManifestSchema = `kind: Schema
+Name: reddit_posts-value
+ID: 7
+version: 3
+AvroSchema: '{"type": "record", "name": "post", "fields": [{"name": "id", "type": "string"}]}'
+`
+
+func TestApplyCommandSchema(t *testing.T) {
+	srv := lensestest.NewServer()
+	defer srv.Close()
+
+	client, err := lenses.OpenConnection(srv.Configuration())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "lenses-cli-apply")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err = ioutil.WriteFile(filepath.Join(dir, "schema.yml"), []byte(testManifestSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"-f", dir}, {"-f", dir, "--dry-run"}} {
+		out := new(bytes.Buffer)
+		cmd := newApplyCommand(client)
+		cmd.SetOutput(out)
+		cmd.SetArgs(args)
+		if err = cmd.Execute(); err != nil {
+			t.Fatalf("%v: %v\n%s", args, err, out)
+		}
+	}
+
+	schema, err := client.GetLatestSchema("reddit_posts-value")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := `{"type":"record","name":"post","fields":[{"name":"id","type":"string"}]}`, schema.AvroSchema; expected != got {
+		t.Fatalf("expected the registered schema %s but got %s", expected, got)
+	}
+}
+
+func TestApplyCommandTopicDefaults(t *testing.T) {
+	srv := lensestest.NewServer()
+	defer srv.Close()
+
+	client, err := lenses.OpenConnection(srv.Configuration())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = client.CreateTopic("reddit_comments", 3, 2, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "lenses-cli-apply")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the manifest omits the partitions and the replication, the existing ones should be kept.
+	if err = ioutil.WriteFile(filepath.Join(dir, "topics.yml"), []byte("kind: Topic\nName: reddit_comments\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := new(bytes.Buffer)
+	cmd := newApplyCommand(client)
+	cmd.SetOutput(out)
+	cmd.SetArgs([]string{"-f", dir})
+	if err = cmd.Execute(); err != nil {
+		t.Fatalf("expected no changes but got: %v\n%s", err, out)
+	}
+
+	if !strings.HasPrefix(out.String(), "No changes") {
+		t.Fatalf("expected no changes but got:\n%s", out)
+	}
+}
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// avroSchema is a parsed Avro schema that encodes the JSON values, i.e the LSQL records' values,
+// to the Avro binary encoding, see https://avro.apache.org/docs/1.8.2/spec.html#binary_encoding.
+//
+// The values are the decoded JSON (using json.Number), the union's branch is selected by the value's type,
+// the Avro JSON encoding of unions, i.e {"string": "value"}, is accepted as well.
+type avroSchema struct {
+	text   string
+	schema interface{}
+	// names are the named types (record, enum and fixed) by their full and short names.
+	names map[string]map[string]interface{}
+}
+
+func parseAvroSchema(text string) (*avroSchema, error) {
+	var schema interface{}
+	if err := json.Unmarshal([]byte(text), &schema); err != nil {
+		return nil, fmt.Errorf("avro: invalid schema: %v", err)
+	}
+
+	s := &avroSchema{text: text, schema: schema, names: make(map[string]map[string]interface{})}
+	s.collectNames(schema, "")
+	return s, nil
+}
+
+func (s *avroSchema) collectNames(schema interface{}, namespace string) {
+	switch t := schema.(type) {
+	case []interface{}:
+		for _, branch := range t {
+			s.collectNames(branch, namespace)
+		}
+	case map[string]interface{}:
+		typ, _ := t["type"].(string)
+		switch typ {
+		case "record", "error", "enum", "fixed":
+			name, _ := t["name"].(string)
+			if ns, ok := t["namespace"].(string); ok {
+				namespace = ns
+			}
+
+			fullName := name
+			if namespace != "" && !strings.Contains(name, ".") {
+				fullName = namespace + "." + name
+			}
+			s.names[fullName] = t
+			s.names[name[strings.LastIndex(name, ".")+1:]] = t
+
+			if fields, ok := t["fields"].([]interface{}); ok {
+				for _, field := range fields {
+					if f, ok := field.(map[string]interface{}); ok {
+						s.collectNames(f["type"], namespace)
+					}
+				}
+			}
+		case "array":
+			s.collectNames(t["items"], namespace)
+		case "map":
+			s.collectNames(t["values"], namespace)
+		default:
+			if _, ok := t["type"].(string); !ok {
+				s.collectNames(t["type"], namespace)
+			}
+		}
+	}
+}
+
+// resolve returns the type name of the schema and, for the complex ones, its definition.
+func (s *avroSchema) resolve(schema interface{}) (string, map[string]interface{}) {
+	switch t := schema.(type) {
+	case string:
+		if named, ok := s.names[t]; ok {
+			typ, _ := named["type"].(string)
+			return typ, named
+		}
+		return t, nil
+	case map[string]interface{}:
+		typ, ok := t["type"].(string)
+		if !ok {
+			return s.resolve(t["type"])
+		}
+		if _, named := s.names[typ]; named {
+			return s.resolve(typ)
+		}
+		return typ, t
+	case []interface{}:
+		return "union", nil
+	}
+
+	return "", nil
+}
+
+// encode appends the binary encoding of the "value" to the "buf".
+func (s *avroSchema) encode(buf *bytes.Buffer, value interface{}) error {
+	return s.encodeValue(buf, s.schema, value, "")
+}
+
+func (s *avroSchema) encodeValue(buf *bytes.Buffer, schema, value interface{}, path string) error {
+	typ, def := s.resolve(schema)
+
+	switch typ {
+	case "union":
+		return s.encodeUnion(buf, schema.([]interface{}), value, path)
+	case "null":
+		if value != nil {
+			return avroTypeError(path, typ, value)
+		}
+		return nil
+	case "boolean":
+		b, ok := value.(bool)
+		if !ok {
+			return avroTypeError(path, typ, value)
+		}
+		if b {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+		return nil
+	case "int", "long":
+		n, ok := value.(json.Number)
+		if !ok {
+			return avroTypeError(path, typ, value)
+		}
+		i, err := n.Int64()
+		if err != nil {
+			return avroTypeError(path, typ, value)
+		}
+		writeAvroLong(buf, i)
+		return nil
+	case "float", "double":
+		n, ok := value.(json.Number)
+		if !ok {
+			return avroTypeError(path, typ, value)
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return avroTypeError(path, typ, value)
+		}
+		if typ == "float" {
+			binary.Write(buf, binary.LittleEndian, math.Float32bits(float32(f)))
+		} else {
+			binary.Write(buf, binary.LittleEndian, math.Float64bits(f))
+		}
+		return nil
+	case "string", "bytes":
+		str, ok := value.(string)
+		if !ok {
+			return avroTypeError(path, typ, value)
+		}
+		writeAvroLong(buf, int64(len(str)))
+		buf.WriteString(str)
+		return nil
+	case "fixed":
+		str, ok := value.(string)
+		if size, _ := def["size"].(float64); !ok || len(str) != int(size) {
+			return avroTypeError(path, typ, value)
+		}
+		buf.WriteString(str)
+		return nil
+	case "enum":
+		str, ok := value.(string)
+		if !ok {
+			return avroTypeError(path, typ, value)
+		}
+		symbols, _ := def["symbols"].([]interface{})
+		for i, symbol := range symbols {
+			if symbol == str {
+				writeAvroLong(buf, int64(i))
+				return nil
+			}
+		}
+		return fmt.Errorf("avro: %s: unknown enum symbol '%s'", path, str)
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return avroTypeError(path, typ, value)
+		}
+		if len(items) > 0 {
+			writeAvroLong(buf, int64(len(items)))
+			for i, item := range items {
+				if err := s.encodeValue(buf, def["items"], item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+		writeAvroLong(buf, 0)
+		return nil
+	case "map":
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return avroTypeError(path, typ, value)
+		}
+		if len(m) > 0 {
+			writeAvroLong(buf, int64(len(m)))
+			for _, key := range sortedKeys(m) {
+				writeAvroLong(buf, int64(len(key)))
+				buf.WriteString(key)
+				if err := s.encodeValue(buf, def["values"], m[key], path+"."+key); err != nil {
+					return err
+				}
+			}
+		}
+		writeAvroLong(buf, 0)
+		return nil
+	case "record", "error":
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return avroTypeError(path, typ, value)
+		}
+		fields, _ := def["fields"].([]interface{})
+		for _, field := range fields {
+			f, _ := field.(map[string]interface{})
+			name, _ := f["name"].(string)
+
+			fieldValue, exists := m[name]
+			if !exists {
+				if fieldValue, exists = f["default"]; exists {
+					fieldValue = jsonNumbers(fieldValue)
+				}
+			}
+
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+
+			if err := s.encodeValue(buf, f["type"], fieldValue, fieldPath); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("avro: %s: unsupported type '%s'", path, typ)
+}
+
+func (s *avroSchema) encodeUnion(buf *bytes.Buffer, branches []interface{}, value interface{}, path string) error {
+	// the Avro JSON encoding wraps the non-null values with their branch's type name.
+	if m, ok := value.(map[string]interface{}); ok && len(m) == 1 {
+		for i, branch := range branches {
+			name := avroTypeName(branch)
+			if inner, exists := m[name]; exists && name != "" {
+				writeAvroLong(buf, int64(i))
+				return s.encodeValue(buf, branch, inner, path)
+			}
+		}
+	}
+
+	for i, branch := range branches {
+		if !s.matches(branch, value) {
+			continue
+		}
+
+		writeAvroLong(buf, int64(i))
+		return s.encodeValue(buf, branch, value, path)
+	}
+
+	return avroTypeError(path, "union", value)
+}
+
+// matches reports whether the "value" can be encoded with the "schema", it's used to select the union's branch.
+func (s *avroSchema) matches(schema, value interface{}) bool {
+	typ, _ := s.resolve(schema)
+
+	switch v := value.(type) {
+	case nil:
+		return typ == "null"
+	case bool:
+		return typ == "boolean"
+	case json.Number:
+		if typ == "int" || typ == "long" {
+			_, err := v.Int64()
+			return err == nil
+		}
+		return typ == "float" || typ == "double"
+	case string:
+		return typ == "string" || typ == "bytes" || typ == "enum" || typ == "fixed"
+	case []interface{}:
+		return typ == "array"
+	case map[string]interface{}:
+		return typ == "record" || typ == "error" || typ == "map"
+	}
+
+	return false
+}
+
+// avroTypeName returns the name that the Avro JSON encoding uses for a union's branch.
+func avroTypeName(schema interface{}) string {
+	switch t := schema.(type) {
+	case string:
+		return t
+	case map[string]interface{}:
+		if name, ok := t["name"].(string); ok {
+			if ns, ok := t["namespace"].(string); ok && !strings.Contains(name, ".") {
+				return ns + "." + name
+			}
+			return name
+		}
+		typ, _ := t["type"].(string)
+		return typ
+	}
+
+	return ""
+}
+
+func avroTypeError(path, typ string, value interface{}) error {
+	if path == "" {
+		path = "value"
+	}
+
+	return fmt.Errorf("avro: %s: cannot encode %T as %s", path, value, typ)
+}
+
+// jsonNumbers converts the float64 numbers of the schema's defaults to json.Number.
+func jsonNumbers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case float64:
+		return json.Number(fmt.Sprint(t))
+	case []interface{}:
+		for i := range t {
+			t[i] = jsonNumbers(t[i])
+		}
+	case map[string]interface{}:
+		for key := range t {
+			t[key] = jsonNumbers(t[key])
+		}
+	}
+
+	return v
+}
+
+// writeAvroLong writes the zig-zag variable length encoding of the "n", used for both int and long.
+func writeAvroLong(buf *bytes.Buffer, n int64) {
+	var b [binary.MaxVarintLen64]byte
+	buf.Write(b[:binary.PutVarint(b[:], n)])
+}
+
+// avroBlockRecords is the maximum number of records of an Avro Object Container File's block.
+const avroBlockRecords = 100
+
+// avroFileWriter writes the Avro Object Container File, see https://avro.apache.org/docs/1.8.2/spec.html#Object+Container+Files,
+// the blocks are not compressed ("null" codec).
+type avroFileWriter struct {
+	w      io.Writer
+	schema *avroSchema
+	sync   [16]byte
+
+	block   bytes.Buffer
+	records int
+}
+
+func newAvroFileWriter(w io.Writer, schema *avroSchema) (*avroFileWriter, error) {
+	fw := &avroFileWriter{w: w, schema: schema}
+	if _, err := rand.Read(fw.sync[:]); err != nil {
+		return nil, err
+	}
+
+	header := new(bytes.Buffer)
+	header.WriteString("Obj\x01")
+	// the metadata, a map of bytes.
+	writeAvroLong(header, 2)
+	for _, kv := range [][2]string{{"avro.schema", schema.text}, {"avro.codec", "null"}} {
+		writeAvroLong(header, int64(len(kv[0])))
+		header.WriteString(kv[0])
+		writeAvroLong(header, int64(len(kv[1])))
+		header.WriteString(kv[1])
+	}
+	writeAvroLong(header, 0)
+	header.Write(fw.sync[:])
+
+	_, err := w.Write(header.Bytes())
+	return fw, err
+}
+
+// Write encodes the value to the current block, the block is written when it's full or on `Close`.
+func (fw *avroFileWriter) Write(value interface{}) error {
+	// encode to a separate buffer first, so a value that does not match the schema does not corrupt the block.
+	record := new(bytes.Buffer)
+	if err := fw.schema.encode(record, value); err != nil {
+		return err
+	}
+	fw.block.Write(record.Bytes())
+
+	fw.records++
+	if fw.records >= avroBlockRecords {
+		return fw.flush()
+	}
+
+	return nil
+}
+
+func (fw *avroFileWriter) flush() error {
+	if fw.records == 0 {
+		return nil
+	}
+
+	header := new(bytes.Buffer)
+	writeAvroLong(header, int64(fw.records))
+	writeAvroLong(header, int64(fw.block.Len()))
+
+	for _, b := range [][]byte{header.Bytes(), fw.block.Bytes(), fw.sync[:]} {
+		if _, err := fw.w.Write(b); err != nil {
+			return err
+		}
+	}
+
+	fw.block.Reset()
+	fw.records = 0
+	return nil
+}
+
+// Close writes the last block, it does not close the underline writer.
+func (fw *avroFileWriter) Close() error {
+	return fw.flush()
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// avroTestFile is a decoded Avro Object Container File.
+type avroTestFile struct {
+	Metadata map[string]string
+	Blocks   int
+	// Records are the decoded records, the unions are decoded to their branch's value
+	// and the numbers to json.Number, so they can be compared as JSON.
+	Records []interface{}
+}
+
+// decodeAvroFile decodes the "data" with the schema of its metadata, it's the reverse of the `avroFileWriter`.
+func decodeAvroFile(t *testing.T, data []byte) avroTestFile {
+	t.Helper()
+
+	d := &avroTestDecoder{t: t, r: bytes.NewReader(data)}
+	if magic := d.read(4); string(magic) != "Obj\x01" {
+		t.Fatalf("avro: expected the magic bytes but got %q", magic)
+	}
+
+	file := avroTestFile{Metadata: make(map[string]string)}
+	d.readBlocks(func() {
+		key := string(d.read(int(d.long())))
+		file.Metadata[key] = string(d.read(int(d.long())))
+	})
+	sync := d.read(16)
+
+	if codec := file.Metadata["avro.codec"]; codec != "null" {
+		t.Fatalf("avro: expected the null codec but got %q", codec)
+	}
+
+	schema, err := parseAvroSchema(file.Metadata["avro.schema"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.schema = schema
+
+	for d.r.Len() > 0 {
+		count, size := d.long(), d.long()
+		remaining := d.r.Len()
+		for i := int64(0); i < count; i++ {
+			file.Records = append(file.Records, d.decode(schema.schema))
+		}
+
+		if read := int64(remaining - d.r.Len()); read != size {
+			t.Fatalf("avro: block %d: expected %d bytes but read %d", file.Blocks, size, read)
+		}
+
+		if got := d.read(16); !bytes.Equal(got, sync) {
+			t.Fatalf("avro: block %d: expected the sync marker %q but got %q", file.Blocks, sync, got)
+		}
+		file.Blocks++
+	}
+
+	return file
+}
+
+type avroTestDecoder struct {
+	t      *testing.T
+	r      *bytes.Reader
+	schema *avroSchema
+}
+
+func (d *avroTestDecoder) read(n int) []byte {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		d.t.Fatalf("avro: read %d bytes: %v", n, err)
+	}
+	return b
+}
+
+func (d *avroTestDecoder) long() int64 {
+	n, err := binary.ReadVarint(d.r)
+	if err != nil {
+		d.t.Fatalf("avro: read long: %v", err)
+	}
+	return n
+}
+
+// readBlocks reads the blocks of an array or a map, the "item" reads each one of their items.
+func (d *avroTestDecoder) readBlocks(item func()) {
+	for {
+		n := d.long()
+		if n == 0 {
+			return
+		}
+
+		if n < 0 {
+			n = -n
+			d.long() // the block's size in bytes.
+		}
+
+		for i := int64(0); i < n; i++ {
+			item()
+		}
+	}
+}
+
+func (d *avroTestDecoder) decode(schema interface{}) interface{} {
+	typ, def := d.schema.resolve(schema)
+
+	switch typ {
+	case "union":
+		branches := schema.([]interface{})
+		i := d.long()
+		if i < 0 || i >= int64(len(branches)) {
+			d.t.Fatalf("avro: union branch %d out of range", i)
+		}
+		return d.decode(branches[i])
+	case "null":
+		return nil
+	case "boolean":
+		return d.read(1)[0] == 1
+	case "int", "long":
+		return json.Number(strconv.FormatInt(d.long(), 10))
+	case "float":
+		f := math.Float32frombits(binary.LittleEndian.Uint32(d.read(4)))
+		return json.Number(strconv.FormatFloat(float64(f), 'g', -1, 32))
+	case "double":
+		f := math.Float64frombits(binary.LittleEndian.Uint64(d.read(8)))
+		return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+	case "string", "bytes":
+		return string(d.read(int(d.long())))
+	case "fixed":
+		size, _ := def["size"].(float64)
+		return string(d.read(int(size)))
+	case "enum":
+		symbols, _ := def["symbols"].([]interface{})
+		return symbols[d.long()]
+	case "array":
+		items := []interface{}{}
+		d.readBlocks(func() {
+			items = append(items, d.decode(def["items"]))
+		})
+		return items
+	case "map":
+		m := make(map[string]interface{})
+		d.readBlocks(func() {
+			key := string(d.read(int(d.long())))
+			m[key] = d.decode(def["values"])
+		})
+		return m
+	case "record", "error":
+		m := make(map[string]interface{})
+		fields, _ := def["fields"].([]interface{})
+		for _, field := range fields {
+			f, _ := field.(map[string]interface{})
+			name, _ := f["name"].(string)
+			m[name] = d.decode(f["type"])
+		}
+		return m
+	}
+
+	d.t.Fatalf("avro: unsupported type '%s'", typ)
+	return nil
+}
+
+// decodeJSON decodes the "s" the way the `sql export` decodes the records' values.
+func decodeJSON(t *testing.T, s string) interface{} {
+	t.Helper()
+
+	var value interface{}
+	decoder := json.NewDecoder(strings.NewReader(s))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		t.Fatal(err)
+	}
+
+	return value
+}
+
+func TestAvroFileWriter(t *testing.T) {
+	schemaText := `{"type":"record","name":"Post","namespace":"io.lenses","fields":[` +
+		`{"name":"title","type":"string"},` +
+		`{"name":"score","type":"long"},` +
+		`{"name":"ratio","type":"float"},` +
+		`{"name":"rank","type":"double"},` +
+		`{"name":"nsfw","type":"boolean"},` +
+		`{"name":"flair","type":["null","string"]},` +
+		`{"name":"kind","type":{"type":"enum","name":"Kind","symbols":["LINK","SELF"]}},` +
+		`{"name":"hash","type":{"type":"fixed","name":"Hash","size":4}},` +
+		`{"name":"author","type":{"type":"record","name":"Author","fields":[{"name":"name","type":"string"},{"name":"karma","type":"int"}]}},` +
+		`{"name":"editor","type":["null","Author"],"default":null},` +
+		`{"name":"tags","type":{"type":"array","items":"string"},"default":[]},` +
+		`{"name":"votes","type":{"type":"map","values":"int"},"default":{}}]}`
+
+	schema, err := parseAvroSchema(schemaText)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		value    string
+		expected string
+	}{
+		{ // the missing fields are written with their defaults.
+			`{"title":"first","score":-42,"ratio":0.5,"rank":1.25,"nsfw":false,"flair":null,"kind":"LINK","hash":"abcd","author":{"name":"john","karma":10}}`,
+			`{"author":{"karma":10,"name":"john"},"editor":null,"flair":null,"hash":"abcd","kind":"LINK","nsfw":false,"rank":1.25,"ratio":0.5,"score":-42,"tags":[],"title":"first","votes":{}}`,
+		},
+		{ // the "flair" is in the Avro JSON encoding of unions.
+			`{"title":"second","score":300,"ratio":2,"rank":-0.1,"nsfw":true,"flair":{"string":"news"},"kind":"SELF","hash":"wxyz",` +
+				`"author":{"name":"jane","karma":20},"editor":{"name":"joe","karma":30},"tags":["a","b"],"votes":{"up":5,"down":-1}}`,
+			`{"author":{"karma":20,"name":"jane"},"editor":{"karma":30,"name":"joe"},"flair":"news","hash":"wxyz","kind":"SELF","nsfw":true,"rank":-0.1,"ratio":2,"score":300,"tags":["a","b"],"title":"second","votes":{"down":-1,"up":5}}`,
+		},
+	}
+
+	out := new(bytes.Buffer)
+	w, err := newAvroFileWriter(out, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, tt := range tests {
+		if err = w.Write(decodeJSON(t, tt.value)); err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		// a value that does not match the schema fails without breaking the block.
+		if err = w.Write(decodeJSON(t, `{"title":"invalid"}`)); err == nil {
+			t.Fatalf("[%d] expected the value without a score to fail", i)
+		}
+	}
+
+	// fill the first block and start a second one.
+	for i := len(tests); i <= avroBlockRecords; i++ {
+		if err = w.Write(decodeJSON(t, tests[0].value)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file := decodeAvroFile(t, out.Bytes())
+	if expected, got := schemaText, file.Metadata["avro.schema"]; expected != got {
+		t.Fatalf("expected the schema to be:\n%s\nbut got:\n%s", expected, got)
+	}
+
+	if expected, got := 2, file.Blocks; expected != got {
+		t.Fatalf("expected %d blocks but got %d", expected, got)
+	}
+
+	if expected, got := avroBlockRecords+1, len(file.Records); expected != got {
+		t.Fatalf("expected %d records but got %d", expected, got)
+	}
+
+	for i, record := range file.Records {
+		expected := tests[0].expected
+		if i < len(tests) {
+			expected = tests[i].expected
+		}
+
+		b, err := json.Marshal(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := string(b); expected != got {
+			t.Fatalf("[%d] expected the record to be:\n%s\nbut got:\n%s", i, expected, got)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/landoop/lenses-go"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(newCompletionCommand())
+}
+
+// newCompletionCommand requires the github.com/spf13/cobra v1.1.0 at least,
+// the dynamic completions and the fish and powershell scripts are missing from the older versions.
+func newCompletionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion bash|zsh|fish|powershell",
+		Short: "Print the shell completion script, the resources' names are completed too, i.e the topic --name",
+		Long: "Prints the completion script of the shell, to load it on each session:\n" +
+			"bash: add 'source <(lenses-cli completion bash)' to the ~/.bashrc\n" +
+			"zsh: lenses-cli completion zsh > \"${fpath[1]}/_lenses-cli\"\n" +
+			"fish: lenses-cli completion fish > ~/.config/fish/completions/lenses-cli.fish\n" +
+			"powershell: add 'lenses-cli completion powershell | Out-String | Invoke-Expression' to the $PROFILE",
+		Example:          exampleString(`completion bash > /etc/bash_completion.d/lenses-cli`),
+		ValidArgs:        []string{"bash", "zsh", "fish", "powershell"},
+		Args:             cobra.ExactValidArgs(1),
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, w := cmd.Root(), cmd.OutOrStdout()
+
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletion(w)
+			case "zsh":
+				return root.GenZshCompletion(w)
+			case "fish":
+				return root.GenFishCompletion(w, true)
+			default:
+				return root.GenPowerShellCompletion(w)
+			}
+		},
+	}
+
+	return cmd
+}
+
+// completionCacheTTL is the duration that the completion values are cached on disk,
+// so the tab completion does not send a request to the Lenses server on each key press.
+const completionCacheTTL = 30 * time.Second
+
+// completionCacheDir is the directory of the cached completion values, one file per context and resource.
+var completionCacheDir = filepath.Join(lenses.DefaultConfigurationHomeDir, "cache", "completion")
+
+// flagCompletion completes the values of a flag of the commands under a top level command, i.e the "topic" `--name`.
+type flagCompletion struct {
+	commands []string
+	flag     string
+	// cacheKey is the name of the cached values' file.
+	cacheKey string
+	fetch    func(api lenses.API) ([]string, error)
+	// filter optionally transforms the cached values based on the command's other flags,
+	// i.e the connectors' names are cached as "cluster/name" and filtered by the `--clusterName`.
+	filter func(cmd *cobra.Command, values []string) []string
+}
+
+var flagCompletions = []flagCompletion{
+	{
+		commands: []string{"topic"},
+		flag:     "name",
+		cacheKey: "topics",
+		fetch:    func(api lenses.API) ([]string, error) { return api.GetTopicsNames() },
+	},
+	{
+		commands: []string{"connectors", "connector"},
+		flag:     "clusterName",
+		cacheKey: "connect-clusters",
+		fetch: func(api lenses.API) ([]string, error) {
+			clusters, err := api.GetConnectClusters()
+			if err != nil {
+				return nil, err
+			}
+
+			names := make([]string, len(clusters))
+			for i, cluster := range clusters {
+				names[i] = cluster.Name
+			}
+			return names, nil
+		},
+	},
+	{
+		commands: []string{"connector"},
+		flag:     "name",
+		cacheKey: "connectors",
+		fetch: func(api lenses.API) ([]string, error) {
+			clusters, err := api.GetConnectClusters()
+			if err != nil {
+				return nil, err
+			}
+
+			var names []string
+			for _, cluster := range clusters {
+				connectors, err := api.GetConnectors(cluster.Name)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, name := range connectors {
+					names = append(names, cluster.Name+"/"+name)
+				}
+			}
+			return names, nil
+		},
+		filter: func(cmd *cobra.Command, values []string) []string {
+			clusterName, _ := cmd.Flags().GetString("clusterName")
+
+			var names []string
+			seen := make(map[string]bool) // the same name may exist on more than one clusters.
+			for _, value := range values {
+				parts := strings.SplitN(value, "/", 2)
+				if len(parts) == 2 && (clusterName == "" || parts[0] == clusterName) && !seen[parts[1]] {
+					seen[parts[1]] = true
+					names = append(names, parts[1])
+				}
+			}
+			return names
+		},
+	},
+	{
+		commands: []string{"schema"},
+		flag:     "name",
+		cacheKey: "subjects",
+		fetch:    func(api lenses.API) ([]string, error) { return api.GetSubjects() },
+	},
+	{
+		commands: []string{"processors", "processor"},
+		flag:     "clusterName",
+		cacheKey: "processor-clusters",
+		fetch: func(api lenses.API) ([]string, error) {
+			return processorValues(api, func(p lenses.ProcessorStream) string { return p.ClusterName })
+		},
+	},
+	{
+		commands: []string{"processors", "processor"},
+		flag:     "name",
+		cacheKey: "processors",
+		fetch: func(api lenses.API) ([]string, error) {
+			return processorValues(api, func(p lenses.ProcessorStream) string { return p.Name })
+		},
+	},
+}
+
+func processorValues(api lenses.API, value func(lenses.ProcessorStream) string) ([]string, error) {
+	result, err := api.GetProcessors()
+	if err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for _, processor := range result.Streams {
+		if v := value(processor); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+// registerCompletions registers the dynamic completion of the `flagCompletions` and the root's `--context`,
+// it should be called after all commands and the configuration manager's flags are registered.
+func registerCompletions(root *cobra.Command) {
+	root.RegisterFlagCompletionFunc("context", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		configManager.load()
+
+		names := make([]string, 0, len(configManager.config.Contexts))
+		for name := range configManager.config.Contexts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	for _, top := range root.Commands() {
+		for i := range flagCompletions {
+			c := flagCompletions[i]
+			if !hasString(c.commands, top.Name()) {
+				continue
+			}
+
+			registerFlagCompletion(top, c)
+		}
+	}
+}
+
+func registerFlagCompletion(cmd *cobra.Command, c flagCompletion) {
+	// the new resources' names can't be completed.
+	if name := cmd.Name(); name != "create" && name != "register" && cmd.Flags().Lookup(c.flag) != nil {
+		cmd.RegisterFlagCompletionFunc(c.flag, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			values, err := completionValues(c.cacheKey, c.fetch)
+			if err != nil {
+				cobra.CompDebugln(err.Error(), false)
+				return nil, cobra.ShellCompDirectiveError
+			}
+
+			if c.filter != nil {
+				values = c.filter(cmd, values)
+			}
+
+			return values, cobra.ShellCompDirectiveNoFileComp
+		})
+	}
+
+	for _, sub := range cmd.Commands() {
+		registerFlagCompletion(sub, c)
+	}
+}
+
+// completionValues returns the cached values of the current context or fetches and caches them,
+// it connects to the Lenses server only when the cache is missing or expired.
+func completionValues(key string, fetch func(api lenses.API) ([]string, error)) ([]string, error) {
+	ok, err := configManager.load()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid configuration")
+	}
+
+	filename := filepath.Join(completionCacheDir, unsafeFilenameChars.ReplaceAllString(configManager.config.CurrentContext, "_")+"-"+key)
+	if values, ok := readCompletionCache(filename, completionCacheTTL); ok {
+		return values, nil
+	}
+
+	if api.API == nil {
+		if err = setupClient(); err != nil {
+			return nil, err
+		}
+	}
+
+	values, err := fetch(api)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(values)
+
+	// the cache is an optimization, failures to write it are ignored.
+	writeCompletionCache(filename, values)
+	return values, nil
+}
+
+// readCompletionCache returns the values of the cache file, one per line,
+// if it exists and it was written in the last "ttl" duration.
+func readCompletionCache(filename string, ttl time.Duration) ([]string, bool) {
+	info, err := os.Stat(filename)
+	if err != nil || time.Since(info.ModTime()) > ttl {
+		return nil, false
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	var values []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		values = append(values, scanner.Text())
+	}
+
+	return values, scanner.Err() == nil
+}
+
+func writeCompletionCache(filename string, values []string) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return err
+	}
+
+	var contents string
+	if len(values) > 0 {
+		contents = strings.Join(values, "\n") + "\n"
+	}
+
+	return ioutil.WriteFile(filename, []byte(contents), 0600)
+}
+
+func hasString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestCompletionCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lenses-cli-completion")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "cache", "master-topics")
+	if _, ok := readCompletionCache(filename, time.Minute); ok {
+		t.Fatal("expected a missing cache")
+	}
+
+	if err = writeCompletionCache(filename, []string{"reddit_comments", "reddit_posts"}); err != nil {
+		t.Fatal(err)
+	}
+
+	values, ok := readCompletionCache(filename, time.Minute)
+	if !ok {
+		t.Fatal("expected a cache hit")
+	}
+
+	if expected, got := "reddit_comments,reddit_posts", strings.Join(values, ","); expected != got {
+		t.Fatalf("expected values %s but got %s", expected, got)
+	}
+
+	past := time.Now().Add(-2 * time.Minute)
+	if err = os.Chtimes(filename, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok = readCompletionCache(filename, time.Minute); ok {
+		t.Fatal("expected an expired cache")
+	}
+}
+
+func TestCompletionConnectorsFilter(t *testing.T) {
+	var filter func(*cobra.Command, []string) []string
+	for _, c := range flagCompletions {
+		if c.cacheKey == "connectors" {
+			filter = c.filter
+		}
+	}
+
+	cmd := &cobra.Command{Use: "status"}
+	cmd.Flags().String("clusterName", "", "")
+
+	values := []string{"dev/file-sink", "dev/file-source", "prod/file-sink"}
+	if expected, got := "file-sink,file-source", strings.Join(filter(cmd, values), ","); expected != got {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	cmd.Flags().Set("clusterName", "prod")
+	if expected, got := "file-sink", strings.Join(filter(cmd, values), ","); expected != got {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
@@ -3,15 +3,17 @@ package main
 import (
 	"fmt"
 
+	"github.com/landoop/lenses-go"
+
 	"github.com/spf13/cobra"
 )
 
 func init() {
-	rootCmd.AddCommand(newGetConfigsCommand())
-	rootCmd.AddCommand(newGetModeCommand())
+	rootCmd.AddCommand(newGetConfigsCommand(api))
+	rootCmd.AddCommand(newGetModeCommand(api))
 }
 
-func newGetConfigsCommand() *cobra.Command {
+func newGetConfigsCommand(api lenses.ConfigAPI) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:              "configs",
 		Short:            "Print the whole lenses box configs",
@@ -24,18 +26,18 @@ func newGetConfigsCommand() *cobra.Command {
 					// let's support it here as well, although
 					// mode has its own command `mode` because it's super important
 					// and users should call that instead.
-					return newGetModeCommand().Execute()
+					return newGetModeCommand(api).Execute()
 				}
 
 				var value interface{}
-				if err := client.GetConfigEntry(&value, args[0]); err == nil {
+				if err := api.GetConfigEntry(&value, args[0]); err == nil {
 					return printJSON(cmd, value)
 					// if error or no valid key then continue with printing the whole lenses configuration.
 				}
 
 			}
 
-			config, err := client.GetConfig()
+			config, err := api.GetConfig()
 			if err != nil {
 				return err
 			}
@@ -51,7 +53,7 @@ func newGetConfigsCommand() *cobra.Command {
 
 const commandModeName = "mode"
 
-func newGetModeCommand() *cobra.Command {
+func newGetModeCommand(api lenses.ConfigAPI) *cobra.Command {
 	return &cobra.Command{
 		Use:                   commandModeName,
 		Short:                 "Print the configuration's execution mode",
@@ -61,7 +63,7 @@ func newGetModeCommand() *cobra.Command {
 		DisableSuggestions:    true,
 		TraverseChildren:      false,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			mode, err := client.GetExecutionMode()
+			mode, err := api.GetExecutionMode()
 			if err != nil {
 				return err
 			}
@@ -39,12 +39,19 @@ func newConfigurationManager(cmd *cobra.Command) *configurationManager {
 	cmd.PersistentFlags().StringVar(&m.config.CurrentContext, "context", "", "--context=dev load specific environment, embedded configuration based on the configuration's 'Contexts'")
 
 	cmd.PersistentFlags().StringVar(&m.flags.Host, "host", "", "--host=https://example.com")
+	cmd.PersistentFlags().StringSliceVar(&m.flags.Hosts, "hosts", nil, "--hosts=https://example2.com,https://example3.com more hosts to fail over to, in order, when the --host is unreachable")
 	cmd.PersistentFlags().StringVar(&m.flags.User, "user", "", "--user=MyUser")
 	cmd.PersistentFlags().StringVar(&m.flags.Timeout, "timeout", "", "--timeout=30s timeout for the connection establishment")
 	cmd.PersistentFlags().StringVar(&m.flags.Password, "pass", "", "--pass=MyPassword")
 	cmd.PersistentFlags().StringVar(&m.flags.Token, "token", "", "--token=DSAUH321S%423#32$321ZXN")
 	cmd.PersistentFlags().BoolVar(&m.flags.Debug, "debug", false, "print some information that are necessary for debugging")
 
+	cmd.PersistentFlags().StringVar(&m.flags.CAFile, "ca-file", "", "--ca-file=./ca.pem verify the server using the certificate authority bundle, in addition to the system ones")
+	cmd.PersistentFlags().StringVar(&m.flags.CertFile, "cert-file", "", "--cert-file=./client.pem the client certificate for mutual TLS, requires --key-file")
+	cmd.PersistentFlags().StringVar(&m.flags.KeyFile, "key-file", "", "--key-file=./client-key.pem the client certificate's private key for mutual TLS")
+	cmd.PersistentFlags().StringVar(&m.flags.ServerName, "server-name", "", "--server-name=lenses.example.com override the host name used to verify the server's certificate")
+	cmd.PersistentFlags().BoolVar(&m.flags.Insecure, "insecure", false, "skip the verification of the server's certificate, use it only for testing")
+
 	cmd.PersistentFlags().StringVar(&m.filepath, "config", "", "load or save the host, user, pass and debug fields from or to a configuration file (yaml, toml or json)")
 	return m
 }
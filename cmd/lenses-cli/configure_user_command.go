@@ -23,10 +23,10 @@ func init() {
 	rootCmd.AddCommand(newGetConfigurationContextsCommand())
 	rootCmd.AddCommand(newConfigurationContextCommand())
 	rootCmd.AddCommand(newConfigureCommand())
-	rootCmd.AddCommand(newLoginCommand())
-	rootCmd.AddCommand(newGetUserInfoCommand())
+	rootCmd.AddCommand(newLoginCommand(api))
+	rootCmd.AddCommand(newGetUserInfoCommand(api))
 	// remove `logout` command (at least for the moment) rootCmd.AddCommand(newLogoutCommand())
-	rootCmd.AddCommand(newGetLicenseInfoCommand())
+	rootCmd.AddCommand(newGetLicenseInfoCommand(api))
 }
 
 func isValidConfigurationContext(name string) bool {
@@ -319,6 +319,12 @@ func newConfigureCommand() *cobra.Command {
 
 				if err := survey.Ask(qs, currentConfig); err != nil {
 					return err
+				}
+
+				if strings.HasPrefix(currentConfig.Host, "https://") {
+					if err := survey.Ask(tlsQuestions(currentConfig), currentConfig); err != nil {
+						return err
+					}
 				} // else continue by saving the result to the desired system filepath.
 
 				// if already saved once and want to add more contexts, then don't ask for system path.
@@ -355,6 +361,52 @@ func newConfigureCommand() *cobra.Command {
 	return cmd
 }
 
+// tlsQuestions returns the optional questions for the TLS fields, they are asked when the host is served over https.
+func tlsQuestions(currentConfig *lenses.Configuration) []*survey.Question {
+	return []*survey.Question{
+		{
+			Name: "caFile",
+			Prompt: &survey.Input{
+				Message: "Certificate authority file (optional)",
+				Default: currentConfig.CAFile,
+				Help:    "This is the path of a PEM encoded certificate authority bundle, used to verify the server in addition to the system ones.",
+			},
+		},
+		{
+			Name: "certFile",
+			Prompt: &survey.Input{
+				Message: "Client certificate file (optional)",
+				Default: currentConfig.CertFile,
+				Help:    "This is the path of the PEM encoded client certificate, necessary when the server requires mutual TLS.",
+			},
+		},
+		{
+			Name: "keyFile",
+			Prompt: &survey.Input{
+				Message: "Client key file (optional)",
+				Default: currentConfig.KeyFile,
+				Help:    "This is the path of the client certificate's PEM encoded private key.",
+			},
+		},
+		{
+			Name: "serverName",
+			Prompt: &survey.Input{
+				Message: "Server name (optional)",
+				Default: currentConfig.ServerName,
+				Help:    "This overrides the host name that is used to verify the server's certificate.",
+			},
+		},
+		{
+			Name: "insecure",
+			Prompt: &survey.Confirm{
+				Message: "Skip the verification of the server's certificate?",
+				Default: currentConfig.Insecure,
+				Help:    "This is insecure, use it only for testing.",
+			},
+		},
+	}
+}
+
 func toHash(plain string) []byte {
 	h := sha256.Sum256([]byte(plain))
 	return h[:]
@@ -444,7 +496,7 @@ func decryptPassword(cfg *lenses.Configuration) {
 	cfg.Password = p
 }
 
-func newLoginCommand() *cobra.Command {
+func newLoginCommand(api lenses.SessionAPI) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:              "login",
 		Short:            "Login, generate the access token using the generated configuration via the 'configure' command. ",
@@ -458,7 +510,7 @@ func newLoginCommand() *cobra.Command {
 			}
 
 			out := cmd.OutOrStdout()
-			signedUser := client.User()
+			signedUser := api.User()
 			fmt.Fprintf(out, "Welcome %s[%s],\ntype 'help' to learn more about the available commands or 'exit' to terminate.\n",
 				signedUser.Name, strings.Join(signedUser.Roles, ", "))
 			// read the input pipe, on each read its buffer consumed, so loop 'forever' here.
@@ -556,14 +608,14 @@ func newLoginCommand() *cobra.Command {
 	return cmd
 }
 
-func newGetUserInfoCommand() *cobra.Command {
+func newGetUserInfoCommand(api lenses.SessionAPI) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:              "user",
 		Short:            "Print some information about the authenticated logged user such as the given roles given by the lenses administrator",
 		Example:          exampleString("user"),
 		TraverseChildren: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if user := client.User(); user.ID != "" {
+			if user := api.User(); user.ID != "" {
 				// if logged in using the user password, then we have those info,
 				// let's print it as well.
 				return printJSON(cmd, user)
@@ -599,14 +651,14 @@ const logoutCmdName = "logout"
 // 	return cmd
 // }
 
-func newGetLicenseInfoCommand() *cobra.Command {
+func newGetLicenseInfoCommand(api lenses.ConfigAPI) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:              "license",
 		Short:            "Print the license information for the connected lenses box",
 		Example:          exampleString("license"),
 		TraverseChildren: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			lc, err := client.GetLicenseInfo()
+			lc, err := api.GetLicenseInfo()
 			if err != nil {
 				return err
 			}
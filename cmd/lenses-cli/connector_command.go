@@ -10,12 +10,19 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// connectorsAPI is the part of the Lenses API that the connector commands depend on,
+// the connect clusters are read from the Lenses configuration.
+type connectorsAPI interface {
+	lenses.ConnectorsAPI
+	lenses.ConfigAPI
+}
+
 func init() {
-	rootCmd.AddCommand(newConnectorsCommand())
-	rootCmd.AddCommand(newConnectorGroupCommand())
+	rootCmd.AddCommand(newConnectorsCommand(api))
+	rootCmd.AddCommand(newConnectorGroupCommand(api))
 }
 
-func newConnectorsCommand() *cobra.Command {
+func newConnectorsCommand(api connectorsAPI) *cobra.Command {
 	var (
 		clusterName string
 
@@ -36,20 +43,20 @@ func newConnectorsCommand() *cobra.Command {
 			if clusterName == "*" || clusterName == "" {
 				// if * then no clusterName given,
 				// fetch the connectors from all known clusters and print them.
-				clusters, err := client.GetConnectClusters()
+				clusters, err := api.GetConnectClusters()
 				if err != nil {
 					return err
 				}
 
 				for _, cluster := range clusters {
-					clusterConnectorsNames, err := client.GetConnectors(cluster.Name)
+					clusterConnectorsNames, err := api.GetConnectors(cluster.Name)
 					if err != nil {
 						return err
 					}
 					connectorNames[cluster.Name] = append(connectorNames[cluster.Name], clusterConnectorsNames...)
 				}
 			} else {
-				names, err := client.GetConnectors(clusterName)
+				names, err := api.GetConnectors(clusterName)
 				if err != nil {
 					errResourceNotFoundMessage = fmt.Sprintf("unable to retrieve connectors, cluster with name '%s' does not exist", clusterName)
 					return err
@@ -76,12 +83,16 @@ func newConnectorsCommand() *cobra.Command {
 				return printJSON(cmd, outlineStringResults("name", names))
 			}
 
+			if isTabularOutput() {
+				return printJSON(cmd, getConnectorsState(cmd, api, connectorNames))
+			}
+
 			connectors := make(map[string][]lenses.Connector, len(connectorNames))
 
 			// else print the entire info.
 			for cluster, names := range connectorNames {
 				for _, name := range names {
-					connector, err := client.GetConnector(cluster, name)
+					connector, err := api.GetConnector(cluster, name)
 					if err != nil {
 						fmt.Fprintf(cmd.OutOrStderr(), "get connector error: %v\n", err)
 						continue
@@ -102,15 +113,57 @@ func newConnectorsCommand() *cobra.Command {
 	canPrintJSON(root)
 
 	// plugins subcommand.
-	root.AddCommand(newGetConnectorsPluginsCommand())
+	root.AddCommand(newGetConnectorsPluginsCommand(api))
 
 	// clusters subcommand.
-	root.AddCommand(newGetConnectorsClustersCommand())
+	root.AddCommand(newGetConnectorsClustersCommand(api))
 
 	return root
 }
 
-func newGetConnectorsPluginsCommand() *cobra.Command {
+// getConnectorsState returns the connectors with their state, sorted by cluster and name, it's used to print the connectors as a table.
+func getConnectorsState(cmd *cobra.Command, api connectorsAPI, connectorNames map[string][]string) []connectorState {
+	var states []connectorState
+
+	for cluster, names := range connectorNames {
+		for _, name := range names {
+			connector, err := api.GetConnector(cluster, name)
+			if err != nil {
+				fmt.Fprintf(cmd.OutOrStderr(), "get connector error: %v\n", err)
+				continue
+			}
+
+			class, _ := connector.Config["connector.class"].(string)
+			state := connectorState{
+				ClusterName: cluster,
+				Name:        name,
+				Tasks:       len(connector.Tasks),
+				Class:       class,
+			}
+
+			status, err := api.GetConnectorStatus(cluster, name)
+			if err != nil {
+				fmt.Fprintf(cmd.OutOrStderr(), "get connector status error: %v\n", err)
+			} else {
+				state.State = status.Connector.State
+				state.WorkerID = status.Connector.WorkerID
+			}
+
+			states = append(states, state)
+		}
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].ClusterName != states[j].ClusterName {
+			return states[i].ClusterName < states[j].ClusterName
+		}
+		return states[i].Name < states[j].Name
+	})
+
+	return states
+}
+
+func newGetConnectorsPluginsCommand(api connectorsAPI) *cobra.Command {
 	var clusterName string
 
 	cmd := &cobra.Command{
@@ -123,13 +176,13 @@ func newGetConnectorsPluginsCommand() *cobra.Command {
 
 			if clusterName == "*" {
 				// if * then no clusterName given, fetch the plugins from all known clusters and print them.
-				clusters, err := client.GetConnectClusters()
+				clusters, err := api.GetConnectClusters()
 				if err != nil {
 					return err
 				}
 
 				for _, cluster := range clusters {
-					clusterPlugins, err := client.GetConnectorPlugins(cluster.Name)
+					clusterPlugins, err := api.GetConnectorPlugins(cluster.Name)
 					if err != nil {
 						return err
 					}
@@ -137,7 +190,7 @@ func newGetConnectorsPluginsCommand() *cobra.Command {
 				}
 			} else {
 				var err error
-				plugins, err = client.GetConnectorPlugins(clusterName)
+				plugins, err = api.GetConnectorPlugins(clusterName)
 				if err != nil {
 					return err
 				}
@@ -160,7 +213,7 @@ func newGetConnectorsPluginsCommand() *cobra.Command {
 	return cmd
 }
 
-func newGetConnectorsClustersCommand() *cobra.Command {
+func newGetConnectorsClustersCommand(api connectorsAPI) *cobra.Command {
 	var (
 		namesOnly bool
 		noNewLine bool // matters when namesOnly is true.
@@ -172,7 +225,7 @@ func newGetConnectorsClustersCommand() *cobra.Command {
 		Example:       exampleString(`connectors clusters`),
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			clusters, err := client.GetConnectClusters()
+			clusters, err := api.GetConnectClusters()
 			if err != nil {
 				return err
 			}
@@ -208,7 +261,7 @@ func newGetConnectorsClustersCommand() *cobra.Command {
 	return cmd
 }
 
-func newConnectorGroupCommand() *cobra.Command {
+func newConnectorGroupCommand(api connectorsAPI) *cobra.Command {
 	var clusterName, name string
 
 	root := &cobra.Command{
@@ -222,7 +275,7 @@ func newConnectorGroupCommand() *cobra.Command {
 				return err
 			}
 
-			connector, err := client.GetConnector(clusterName, name)
+			connector, err := api.GetConnector(clusterName, name)
 			if err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("connector '%s:%s' does not exist", clusterName, name)
 				return err
@@ -237,22 +290,22 @@ func newConnectorGroupCommand() *cobra.Command {
 	root.Flags().StringVar(&name, "name", "", `--name="connector_name"`)
 
 	// subcommands.
-	root.AddCommand(newConnectorCreateCommand())
-	root.AddCommand(newConnectorUpdateCommand())
-	root.AddCommand(newConnectorGetConfigCommand())
-	root.AddCommand(newConnectorGetStatusCommand())
-	root.AddCommand(newConnectorPauseCommand())
-	root.AddCommand(newConnectorResumeCommand())
-	root.AddCommand(newConnectorRestartCommand())
-	root.AddCommand(newConnectorGetTasksCommand())
-	root.AddCommand(newConnectorDeleteCommand())
+	root.AddCommand(newConnectorCreateCommand(api))
+	root.AddCommand(newConnectorUpdateCommand(api))
+	root.AddCommand(newConnectorGetConfigCommand(api))
+	root.AddCommand(newConnectorGetStatusCommand(api))
+	root.AddCommand(newConnectorPauseCommand(api))
+	root.AddCommand(newConnectorResumeCommand(api))
+	root.AddCommand(newConnectorRestartCommand(api))
+	root.AddCommand(newConnectorGetTasksCommand(api))
+	root.AddCommand(newConnectorDeleteCommand(api))
 	// connector.task subcommands.
-	root.AddCommand(newConnectorTaskGroupCommand())
+	root.AddCommand(newConnectorTaskGroupCommand(api))
 
 	return root
 }
 
-func newConnectorCreateCommand() *cobra.Command {
+func newConnectorCreateCommand(api connectorsAPI) *cobra.Command {
 	var (
 		configRaw string
 		connector = lenses.CreateUpdateConnectorPayload{Config: make(lenses.ConnectorConfig)}
@@ -273,7 +326,7 @@ func newConnectorCreateCommand() *cobra.Command {
 				return err
 			}
 
-			_, err := client.CreateConnector(connector.ClusterName, connector.Name, connector.Config)
+			_, err := api.CreateConnector(connector.ClusterName, connector.Name, connector.Config)
 			if err != nil {
 				// give the exactly "low-level" message here, because we can't know if it's from the configuration
 				// or if cluster does not exist here (<- *).
@@ -295,7 +348,7 @@ func newConnectorCreateCommand() *cobra.Command {
 	return cmd
 }
 
-func newConnectorUpdateCommand() *cobra.Command { // almost the same as `newConnectorCreateCommand` but keep them separate, in future this may change.
+func newConnectorUpdateCommand(api connectorsAPI) *cobra.Command { // almost the same as `newConnectorCreateCommand` but keep them separate, in future this may change.
 	var (
 		configRaw string
 		connector = lenses.CreateUpdateConnectorPayload{Config: make(lenses.ConnectorConfig)}
@@ -317,7 +370,7 @@ func newConnectorUpdateCommand() *cobra.Command { // almost the same as `newConn
 			}
 
 			// for any case.
-			existingConnector, err := client.GetConnector(connector.ClusterName, connector.Name)
+			existingConnector, err := api.GetConnector(connector.ClusterName, connector.Name)
 			if err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("connector '%s:%s' does not exist", connector.ClusterName, connector.Name)
 				return err
@@ -329,7 +382,7 @@ func newConnectorUpdateCommand() *cobra.Command { // almost the same as `newConn
 				}
 			}
 
-			updatedConnector, err := client.UpdateConnector(connector.ClusterName, connector.Name, connector.Config)
+			updatedConnector, err := api.UpdateConnector(connector.ClusterName, connector.Name, connector.Config)
 			if err != nil {
 				return err
 			}
@@ -355,7 +408,7 @@ func newConnectorUpdateCommand() *cobra.Command { // almost the same as `newConn
 	return cmd
 }
 
-func newConnectorGetConfigCommand() *cobra.Command {
+func newConnectorGetConfigCommand(api connectorsAPI) *cobra.Command {
 	var clusterName, name string
 
 	cmd := &cobra.Command{
@@ -369,7 +422,7 @@ func newConnectorGetConfigCommand() *cobra.Command {
 				return err
 			}
 
-			cfg, err := client.GetConnectorConfig(clusterName, name)
+			cfg, err := api.GetConnectorConfig(clusterName, name)
 			if err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("unable to retrieve config, connector '%s:%s' does not exist", clusterName, name)
 				return err
@@ -387,7 +440,7 @@ func newConnectorGetConfigCommand() *cobra.Command {
 	return cmd
 }
 
-func newConnectorGetStatusCommand() *cobra.Command {
+func newConnectorGetStatusCommand(api connectorsAPI) *cobra.Command {
 	var clusterName, name string
 
 	cmd := &cobra.Command{
@@ -401,7 +454,7 @@ func newConnectorGetStatusCommand() *cobra.Command {
 				return err
 			}
 
-			cs, err := client.GetConnectorStatus(clusterName, name)
+			cs, err := api.GetConnectorStatus(clusterName, name)
 			if err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("unable to retrieve status, connector '%s:%s' does not exist", clusterName, name)
 				return err
@@ -419,7 +472,7 @@ func newConnectorGetStatusCommand() *cobra.Command {
 	return cmd
 }
 
-func newConnectorPauseCommand() *cobra.Command {
+func newConnectorPauseCommand(api connectorsAPI) *cobra.Command {
 	var clusterName, name string
 
 	cmd := &cobra.Command{
@@ -433,7 +486,7 @@ func newConnectorPauseCommand() *cobra.Command {
 				return err
 			}
 
-			if err := client.PauseConnector(clusterName, name); err != nil {
+			if err := api.PauseConnector(clusterName, name); err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("unable to pause, connector '%s:%s' does not exist", clusterName, name)
 				return err
 			}
@@ -449,7 +502,7 @@ func newConnectorPauseCommand() *cobra.Command {
 	return cmd
 }
 
-func newConnectorResumeCommand() *cobra.Command {
+func newConnectorResumeCommand(api connectorsAPI) *cobra.Command {
 	var clusterName, name string
 
 	cmd := &cobra.Command{
@@ -463,7 +516,7 @@ func newConnectorResumeCommand() *cobra.Command {
 				return err
 			}
 
-			if err := client.ResumeConnector(clusterName, name); err != nil {
+			if err := api.ResumeConnector(clusterName, name); err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("unable to resume, connector '%s:%s' does not exist", clusterName, name)
 				return err
 			}
@@ -479,7 +532,7 @@ func newConnectorResumeCommand() *cobra.Command {
 	return cmd
 }
 
-func newConnectorRestartCommand() *cobra.Command {
+func newConnectorRestartCommand(api connectorsAPI) *cobra.Command {
 	var clusterName, name string
 
 	cmd := &cobra.Command{
@@ -493,7 +546,7 @@ func newConnectorRestartCommand() *cobra.Command {
 				return err
 			}
 
-			if err := client.RestartConnector(clusterName, name); err != nil {
+			if err := api.RestartConnector(clusterName, name); err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("unable to restart, connector '%s:%s' does not exist", clusterName, name)
 				return err
 			}
@@ -509,7 +562,7 @@ func newConnectorRestartCommand() *cobra.Command {
 	return cmd
 }
 
-func newConnectorGetTasksCommand() *cobra.Command {
+func newConnectorGetTasksCommand(api connectorsAPI) *cobra.Command {
 	var clusterName, name string
 
 	cmd := &cobra.Command{
@@ -523,7 +576,7 @@ func newConnectorGetTasksCommand() *cobra.Command {
 				return err
 			}
 
-			tasksMap, err := client.GetConnectorTasks(clusterName, name)
+			tasksMap, err := api.GetConnectorTasks(clusterName, name)
 			if err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("unable to retrieve tasks, connector '%s:%s' does not exist", clusterName, name)
 				return err
@@ -541,7 +594,7 @@ func newConnectorGetTasksCommand() *cobra.Command {
 	return cmd
 }
 
-func newConnectorTaskGroupCommand() *cobra.Command {
+func newConnectorTaskGroupCommand(api connectorsAPI) *cobra.Command {
 	rootSub := &cobra.Command{
 		Use:              "task",
 		Short:            "Work with a particular connector task, see connector task --help for details",
@@ -550,13 +603,13 @@ func newConnectorTaskGroupCommand() *cobra.Command {
 		TraverseChildren: true,
 	}
 
-	rootSub.AddCommand(newConnectorGetCurrentTaskStatusCommand())
-	rootSub.AddCommand(newConnectorTaskRestartCommand())
+	rootSub.AddCommand(newConnectorGetCurrentTaskStatusCommand(api))
+	rootSub.AddCommand(newConnectorTaskRestartCommand(api))
 
 	return rootSub
 }
 
-func newConnectorGetCurrentTaskStatusCommand() *cobra.Command {
+func newConnectorGetCurrentTaskStatusCommand(api connectorsAPI) *cobra.Command {
 	var (
 		clusterName, name string
 		taskID            int
@@ -573,7 +626,7 @@ func newConnectorGetCurrentTaskStatusCommand() *cobra.Command {
 				return err
 			}
 
-			cst, err := client.GetConnectorTaskStatus(clusterName, name, taskID)
+			cst, err := api.GetConnectorTaskStatus(clusterName, name, taskID)
 			if err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("task does not exist")
 				return err
@@ -594,7 +647,7 @@ func newConnectorGetCurrentTaskStatusCommand() *cobra.Command {
 	return cmd
 }
 
-func newConnectorTaskRestartCommand() *cobra.Command {
+func newConnectorTaskRestartCommand(api connectorsAPI) *cobra.Command {
 	var (
 		clusterName, name string
 		taskID            int
@@ -611,7 +664,7 @@ func newConnectorTaskRestartCommand() *cobra.Command {
 				return err
 			}
 
-			if err := client.RestartConnectorTask(clusterName, name, taskID); err != nil {
+			if err := api.RestartConnectorTask(clusterName, name, taskID); err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("task does not exist")
 				return err
 			}
@@ -629,7 +682,7 @@ func newConnectorTaskRestartCommand() *cobra.Command {
 	return cmd
 }
 
-func newConnectorDeleteCommand() *cobra.Command {
+func newConnectorDeleteCommand(api connectorsAPI) *cobra.Command {
 	var clusterName, name string
 
 	cmd := &cobra.Command{
@@ -643,7 +696,7 @@ func newConnectorDeleteCommand() *cobra.Command {
 				return err
 			}
 
-			if err := client.DeleteConnector(clusterName, name); err != nil {
+			if err := api.DeleteConnector(clusterName, name); err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("unable to delete, connector '%s:%s' does not exist", clusterName, name)
 				return err
 			}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/landoop/lenses-go"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(newDiffCommand(openContext))
+}
+
+// errDiff is returned by the `diff` when the resources of the two contexts are different,
+// the process exits with a non-zero code, like the `apply --dry-run` does on drift.
+var errDiff = errors.New("the resources of the contexts are different")
+
+const (
+	diffFormatUnified = "unified"
+	diffFormatJSON    = "json"
+)
+
+// openContext opens a connection to the Lenses server of a configured context,
+// the `diff` command uses it to connect to more than one contexts at the same time.
+// The returned "logout" ends the session that the connection opened, if it logged in with the context's credentials.
+func openContext(name string) (api lenses.API, logout func() error, err error) {
+	cfg, ok := configManager.config.Contexts[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown context '%s'", name)
+	}
+
+	contextConfig := *cfg
+	contextConfig.FormatHost()
+	client, err := lenses.OpenConnection(contextConfig, clientOptions()...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to connect to the context '%s': %v", name, err)
+	}
+
+	logout = func() error {
+		if contextConfig.Token != "" {
+			return nil // the context's own token, it's not ours to revoke.
+		}
+
+		return client.Logout()
+	}
+
+	return client, logout, nil
+}
+
+func newDiffCommand(open func(context string) (api lenses.API, logout func() error, err error)) *cobra.Command {
+	var from, to, format string
+
+	cmd := &cobra.Command{
+		Use:   "diff [kinds]",
+		Short: "Compare the resources of two contexts, i.e dev and prod",
+		Long: "Compares the resources (" + strings.Join(resourceKindNames(), ", ") + ") of two contexts, all kinds if none given, " +
+			"and prints the differences, exits with a non-zero code if there are any.",
+		Example:          exampleString(`diff --from=dev --to=prod or diff --from=dev --to=prod Topic Connector --format=json`),
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkRequiredFlags(cmd, flags{"from": from, "to": to}); err != nil {
+				return err
+			}
+
+			if format != diffFormatUnified && format != diffFormatJSON {
+				return fmt.Errorf("unknown format '%s', available formats: %s, %s", format, diffFormatUnified, diffFormatJSON)
+			}
+
+			kinds := resourceKinds
+			if len(args) > 0 {
+				kinds = nil
+				for _, name := range args {
+					kind := findResourceKind(name)
+					if kind == nil {
+						// accept the plural too, i.e "topics".
+						kind = findResourceKind(strings.TrimSuffix(name, "s"))
+					}
+					if kind == nil {
+						return fmt.Errorf("unknown kind '%s', available kinds: %s", name, strings.Join(resourceKindNames(), ", "))
+					}
+					kinds = append(kinds, kind)
+				}
+			}
+
+			fromAPI, logoutFrom, err := open(from)
+			if err != nil {
+				return err
+			}
+			defer logoutFrom()
+
+			toAPI, logoutTo, err := open(to)
+			if err != nil {
+				return err
+			}
+			defer logoutTo()
+
+			diffs, err := diffContexts(fromAPI, toAPI, kinds)
+			if err != nil {
+				return err
+			}
+
+			if format == diffFormatJSON {
+				if diffs == nil {
+					diffs = []resourceDiff{} // print an empty list instead of null.
+				}
+				err = printJSON(cmd, diffs)
+			} else {
+				err = printUnifiedDiff(cmd.OutOrStdout(), from, to, diffs)
+			}
+
+			if err == nil && len(diffs) > 0 {
+				err = errDiff
+			}
+
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "--from=dev the context to compare from")
+	cmd.Flags().StringVar(&to, "to", "", "--to=prod the context to compare to")
+	cmd.Flags().StringVar(&format, "format", diffFormatUnified, "--format=json the output format: "+diffFormatUnified+"|"+diffFormatJSON)
+
+	return cmd
+}
+
+const (
+	diffStatusAdded   = "added"
+	diffStatusRemoved = "removed"
+	diffStatusChanged = "changed"
+)
+
+// resourceDiff is a resource that is different between two contexts,
+// it is "added" if it exists only on the "to" context, "removed" if it exists only on the "from" one.
+type resourceDiff struct {
+	Kind   string              `json:"kind"`
+	ID     string              `json:"id"`
+	Status string              `json:"status"`
+	Fields []resourceFieldDiff `json:"fields"`
+}
+
+// resourceFieldDiff is a field of a resource that is different, the "from" or "to" is empty if the field is missing.
+type resourceFieldDiff struct {
+	Field string `json:"field"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+}
+
+// diffContexts compares the normalized resources of the kinds, see `listResources`,
+// the results are sorted by the order of the kinds and then by the resources' identifiers.
+func diffContexts(from, to lenses.API, kinds []*resourceKind) ([]resourceDiff, error) {
+	var diffs []resourceDiff
+
+	for _, kind := range kinds {
+		fromResources, err := listResources(from, kind)
+		if err != nil {
+			return nil, err
+		}
+
+		toResources, err := listResources(to, kind)
+		if err != nil {
+			return nil, err
+		}
+
+		ids := sortedResourceIDs(fromResources)
+		for _, id := range sortedResourceIDs(toResources) {
+			if _, exists := fromResources[id]; !exists {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			fromResource, inFrom := fromResources[id]
+			toResource, inTo := toResources[id]
+
+			d := resourceDiff{Kind: kind.name, ID: id, Status: diffStatusChanged}
+			if !inFrom {
+				d.Status = diffStatusAdded
+			} else if !inTo {
+				d.Status = diffStatusRemoved
+			}
+
+			if d.Fields, err = diffAllFields(fromResource, toResource); err != nil {
+				return nil, err
+			}
+
+			if len(d.Fields) > 0 {
+				diffs = append(diffs, d)
+			}
+		}
+	}
+
+	return diffs, nil
+}
+
+// diffAllFields returns the fields that are different or missing on any of the "from" and "to",
+// unlike the `diffResources`, the empty fields are compared too, a nil resource has no fields.
+func diffAllFields(from, to interface{}) ([]resourceFieldDiff, error) {
+	fromFields, toFields := make(map[string]string), make(map[string]string)
+
+	var err error
+	if from != nil {
+		if fromFields, err = flattenResource(from); err != nil {
+			return nil, err
+		}
+	}
+
+	if to != nil {
+		if toFields, err = flattenResource(to); err != nil {
+			return nil, err
+		}
+	}
+
+	fields := make([]string, 0, len(fromFields))
+	for field := range fromFields {
+		fields = append(fields, field)
+	}
+	for field := range toFields {
+		if _, exists := fromFields[field]; !exists {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	var diff []resourceFieldDiff
+	for _, field := range fields {
+		fromValue, inFrom := fromFields[field]
+		toValue, inTo := toFields[field]
+		if inFrom == inTo && fromValue == toValue {
+			continue
+		}
+
+		diff = append(diff, resourceFieldDiff{Field: field, From: fromValue, To: toValue})
+	}
+
+	return diff, nil
+}
+
+// printUnifiedDiff prints the differences like a unified diff, a hunk per resource, i.e
+// "@@ Topic reddit_posts @@\n-partitions: 3\n+partitions: 6\n".
+func printUnifiedDiff(w io.Writer, from, to string, diffs []resourceDiff) error {
+	if len(diffs) == 0 {
+		_, err := fmt.Fprintf(w, "No differences between '%s' and '%s'.\n", from, to)
+		return err
+	}
+
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", from, to)
+	for _, d := range diffs {
+		header := d.Kind + " " + d.ID
+		if d.Status != diffStatusChanged {
+			header += " (" + d.Status + ")"
+		}
+		fmt.Fprintf(w, "@@ %s @@\n", header)
+
+		for _, field := range d.Fields {
+			if d.Status != diffStatusAdded && (field.From != "" || d.Status == diffStatusRemoved) {
+				fmt.Fprintf(w, "-%s: %s\n", field.Field, field.From)
+			}
+			if d.Status != diffStatusRemoved && (field.To != "" || d.Status == diffStatusAdded) {
+				fmt.Fprintf(w, "+%s: %s\n", field.Field, field.To)
+			}
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%d added, %d removed, %d changed.\n",
+		countDiffs(diffs, diffStatusAdded), countDiffs(diffs, diffStatusRemoved), countDiffs(diffs, diffStatusChanged))
+	return err
+}
+
+func countDiffs(diffs []resourceDiff, status string) (n int) {
+	for _, d := range diffs {
+		if d.Status == status {
+			n++
+		}
+	}
+
+	return
+}
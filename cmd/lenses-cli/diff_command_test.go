@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/landoop/lenses-go"
+	"github.com/landoop/lenses-go/lensestest"
+)
+
+func TestDiffCommand(t *testing.T) {
+	contexts := make(map[string]lenses.API)
+	for _, name := range []string{"dev", "prod"} {
+		srv := lensestest.NewServer()
+		defer srv.Close()
+
+		client, err := lenses.OpenConnection(srv.Configuration())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = client.CreateTopic("reddit_posts", 1, 1, lenses.KV{"cleanup.policy": "compact"}); err != nil {
+			t.Fatal(err)
+		}
+
+		contexts[name] = client
+	}
+
+	// logouts are the contexts that the command logged out, in order.
+	var logouts []string
+	open := func(name string) (lenses.API, func() error, error) {
+		if api, ok := contexts[name]; ok {
+			return api, func() error {
+				logouts = append(logouts, name)
+				return nil
+			}, nil
+		}
+		return nil, nil, fmt.Errorf("unknown context '%s'", name)
+	}
+
+	diff := func(args ...string) (string, error) {
+		out := new(bytes.Buffer)
+		cmd := newDiffCommand(open)
+		cmd.SetOutput(out)
+		cmd.SetArgs(append([]string{"--from", "dev", "--to", "prod"}, args...))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	if out, err := diff("topics"); err != nil {
+		t.Fatalf("expected no differences but got: %v\n%s", err, out)
+	}
+
+	if expected, got := "prod,dev", strings.Join(logouts, ","); expected != got {
+		t.Fatalf("expected both contexts to be logged out, %s, but got %s", expected, got)
+	}
+
+	logouts = nil
+	if _, err := diff("topics", "--to", "staging"); err == nil {
+		t.Fatal("expected the unknown context to fail")
+	}
+
+	if expected, got := "dev", strings.Join(logouts, ","); expected != got {
+		t.Fatalf("expected the opened context to be logged out, %s, but got %s", expected, got)
+	}
+
+	if err := contexts["dev"].CreateTopic("reddit_comments", 1, 1, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := contexts["prod"].UpdateTopic("reddit_posts", []lenses.KV{{"key": "cleanup.policy", "value": "delete"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := diff("Topic")
+	if err != errDiff {
+		t.Fatalf("expected the diff error but got: %v\n%s", err, out)
+	}
+
+	expected := "--- dev\n+++ prod\n" +
+		"@@ Topic reddit_comments (removed) @@\n-partitions: 1\n-replication: 1\n-topicName: reddit_comments\n" +
+		"@@ Topic reddit_posts @@\n-configs.cleanup.policy: compact\n+configs.cleanup.policy: delete\n" +
+		"0 added, 1 removed, 1 changed.\n"
+	if !strings.HasPrefix(out, expected) {
+		t.Fatalf("expected diff:\n%s\nbut got:\n%s", expected, out)
+	}
+
+	if out, _ = diff("Topic", "--format", "json"); !strings.Contains(out, `"status": "removed"`) {
+		t.Fatalf("expected json diff but got:\n%s", out)
+	}
+}
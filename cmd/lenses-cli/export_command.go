@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/landoop/lenses-go"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	rootCmd.AddCommand(newExportCommand(api))
+}
+
+func newExportCommand(api lenses.API) *cobra.Command {
+	var (
+		dir              string
+		include, exclude []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the resources to manifests, one yaml file per resource, that can be loaded by the apply command",
+		Long: "Exports the resources (" + strings.Join(resourceKindNames(), ", ") + ") to <dir>/<kind>/<name>.yml, " +
+			"the filters are matched against the kind, i.e 'Topic', or the kind and the name, i.e 'Topic/reddit_*'.",
+		Example:          exampleString(`export --dir=./manifests or export --dir=./manifests --include="Topic" --include="Connector/dev/*" --exclude="Topic/_*"`),
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkRequiredFlags(cmd, flags{"dir": dir}); err != nil {
+				return err
+			}
+
+			filter, err := newResourceFilter(include, exclude)
+			if err != nil {
+				return err
+			}
+
+			var exported int
+			for _, kind := range resourceKinds {
+				if !filter.matchKind(kind.name) {
+					continue
+				}
+
+				resources, err := listResources(api, kind)
+				if err != nil {
+					return err
+				}
+
+				files := make(map[string]string) // filename:id, to catch the sanitized names that collide.
+				for _, id := range sortedResourceIDs(resources) {
+					if !filter.match(kind.name, id) {
+						continue
+					}
+
+					filename := resourceFilename(kind, id)
+					for n := 2; files[filename] != ""; n++ {
+						filename = resourceFilename(kind, fmt.Sprintf("%s-%d", id, n))
+					}
+					files[filename] = id
+
+					if err = writeManifest(filepath.Join(dir, filename), kind, resources[id]); err != nil {
+						return err
+					}
+					exported++
+				}
+			}
+
+			return echo(cmd, "Exported %d resources to %s", exported, dir)
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", "", "--dir=./manifests the directory to write the manifests to")
+	cmd.Flags().StringSliceVar(&include, "include", nil, `--include="Topic/reddit_*" export only the matching resources, can be repeated`)
+	cmd.Flags().StringSliceVar(&exclude, "exclude", nil, `--exclude="Topic/_*" skip the matching resources, can be repeated`)
+	canBeSilent(cmd)
+
+	return cmd
+}
+
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// resourceFilename returns the manifest's file of a resource, relative to the export directory, i.e "topics/reddit_posts.yml".
+func resourceFilename(kind *resourceKind, id string) string {
+	return filepath.Join(strings.ToLower(kind.name)+"s", unsafeFilenameChars.ReplaceAllString(id, "_")+".yml")
+}
+
+// writeManifest writes the resource as yaml, the "kind" field comes first.
+func writeManifest(filename string, kind *resourceKind, resource interface{}) error {
+	b, err := yaml.Marshal(resource)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+
+	contents := append([]byte(fmt.Sprintf("%s: %s\n", manifestKindField, kind.name)), b...)
+	return ioutil.WriteFile(filename, contents, 0644)
+}
+
+// resourceFilter matches the resources against the include and exclude patterns,
+// a pattern is a kind, i.e "Topic", or a kind and an identifier, i.e "Topic/reddit_*", the "*" matches any text.
+type resourceFilter struct {
+	include, exclude []*regexp.Regexp
+	// includeKinds are the kinds' parts of the include patterns.
+	includeKinds []*regexp.Regexp
+}
+
+func newResourceFilter(include, exclude []string) (*resourceFilter, error) {
+	f := new(resourceFilter)
+
+	for _, pattern := range include {
+		expr, err := globToRegexp(pattern)
+		if err != nil {
+			return nil, err
+		}
+		f.include = append(f.include, expr)
+
+		kindPattern := strings.SplitN(pattern, "/", 2)[0]
+		kindExpr, err := globToRegexp(kindPattern)
+		if err != nil {
+			return nil, err
+		}
+		f.includeKinds = append(f.includeKinds, kindExpr)
+	}
+
+	for _, pattern := range exclude {
+		expr, err := globToRegexp(pattern)
+		if err != nil {
+			return nil, err
+		}
+		f.exclude = append(f.exclude, expr)
+	}
+
+	return f, nil
+}
+
+// matchKind reports whether any resource of the kind can be matched, so the kind should be read from the server.
+func (f *resourceFilter) matchKind(kind string) bool {
+	for _, expr := range f.exclude {
+		if expr.MatchString(kind) {
+			return false
+		}
+	}
+
+	if len(f.includeKinds) == 0 {
+		return true
+	}
+
+	for _, expr := range f.includeKinds {
+		if expr.MatchString(kind) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *resourceFilter) match(kind, id string) bool {
+	name := kind + "/" + id
+
+	for _, expr := range f.exclude {
+		if expr.MatchString(kind) || expr.MatchString(name) {
+			return false
+		}
+	}
+
+	if len(f.include) == 0 {
+		return true
+	}
+
+	for _, expr := range f.include {
+		if expr.MatchString(kind) || expr.MatchString(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	return regexp.Compile("^(?i:" + strings.Join(parts, ".*") + ")$")
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/landoop/lenses-go"
+	"github.com/landoop/lenses-go/lensestest"
+)
+
+func TestExportCommand(t *testing.T) {
+	srv := lensestest.NewServer()
+	defer srv.Close()
+
+	client, err := lenses.OpenConnection(srv.Configuration())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = client.CreateTopic("reddit_posts", 1, 3, lenses.KV{"cleanup.policy": "compact"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = client.CreateTopic("_internal", 1, 1, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, schema := range []string{
+		`{"type":"record","name":"post","fields":[{"name":"id","type":"string"}]}`,
+		`{"type":"record","name":"post","fields":[{"name":"id","type":"string"},{"name":"title","type":["null","string"],"default":null}]}`,
+	} {
+		if _, err = client.RegisterSchema("reddit_posts-value", schema); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = client.UpdateSubjectCompatibilityLevel("reddit_posts-value", lenses.CompatibilityLevelFull); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = client.CreateOrUpdateQuotaForUserClient("john", "app", lenses.QuotaConfig{ProducerByteRate: "1000"}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "lenses-cli-export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cmd := newExportCommand(client)
+	cmd.SetOutput(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--dir", dir, "--exclude", "Topic/_*"})
+	if err = cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	var files []string
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			rel, _ := filepath.Rel(dir, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return err
+	})
+
+	if expected, got := "quotas/USERCLIENT_john_app.yml,schemas/reddit_posts-value.yml,topics/reddit_posts.yml", strings.Join(files, ","); expected != got {
+		t.Fatalf("expected files %s but got %s", expected, got)
+	}
+
+	// the exported manifests should match the live state.
+	applyCmd := newApplyCommand(client)
+	out := new(bytes.Buffer)
+	applyCmd.SetOutput(out)
+	applyCmd.SetArgs([]string{"-f", dir, "--dry-run"})
+	if err = applyCmd.Execute(); err != nil {
+		t.Fatalf("expected no drift but got: %v\n%s", err, out)
+	}
+}
@@ -22,10 +22,18 @@ var (
 	// It's not a global flag, but it's a common one, all commands that return results
 	// set that via command flag binding.
 	jmespathQuery string
+	// outputFormat is the format of the results, see `printOutput`.
+	// Defaults to "json".
+	// It's not a global flag, but it's a common one, all commands that return results
+	// set that via command flag binding.
+	outputFormat = outputJSON
 
 	jsonFlagSet = newFlagGroup("flagset.json", func(flags *pflag.FlagSet) {
 		flags.BoolVar(&noPretty, "no-pretty", noPretty, "disable the pretty format for JSON output of commands (default false).")
 		flags.StringVarP(&jmespathQuery, "query", "q", "", "a jmespath query expression. This allows for querying the JSON output of commands")
+		flags.StringVarP(&outputFormat, "output", "o", outputFormat, "the output format of the results: "+strings.Join(outputFormats, "|"))
+		flags.StringVar(&templateText, "template", "", `a go template to print the results with, i.e --template='{{range .}}{{.TopicName}}{{"\n"}}{{end}}'`)
+		flags.StringVar(&templateFile, "template-file", "", "--template-file=./report.tmpl same as --template but the template is loaded from a file")
 	})
 )
 
@@ -56,7 +64,25 @@ func returnJSON(fn func() (interface{}, error)) func(cmd *cobra.Command, args []
 	}
 }
 
+// printJSON prints the "v" as json, or in the format of the `--output` flag or through the `--template`,
+// the `--query` is applied before the formatting.
 func printJSON(cmd *cobra.Command, v interface{}) error {
+	if outputFormat != outputJSON || hasTemplate() {
+		if jmespathQuery != "" {
+			result, err := jmespath.Search(jmespathQuery, v)
+			if err != nil {
+				return err
+			}
+			v = result
+		}
+
+		if hasTemplate() {
+			return printTemplate(cmd.OutOrStdout(), v)
+		}
+
+		return printOutput(cmd.OutOrStdout(), v)
+	}
+
 	rawJSON, err := toJSON(v, !noPretty, jmesQuery(jmespathQuery, v))
 	if err != nil {
 		return err
@@ -71,6 +97,30 @@ func printJSON(cmd *cobra.Command, v interface{}) error {
 	return err
 }
 
+// newStreamPrinter returns the printer of the results that are received one by one, i.e the `sql`'s records,
+// the tabular outputs print them as the rows of one table, the rest print each one through the `printJSON`.
+// The "flush" prints the buffered rows, it should be called when the stream is finished.
+func newStreamPrinter(cmd *cobra.Command) (printValue func(v interface{}) error, flush func() error) {
+	if !isTabularOutput() || hasTemplate() {
+		return func(v interface{}) error { return printJSON(cmd, v) }, func() error { return nil }
+	}
+
+	rw := newRowsWriter(cmd.OutOrStdout())
+	printValue = func(v interface{}) error {
+		if jmespathQuery != "" {
+			result, err := jmespath.Search(jmespathQuery, v)
+			if err != nil {
+				return err
+			}
+			v = result
+		}
+
+		return rw.Write(v)
+	}
+
+	return printValue, rw.Flush
+}
+
 type transformer func([]byte, bool) ([]byte, error)
 
 func toJSON(v interface{}, pretty bool, transformers ...transformer) ([]byte, error) {
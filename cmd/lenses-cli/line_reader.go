@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// errInterrupted is returned by the `lineReader#readLine` when the user presses Ctrl+C.
+var errInterrupted = errors.New("interrupted")
+
+// lineCompleter returns the candidates to replace the last word of the "line" with,
+// the "word" is the part of the line that the candidates replace.
+type lineCompleter func(line string) (word string, candidates []string)
+
+// lineReader reads the lines of an interactive shell, i.e the `sql shell`.
+// On a terminal it edits the line in raw mode, with history navigation (up and down arrows)
+// and completion (tab), otherwise it reads whole lines, i.e from a pipe.
+type lineReader struct {
+	in       *bufio.Reader
+	out      io.Writer
+	fd       uintptr
+	terminal bool
+	complete lineCompleter
+
+	history []string
+}
+
+func newLineReader(in io.Reader, out io.Writer, complete lineCompleter) *lineReader {
+	r := &lineReader{in: bufio.NewReader(in), out: out, complete: complete}
+	if f, ok := in.(*os.File); ok {
+		r.fd = f.Fd()
+		r.terminal = isTerminal(r.fd)
+	}
+
+	return r
+}
+
+// addHistory appends an entry to the history, the consecutive duplicates are skipped.
+func (r *lineReader) addHistory(entry string) {
+	if entry == "" || (len(r.history) > 0 && r.history[len(r.history)-1] == entry) {
+		return
+	}
+
+	r.history = append(r.history, entry)
+}
+
+// readLine prints the prompt and returns the line that the user entered, without the new line.
+// It returns io.EOF on Ctrl+D, or at the end of the input, and `errInterrupted` on Ctrl+C.
+func (r *lineReader) readLine(prompt string) (string, error) {
+	if !r.terminal {
+		return r.readPlainLine(prompt)
+	}
+
+	restore, err := makeRaw(r.fd)
+	if err != nil {
+		return r.readPlainLine(prompt)
+	}
+	defer restore()
+
+	e := &lineEdit{r: r, prompt: prompt, historyIndex: len(r.history)}
+	e.redraw()
+
+	for {
+		ch, _, err := r.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch ch {
+		case '\r', '\n':
+			fmt.Fprint(r.out, "\n")
+			return string(e.buf), nil
+		case 3: // Ctrl+C.
+			fmt.Fprint(r.out, "^C\n")
+			return "", errInterrupted
+		case 4: // Ctrl+D.
+			if len(e.buf) == 0 {
+				fmt.Fprint(r.out, "\n")
+				return "", io.EOF
+			}
+			e.delete()
+		case 127, 8: // Backspace.
+			if e.pos > 0 {
+				e.pos--
+				e.delete()
+			}
+		case 1: // Ctrl+A.
+			e.pos = 0
+		case 5: // Ctrl+E.
+			e.pos = len(e.buf)
+		case 21: // Ctrl+U.
+			e.buf, e.pos = e.buf[e.pos:], 0
+		case '\t':
+			e.completeWord()
+		case 27: // escape sequences, i.e the arrows.
+			e.escape()
+		default:
+			if ch >= 32 {
+				e.insert(ch)
+			}
+		}
+
+		e.redraw()
+	}
+}
+
+func (r *lineReader) readPlainLine(prompt string) (string, error) {
+	if r.terminal {
+		fmt.Fprint(r.out, prompt)
+	}
+
+	line, err := r.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// lineEdit is the state of the line that is being edited in raw mode.
+type lineEdit struct {
+	r      *lineReader
+	prompt string
+	buf    []rune
+	pos    int
+
+	historyIndex int
+	// pending is the line that was being edited before the history navigation.
+	pending []rune
+}
+
+func (e *lineEdit) insert(runes ...rune) {
+	buf := make([]rune, 0, len(e.buf)+len(runes))
+	buf = append(buf, e.buf[:e.pos]...)
+	buf = append(buf, runes...)
+	e.buf = append(buf, e.buf[e.pos:]...)
+	e.pos += len(runes)
+}
+
+// delete removes the character under the cursor.
+func (e *lineEdit) delete() {
+	if e.pos < len(e.buf) {
+		e.buf = append(e.buf[:e.pos], e.buf[e.pos+1:]...)
+	}
+}
+
+func (e *lineEdit) escape() {
+	ch, _, err := e.r.in.ReadRune()
+	if err != nil || (ch != '[' && ch != 'O') {
+		return
+	}
+
+	if ch, _, err = e.r.in.ReadRune(); err != nil {
+		return
+	}
+
+	switch ch {
+	case 'A':
+		e.historyMove(-1)
+	case 'B':
+		e.historyMove(1)
+	case 'C':
+		if e.pos < len(e.buf) {
+			e.pos++
+		}
+	case 'D':
+		if e.pos > 0 {
+			e.pos--
+		}
+	case 'H':
+		e.pos = 0
+	case 'F':
+		e.pos = len(e.buf)
+	case '3': // Delete, "\x1b[3~".
+		if next, _, err := e.r.in.ReadRune(); err == nil && next == '~' {
+			e.delete()
+		}
+	}
+}
+
+func (e *lineEdit) historyMove(delta int) {
+	history := e.r.history
+	index := e.historyIndex + delta
+	if index < 0 || index > len(history) {
+		return
+	}
+
+	if e.historyIndex == len(history) {
+		e.pending = e.buf
+	}
+
+	e.historyIndex = index
+	if index == len(history) {
+		e.buf = e.pending
+	} else {
+		e.buf = []rune(history[index])
+	}
+	e.pos = len(e.buf)
+}
+
+// completeWord completes the word before the cursor, if there are more than one candidates
+// it completes their common prefix or, if there is not any, it prints them under the line.
+func (e *lineEdit) completeWord() {
+	if e.r.complete == nil {
+		return
+	}
+
+	word, candidates := e.r.complete(string(e.buf[:e.pos]))
+	if len(candidates) == 0 {
+		return
+	}
+
+	if len(candidates) == 1 {
+		e.replaceWord(word, candidates[0]+" ")
+		return
+	}
+
+	if prefix := commonPrefix(candidates); len(prefix) > len(word) {
+		e.replaceWord(word, prefix)
+		return
+	}
+
+	fmt.Fprintf(e.r.out, "\n%s\n", strings.Join(candidates, "  "))
+}
+
+// replaceWord replaces the "word" before the cursor, the candidates may differ in case, i.e "sel" with "SELECT".
+func (e *lineEdit) replaceWord(word, replacement string) {
+	n := len([]rune(word))
+	e.buf = append(e.buf[:e.pos-n:e.pos-n], e.buf[e.pos:]...)
+	e.pos -= n
+	e.insert([]rune(replacement)...)
+}
+
+func (e *lineEdit) redraw() {
+	fmt.Fprintf(e.r.out, "\r%s%s\x1b[K", e.prompt, string(e.buf))
+	if back := len(e.buf) - e.pos; back > 0 {
+		fmt.Fprintf(e.r.out, "\x1b[%dD", back)
+	}
+}
+
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, v := range values[1:] {
+		for !strings.HasPrefix(v, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	return prefix
+}
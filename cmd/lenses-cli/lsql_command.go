@@ -2,9 +2,14 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/landoop/lenses-go"
@@ -13,10 +18,13 @@ import (
 )
 
 func init() {
-	rootCmd.AddCommand(newLSQLCommand())
+	cmd := newLSQLCommand(api)
+	cmd.AddCommand(newLSQLShellCommand(api))
+	cmd.AddCommand(newLSQLExportCommand(api))
+	rootCmd.AddCommand(cmd)
 }
 
-func newLSQLCommand() *cobra.Command {
+func newLSQLCommand(api lenses.LSQLAPI) *cobra.Command {
 	// Idea:
 	// Maybe in the (near) future give a something like a --details flag in order
 	// to print the whole information for the lsql validation(line,column and error message),
@@ -30,6 +38,8 @@ func newLSQLCommand() *cobra.Command {
 		withOffsets bool
 		// only on execution: if not empty and > "1s" the client will accept LSQLStats every `statsEvery` duration, therefore they will be visible to the output.
 		statsEvery time.Duration
+		// only on execution: if not empty the query continues from the offsets of the previous run that are saved to this file.
+		checkpointFile string
 	)
 
 	rootSub := &cobra.Command{
@@ -38,43 +48,14 @@ func newLSQLCommand() *cobra.Command {
 		Example:       exampleString(`sql --offsets --stats=2s "SELECT * FROM reddit_posts LIMIT 50"`),
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
-			var query []byte
-
-			// argument has a priority.
-			if n := len(args); n == 1 {
-				query, err = tryReadFileContents(args[0])
-				if err != nil {
-					return err
-				}
-			} else if n == 0 {
-				// read from input pipe, no argument given.
-				has, b, err := readInPipe()
-				if err != nil {
-					return fmt.Errorf("io pipe: %v", err)
-				}
-
-				if !has {
-					// no data to read from.
-					return fmt.Errorf("sql argument is missing and input pipe has no data to read from")
-				}
-
-				query = b
-			} else {
-				// argument and input pipe are missing.
-				return fmt.Errorf("sql argument is the only one required argument")
-			}
-
-			if len(query) == 0 {
-				return fmt.Errorf("query should not be empty")
+			query, err := readQueryArgument(args)
+			if err != nil {
+				return err
 			}
 
-			// replace all new line with spaces and trim any trailing space.
-			query = bytes.Replace(query, []byte("\n"), []byte(" "), -1)
-			query = bytes.TrimSpace(query)
-
 			// if --validate then validate, not execute.
 			if validate {
-				validation, err := client.ValidateLSQL(string(query))
+				validation, err := api.ValidateLSQL(string(query))
 				if err != nil {
 					return err
 				}
@@ -91,14 +72,42 @@ func newLSQLCommand() *cobra.Command {
 				return nil
 			}
 
+			sql := string(query)
+			var checkpoint *lenses.LSQLCheckpoint
+			if checkpointFile != "" {
+				if checkpoint, err = lenses.LoadLSQLCheckpoint(checkpointFile, sql); err != nil {
+					return err
+				}
+
+				if sql, err = checkpoint.Rewrite(); err != nil {
+					return err
+				}
+			}
+
+			// the summary that is printed if the query is interrupted, the server does not send its stop then.
+			var summary lenses.LSQLStop
+
+			// the tabular outputs print the records as the rows of one table, see `newStreamPrinter`.
+			printRecord, flushRecords := newStreamPrinter(cmd)
+
 			recordHandler := func(r lenses.LSQLRecord) error {
+				summary.TotalRecords++
+				summary.Size += int64(len(r.Key) + len(r.Value))
+
 				b := []byte(r.Value) // we care for the value here, which is a json raw string.
 				var in interface{}
 				if errR := json.Unmarshal(b, &in); errR != nil {
 					return errR // fail on first error.
 				}
 
-				return printJSON(cmd, in) // if != nil then it will exit(1) and print the error.
+				if errR := printRecord(in); errR != nil {
+					return errR // if != nil then it will exit(1) and print the error.
+				}
+
+				if checkpoint != nil {
+					checkpoint.Track(r)
+				}
+				return nil
 			}
 
 			stopHandler := func(stopRecord lenses.LSQLStop) error {
@@ -133,14 +142,16 @@ func newLSQLCommand() *cobra.Command {
 				}
 				*/
 				// here we stop but it's not an error, so we can't return a non-nil error.
+				flushRecords()
 				fmt.Fprintln(cmd.OutOrStdout(), "Stop")
 				printJSON(cmd, stopRecord)
 				return nil
 			}
 
 			stopErrHandler := func(errRecord lenses.LSQLError) error {
+				flushRecords()
 				fmt.Fprintln(cmd.OutOrStdout(), "Stop:Error")
-				// this error will be catched by the err = client.LSQL(...) below, same with the rest of the handlers.
+				// this error will be catched by the err = api.LSQL(...) below, same with the rest of the handlers.
 				return fmt.Errorf(errRecord.Message)
 			}
 
@@ -166,31 +177,131 @@ func newLSQLCommand() *cobra.Command {
 				stopHandler = nil
 			}
 
-			return client.LSQL(string(query), withOffsets, statsEvery, recordHandler, stopHandler, stopErrHandler, statsHandler)
+			// on Ctrl+C the query is canceled on the server side too, otherwise it keeps running until its max.time.
+			ctx, cancel := contextWithSignals(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			err = api.LSQLContext(ctx, sql, withOffsets, statsEvery, recordHandler, stopHandler, stopErrHandler, statsHandler)
+			if flushErr := flushRecords(); flushErr != nil && err == nil {
+				err = flushErr
+			}
+
+			if checkpoint != nil {
+				// save the offsets of the printed records even on failure or interrupt, the next run continues after them.
+				if saveErr := checkpoint.Save(checkpointFile); saveErr != nil && err == nil {
+					err = saveErr
+				}
+			}
+
+			if ctx.Err() != nil {
+				summary.IsStopped = true
+				fmt.Fprintln(cmd.OutOrStdout(), "Stop")
+				printJSON(cmd, summary)
+				return queryInterruptedError(err)
+			}
+
+			return err
 		},
 	}
 
 	rootSub.Flags().BoolVar(&validate, "validate", false, "runs query validation only") // if --validate exists in the flags then it's true.
 	rootSub.Flags().BoolVar(&withOffsets, "offsets", false, "the stop output will contain the 'offsets' information as well")
 	rootSub.Flags().DurationVar(&statsEvery, "stats", 0, "--stats=2s if passed the client will accept stats records every 'stats' duration, therefore they will be visible to the output")
+	rootSub.Flags().StringVar(&checkpointFile, "checkpoint", "", "--checkpoint=./reddit_posts.checkpoint.json continue from the last offsets of the previous run and save the new ones to the file, for incremental reads")
 	canPrintJSON(rootSub)
 
 	rootSub.AddCommand(
-		newGetRunningQueriesCommand(),
-		newCancelQueryCommand(),
+		newGetRunningQueriesCommand(api),
+		newCancelQueryCommand(api),
 	)
 
 	return rootSub
 }
 
-func newGetRunningQueriesCommand() *cobra.Command {
+// readQueryArgument returns the query of the "args", it can be a file too, or, if missing, of the input pipe.
+func readQueryArgument(args []string) (query []byte, err error) {
+	// argument has a priority.
+	if n := len(args); n == 1 {
+		query, err = tryReadFileContents(args[0])
+		if err != nil {
+			return nil, err
+		}
+	} else if n == 0 {
+		// read from input pipe, no argument given.
+		has, b, err := readInPipe()
+		if err != nil {
+			return nil, fmt.Errorf("io pipe: %v", err)
+		}
+
+		if !has {
+			// no data to read from.
+			return nil, fmt.Errorf("sql argument is missing and input pipe has no data to read from")
+		}
+
+		query = b
+	} else {
+		// argument and input pipe are missing.
+		return nil, fmt.Errorf("sql argument is the only one required argument")
+	}
+
+	if len(query) == 0 {
+		return nil, fmt.Errorf("query should not be empty")
+	}
+
+	// replace all new line with spaces and trim any trailing space.
+	query = bytes.Replace(query, []byte("\n"), []byte(" "), -1)
+	return bytes.TrimSpace(query), nil
+}
+
+var (
+	// errQueryInterrupted is returned by the `sql` command when the query is interrupted, i.e by Ctrl+C.
+	errQueryInterrupted = errors.New("query interrupted, it was canceled on the server")
+	// errQueryNotCanceled is returned instead when the running query could not be matched on the server.
+	errQueryNotCanceled = errors.New("query interrupted, could not cancel it on the server, query id unknown")
+)
+
+// queryInterruptedError returns the error of an interrupted query,
+// based on the `LSQLContext`'s error which reports whether the query was canceled on the server too.
+func queryInterruptedError(err error) error {
+	cancelErr, ok := err.(*lenses.QueryCancelError)
+	if !ok {
+		return errQueryInterrupted
+	}
+
+	if cancelErr.Err == lenses.ErrRunningQueryNotFound {
+		return errQueryNotCanceled
+	}
+
+	return fmt.Errorf("query interrupted, could not cancel it on the server: %v", cancelErr.Err)
+}
+
+// contextWithSignals returns a context that is canceled when the process receives one of the "signals",
+// the returned function should be called to release its resources.
+func contextWithSignals(parent context.Context, signals ...os.Signal) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	go func() {
+		select {
+		case <-ch:
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(ch)
+	}()
+
+	return ctx, cancel
+}
+
+func newGetRunningQueriesCommand(api lenses.LSQLAPI) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:           "running",
 		Short:         "Print the current running queries, if any",
 		Example:       exampleString("sql running"),
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			queries, err := client.GetRunningQueries()
+			queries, err := api.GetRunningQueries()
 			if err != nil {
 				return err
 			}
@@ -202,7 +313,7 @@ func newGetRunningQueriesCommand() *cobra.Command {
 	return cmd
 }
 
-func newCancelQueryCommand() *cobra.Command {
+func newCancelQueryCommand(api lenses.LSQLAPI) *cobra.Command {
 	var id int64
 
 	cmd := &cobra.Command{
@@ -222,7 +333,7 @@ func newCancelQueryCommand() *cobra.Command {
 				}
 			}
 
-			deleted, err := client.CancelQuery(id)
+			deleted, err := api.CancelQuery(id)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/landoop/lenses-go"
+	"github.com/landoop/lenses-go/lensestest"
+
+	"github.com/spf13/cobra"
+)
+
+func TestLSQLCommandOutput(t *testing.T) {
+	srv := lensestest.NewServer()
+	defer srv.Close()
+
+	srv.AddRecords("reddit_posts",
+		lenses.LSQLRecord{Key: "1", Value: `{"a": 1, "b": "x", "author": {"name": "john"}}`},
+		lenses.LSQLRecord{Key: "2", Value: `{"a": 2, "b": "y", "author": {"name": "jane"}}`},
+		lenses.LSQLRecord{Key: "3", Value: `{"a": 3, "b": "z", "author": {"name": "joe"}}`},
+	)
+
+	client, err := lenses.OpenConnection(srv.Configuration())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { outputFormat, jmespathQuery = outputJSON, "" }()
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-o", "csv"}, "A,AUTHOR,B\n1,\"{\"\"name\"\":\"\"john\"\"}\",x\n2,\"{\"\"name\"\":\"\"jane\"\"}\",y\n3,\"{\"\"name\"\":\"\"joe\"\"}\",z\n"},
+		{[]string{"-o", "table"}, "A   B\n1   x\n2   y\n3   z\n"},
+		{[]string{"-o", "csv", "--query", "{name: author.name}"}, "NAME\njohn\njane\njoe\n"},
+	}
+
+	for _, tt := range tests {
+		// the "sql" has sub commands, its query argument is accepted only under a parent, like the root's.
+		out := new(bytes.Buffer)
+		root := &cobra.Command{Use: "lenses-cli"}
+		root.AddCommand(newLSQLCommand(client))
+		root.SetOutput(out)
+		root.SetArgs(append([]string{"sql"}, append(tt.args, "SELECT * FROM reddit_posts")...))
+
+		if err := root.Execute(); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := out.String(); tt.expected != got {
+			t.Fatalf("[%v] expected output:\n%s\nbut got:\n%s", tt.args, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,446 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/landoop/lenses-go"
+
+	"github.com/spf13/cobra"
+)
+
+// lsqlExportAPI is the part of the `lenses.API` that the `sql export` uses.
+type lsqlExportAPI interface {
+	lenses.LSQLAPI
+	lenses.SchemaRegistryAPI
+}
+
+const (
+	exportFormatNDJSON = "ndjson"
+	exportFormatCSV    = "csv"
+	exportFormatAvro   = "avro"
+)
+
+// exportManifestFilename is the file that the `sql export` writes to the output directory when finished.
+const exportManifestFilename = "manifest.json"
+
+func newLSQLExportCommand(api lsqlExportAPI) *cobra.Command {
+	e := &lsqlExporter{api: api}
+
+	cmd := &cobra.Command{
+		Use:   "export [query]",
+		Short: "Export the results of a query to ndjson, csv or avro files",
+		Long: "Runs the query and writes its records' values to the --out directory, the files are rotated by --max-records or --max-size. " +
+			"The csv columns are the flattened fields of the first record of each file, a record with new fields starts a new file. " +
+			"The avro files are Object Container Files with the topic's value schema of the schema registry, " +
+			"when finished a " + exportManifestFilename + " with the files and the query's stop information is written.",
+		Example:          exampleString(`sql export --format=csv --out=./reddit_posts --max-records=10000 "SELECT * FROM reddit_posts LIMIT 50000"`),
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkRequiredFlags(cmd, flags{"out": e.dir}); err != nil {
+				return err
+			}
+
+			query, err := readQueryArgument(args)
+			if err != nil {
+				return err
+			}
+			e.sql = string(query)
+
+			switch e.format {
+			case exportFormatNDJSON, exportFormatCSV, exportFormatAvro:
+			default:
+				return fmt.Errorf("unknown format '%s', available formats: %s, %s, %s", e.format, exportFormatNDJSON, exportFormatCSV, exportFormatAvro)
+			}
+
+			if err = os.MkdirAll(e.dir, 0755); err != nil {
+				return err
+			}
+
+			// on Ctrl+C the query is canceled on the server side too and the manifest is written with the records so far.
+			ctx, cancel := contextWithSignals(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			if err = e.run(ctx); err != nil {
+				return err
+			}
+
+			if err = echo(cmd, "Exported %d records to %d files in %s", e.manifest.TotalRecords, len(e.manifest.Files), e.dir); err != nil {
+				return err
+			}
+
+			if e.manifest.Interrupted {
+				return e.interruptErr
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&e.dir, "out", "", "--out=./reddit_posts the directory to write the files to")
+	cmd.Flags().StringVar(&e.format, "format", exportFormatNDJSON, "--format=csv the format of the files: "+exportFormatNDJSON+"|"+exportFormatCSV+"|"+exportFormatAvro)
+	cmd.Flags().StringVar(&e.separator, "separator", ".", `--separator="_" the separator of the nested fields' names of the csv columns, i.e "author.name"`)
+	cmd.Flags().IntVar(&e.maxRecords, "max-records", 0, "--max-records=10000 start a new file after the given number of records, zero means no limit")
+	cmd.Flags().Int64Var(&e.maxSize, "max-size", 0, "--max-size=10485760 start a new file after the given size in bytes, zero means no limit")
+	cmd.Flags().StringVar(&e.subject, "subject", "", "--subject=reddit_posts-value the schema registry subject of the avro schema, defaults to the <topic>-value")
+	canBeSilent(cmd)
+
+	return cmd
+}
+
+// exportManifest is the `exportManifestFilename`'s contents.
+type exportManifest struct {
+	SQL          string         `json:"sql"`
+	Format       string         `json:"format"`
+	Files        []exportedFile `json:"files"`
+	TotalRecords int            `json:"totalRecords"`
+	// Interrupted reports whether the query was interrupted, i.e by Ctrl+C, the `Stop` is filled by the client then.
+	Interrupted bool            `json:"interrupted"`
+	Stop        lenses.LSQLStop `json:"stop"`
+}
+
+type exportedFile struct {
+	Name    string `json:"name"`
+	Records int    `json:"records"`
+	Size    int64  `json:"size"`
+}
+
+// exportWriter writes the records of a file in a specific format.
+type exportWriter interface {
+	Write(record lenses.LSQLRecord, value interface{}) error
+	// Close writes any buffered data, it does not close the file.
+	Close() error
+}
+
+type lsqlExporter struct {
+	api lsqlExportAPI
+
+	sql, dir, format, separator, subject string
+	maxRecords                           int
+	maxSize                              int64
+
+	schema   *avroSchema
+	file     *os.File
+	counter  *countingWriter
+	writer   exportWriter
+	manifest exportManifest
+	// interruptErr reports whether the interrupted query was canceled on the server too, see `queryInterruptedError`.
+	interruptErr error
+}
+
+func (e *lsqlExporter) run(ctx context.Context) error {
+	e.manifest = exportManifest{SQL: e.sql, Format: e.format, Files: []exportedFile{}}
+
+	recordHandler := func(record lenses.LSQLRecord) error {
+		return e.write(record)
+	}
+
+	stopHandler := func(stop lenses.LSQLStop) error {
+		e.manifest.Stop = stop
+		return nil
+	}
+
+	stopErrHandler := func(errRecord lenses.LSQLError) error {
+		return fmt.Errorf(errRecord.Message)
+	}
+
+	err := e.api.LSQLContext(ctx, e.sql, true, 0, recordHandler, stopHandler, stopErrHandler, nil)
+	if closeErr := e.closeFile(); err == nil {
+		err = closeErr
+	}
+
+	if ctx.Err() != nil {
+		// the server does not send the stop information, fill what the client knows.
+		e.manifest.Interrupted = true
+		e.manifest.Stop = lenses.LSQLStop{IsStopped: true, TotalRecords: e.manifest.TotalRecords}
+		e.interruptErr = queryInterruptedError(err)
+		err = nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(e.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(e.dir, exportManifestFilename), append(b, '\n'), 0644)
+}
+
+func (e *lsqlExporter) write(record lenses.LSQLRecord) error {
+	var value interface{}
+	decoder := json.NewDecoder(strings.NewReader(record.Value))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		value = record.Value // not a json value, i.e a string topic.
+	}
+
+	if e.writer != nil && e.shouldRotate() {
+		if err := e.closeFile(); err != nil {
+			return err
+		}
+	}
+
+	if e.writer == nil {
+		if err := e.openFile(record); err != nil {
+			return err
+		}
+	}
+
+	err := e.writer.Write(record, value)
+	if err == errCSVColumnsWidened {
+		// the record has fields that the file's columns miss, start a new file with its columns.
+		if err = e.closeFile(); err != nil {
+			return err
+		}
+
+		if err = e.openFile(record); err != nil {
+			return err
+		}
+
+		err = e.writer.Write(record, value)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	e.manifest.TotalRecords++
+	e.manifest.Files[len(e.manifest.Files)-1].Records++
+	return nil
+}
+
+func (e *lsqlExporter) shouldRotate() bool {
+	current := e.manifest.Files[len(e.manifest.Files)-1]
+	if e.maxRecords > 0 && current.Records >= e.maxRecords {
+		return true
+	}
+
+	if e.maxSize > 0 {
+		size := e.counter.n
+		if buffered, ok := e.writer.(interface{ Buffered() int }); ok {
+			size += int64(buffered.Buffered())
+		}
+
+		return size >= e.maxSize
+	}
+
+	return false
+}
+
+func (e *lsqlExporter) openFile(record lenses.LSQLRecord) (err error) {
+	name := fmt.Sprintf("part-%05d.%s", len(e.manifest.Files)+1, e.format)
+	if e.file, err = os.Create(filepath.Join(e.dir, name)); err != nil {
+		return err
+	}
+	e.counter = &countingWriter{w: e.file}
+
+	switch e.format {
+	case exportFormatCSV:
+		e.writer = &csvExportWriter{w: csv.NewWriter(e.counter), separator: e.separator}
+	case exportFormatAvro:
+		if e.schema == nil {
+			if e.schema, err = e.valueSchema(record); err != nil {
+				return err
+			}
+		}
+
+		var w *avroFileWriter
+		if w, err = newAvroFileWriter(e.counter, e.schema); err != nil {
+			return err
+		}
+		e.writer = &avroExportWriter{w}
+	default:
+		e.writer = &ndjsonExportWriter{w: e.counter}
+	}
+
+	e.manifest.Files = append(e.manifest.Files, exportedFile{Name: name})
+	return nil
+}
+
+func (e *lsqlExporter) closeFile() error {
+	if e.writer == nil {
+		return nil
+	}
+
+	err := e.writer.Close()
+	if closeErr := e.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	e.manifest.Files[len(e.manifest.Files)-1].Size = e.counter.n
+	e.writer, e.file = nil, nil
+	return err
+}
+
+var lsqlFromRegexp = regexp.MustCompile("(?i)\\bFROM\\s+(`[^`]+`|[\\w.\\-]+)")
+
+// valueSchema returns the latest avro schema of the topic's value, the topic is the record's one or the query's FROM.
+func (e *lsqlExporter) valueSchema(record lenses.LSQLRecord) (*avroSchema, error) {
+	subject := e.subject
+	if subject == "" {
+		topic := record.Topic
+		if topic == "" {
+			if m := lsqlFromRegexp.FindStringSubmatch(e.sql); len(m) == 2 {
+				topic = strings.Trim(m[1], "`")
+			}
+		}
+
+		if topic == "" {
+			return nil, fmt.Errorf("unable to find the topic of the query, please set the --subject")
+		}
+		subject = topic + "-value"
+	}
+
+	schema, err := e.api.GetLatestSchema(subject)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve the avro schema of the subject '%s': %v", subject, err)
+	}
+
+	return parseAvroSchema(schema.AvroSchema)
+}
+
+// countingWriter counts the bytes that are written to the file, they are the file's size.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// ndjsonExportWriter writes the records' values one per line, as they are received.
+type ndjsonExportWriter struct {
+	w io.Writer
+}
+
+func (w *ndjsonExportWriter) Write(record lenses.LSQLRecord, value interface{}) error {
+	buf := new(bytes.Buffer)
+	if err := json.Compact(buf, []byte(record.Value)); err != nil {
+		buf.Reset()
+		b, _ := json.Marshal(record.Value)
+		buf.Write(b)
+	}
+	buf.WriteByte('\n')
+
+	_, err := w.w.Write(buf.Bytes())
+	return err
+}
+
+func (w *ndjsonExportWriter) Close() error { return nil }
+
+// errCSVColumnsWidened is returned by the `csvExportWriter#Write` when the record has fields
+// that are not part of the file's columns, the record is not written, the exporter starts a new file for it.
+var errCSVColumnsWidened = errors.New("csv: the record has fields that are not part of the file's columns")
+
+// csvExportWriter writes the flattened values, the columns are the fields of the first record, sorted.
+type csvExportWriter struct {
+	w         *csv.Writer
+	separator string
+	columns   []string
+}
+
+func (w *csvExportWriter) Write(record lenses.LSQLRecord, value interface{}) error {
+	fields := make(map[string]string)
+	flattenValue(fields, "", w.separator, value)
+
+	if w.columns == nil {
+		for column := range fields {
+			w.columns = append(w.columns, column)
+		}
+		sort.Strings(w.columns)
+
+		if err := w.w.Write(w.columns); err != nil {
+			return err
+		}
+	}
+
+	row := make([]string, len(w.columns))
+	for i, column := range w.columns {
+		row[i] = fields[column]
+		delete(fields, column)
+	}
+
+	if len(fields) > 0 {
+		return errCSVColumnsWidened
+	}
+
+	if err := w.w.Write(row); err != nil {
+		return err
+	}
+
+	// flush on each record, so the file's size is known for the rotation.
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (w *csvExportWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// flattenValue flattens the nested objects of the "value" to the "fields", their names are joined with the "separator",
+// the arrays are written as json, the non-object values are written to the "value" column.
+func flattenValue(fields map[string]string, prefix, separator string, value interface{}) {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		if prefix == "" {
+			prefix = "value"
+		}
+
+		switch v := value.(type) {
+		case nil:
+			fields[prefix] = ""
+		case string:
+			fields[prefix] = v
+		case []interface{}:
+			b, _ := json.Marshal(v)
+			fields[prefix] = string(b)
+		default:
+			fields[prefix] = fmt.Sprintf("%v", v)
+		}
+		return
+	}
+
+	for key, v := range m {
+		if prefix != "" {
+			key = prefix + separator + key
+		}
+		flattenValue(fields, key, separator, v)
+	}
+}
+
+type avroExportWriter struct {
+	*avroFileWriter
+}
+
+func (w *avroExportWriter) Write(record lenses.LSQLRecord, value interface{}) error {
+	if err := w.avroFileWriter.Write(value); err != nil {
+		return fmt.Errorf("record at partition %d offset %d: %v", record.Partition, record.Offset64(), err)
+	}
+
+	return nil
+}
+
+// Buffered returns the size of the block that is not written to the file yet.
+func (w *avroExportWriter) Buffered() int {
+	return w.block.Len()
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/landoop/lenses-go"
+	"github.com/landoop/lenses-go/lensestest"
+)
+
+func TestLSQLExportCommand(t *testing.T) {
+	srv := lensestest.NewServer()
+	defer srv.Close()
+
+	srv.AddRecords("reddit_posts",
+		lenses.LSQLRecord{Key: "1", Value: `{"title": "first", "author": {"name": "john", "karma": 10}}`},
+		lenses.LSQLRecord{Key: "2", Value: `{"title": "second", "author": {"name": "jane", "karma": 20}}`},
+		lenses.LSQLRecord{Key: "3", Value: `{"title": "third", "author": {"name": "joe", "karma": 30}, "tags": ["a"]}`},
+	)
+
+	client, err := lenses.OpenConnection(srv.Configuration())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := `{"type":"record","name":"Post","fields":[{"name":"title","type":"string"},` +
+		`{"name":"author","type":{"type":"record","name":"Author","fields":[{"name":"name","type":"string"},{"name":"karma","type":"int"}]}},` +
+		`{"name":"tags","type":{"type":"array","items":"string"},"default":[]}]}`
+	if _, err = client.RegisterSchema("reddit_posts-value", schema); err != nil {
+		t.Fatal(err)
+	}
+
+	export := func(args ...string) (string, exportManifest) {
+		dir, err := ioutil.TempDir("", "lenses-cli-export")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out := new(bytes.Buffer)
+		cmd := newLSQLExportCommand(client)
+		cmd.SetOutput(out)
+		cmd.SetArgs(append(args, "--out="+dir, "SELECT * FROM reddit_posts"))
+		if err = cmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+
+		if expected, got := "Exported 3 records", out.String(); !strings.HasPrefix(got, expected) {
+			t.Fatalf("expected the output to start with %q but got %q", expected, got)
+		}
+
+		b, err := ioutil.ReadFile(filepath.Join(dir, exportManifestFilename))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var manifest exportManifest
+		if err = json.Unmarshal(b, &manifest); err != nil {
+			t.Fatal(err)
+		}
+
+		return dir, manifest
+	}
+
+	readFile := func(dir, name string) string {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	dir, manifest := export("--max-records=2")
+	if expected, got := 2, len(manifest.Files); expected != got {
+		t.Fatalf("expected %d files but got %d: %#+v", expected, got, manifest.Files)
+	}
+	if expected, got := 3, manifest.TotalRecords; expected != got {
+		t.Fatalf("expected %d total records but got %d", expected, got)
+	}
+
+	expectedFirst := `{"title":"first","author":{"name":"john","karma":10}}` + "\n" + `{"title":"second","author":{"name":"jane","karma":20}}` + "\n"
+	if got := readFile(dir, "part-00001.ndjson"); expectedFirst != got {
+		t.Fatalf("expected the first file to be:\n%s\nbut got:\n%s", expectedFirst, got)
+	}
+	if expected, got := int64(len(expectedFirst)), manifest.Files[0].Size; expected != got {
+		t.Fatalf("expected the first file's size to be %d but got %d", expected, got)
+	}
+
+	// the columns are the first record's ones, the "tags" of the third record starts a new file.
+	dir, manifest = export("--format=csv", "--separator=_")
+	if expected, got := 2, len(manifest.Files); expected != got {
+		t.Fatalf("expected %d csv files but got %d: %#+v", expected, got, manifest.Files)
+	}
+
+	expectedCSV := "author_karma,author_name,title\n10,john,first\n20,jane,second\n"
+	if got := readFile(dir, "part-00001.csv"); expectedCSV != got {
+		t.Fatalf("expected the first csv to be:\n%s\nbut got:\n%s", expectedCSV, got)
+	}
+
+	expectedCSV = "author_karma,author_name,tags,title\n30,joe,\"[\"\"a\"\"]\",third\n"
+	if got := readFile(dir, "part-00002.csv"); expectedCSV != got {
+		t.Fatalf("expected the second csv to be:\n%s\nbut got:\n%s", expectedCSV, got)
+	}
+
+	if expected, got := 2, manifest.Files[0].Records; expected != got {
+		t.Fatalf("expected %d records in the first csv but got %d", expected, got)
+	}
+
+	dir, _ = export("--format=avro")
+	file := decodeAvroFile(t, []byte(readFile(dir, "part-00001.avro")))
+	if expected, got := schema, file.Metadata["avro.schema"]; expected != got {
+		t.Fatalf("expected the avro schema to be:\n%s\nbut got:\n%s", expected, got)
+	}
+
+	b, err := json.Marshal(file.Records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first two records are written with the default, empty, tags.
+	expectedAvro := `[{"author":{"karma":10,"name":"john"},"tags":[],"title":"first"},` +
+		`{"author":{"karma":20,"name":"jane"},"tags":[],"title":"second"},` +
+		`{"author":{"karma":30,"name":"joe"},"tags":["a"],"title":"third"}]`
+	if got := string(b); expectedAvro != got {
+		t.Fatalf("expected the avro records to be:\n%s\nbut got:\n%s", expectedAvro, got)
+	}
+}
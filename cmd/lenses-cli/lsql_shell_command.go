@@ -0,0 +1,386 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/landoop/lenses-go"
+
+	"github.com/spf13/cobra"
+)
+
+// lsqlShellAPI is the part of the `lenses.API` that the `sql shell` uses.
+type lsqlShellAPI interface {
+	lenses.LSQLAPI
+	lenses.TopicsAPI
+}
+
+// lsqlHistoryFile is the file that the statements of the `sql shell` are saved to, one per line.
+var lsqlHistoryFile = filepath.Join(lenses.DefaultConfigurationHomeDir, "lsql_history")
+
+// lsqlHistoryLimit is the maximum number of the statements that are loaded from the history file.
+const lsqlHistoryLimit = 1000
+
+func newLSQLShellCommand(api lsqlShellAPI) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "shell",
+		Short: "Start an interactive shell to run queries, the statements end with ';', type '\\help' for the meta-commands",
+		Long: "Starts an interactive shell, the statements can span multiple lines and they end with ';'. " +
+			"The topics, the keywords and the _key, _value, _ktype, _vtype, _partition and _offset fields are completed with the Tab key. " +
+			"The history is saved to the " + lsqlHistoryFile + ".",
+		Example:          exampleString(`sql shell`),
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			shell := newLSQLShell(cmd, api)
+			shell.loadHistory(lsqlHistoryFile)
+			return shell.run(os.Stdin)
+		},
+	}
+
+	canPrintJSON(cmd)
+
+	return cmd
+}
+
+type lsqlShell struct {
+	cmd *cobra.Command
+	api lsqlShellAPI
+	out io.Writer
+
+	reader      *lineReader
+	history     []string
+	historyFile string
+	// topics are the topics' names that are completed, they are loaded on first use and on `\topics`.
+	topics []string
+}
+
+func newLSQLShell(cmd *cobra.Command, api lsqlShellAPI) *lsqlShell {
+	return &lsqlShell{cmd: cmd, api: api, out: cmd.OutOrStdout()}
+}
+
+// loadHistory loads the previous statements and sets the file that the new ones are appended to,
+// the shell works without history if the file can't be read.
+func (s *lsqlShell) loadHistory(filename string) {
+	s.historyFile = filename
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	var entries []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		entries = append(entries, scanner.Text())
+	}
+
+	if len(entries) > lsqlHistoryLimit {
+		entries = entries[len(entries)-lsqlHistoryLimit:]
+	}
+
+	s.history = entries
+}
+
+func (s *lsqlShell) saveHistory(entry string) {
+	s.reader.addHistory(entry)
+
+	if s.historyFile == "" {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.historyFile), 0700); err != nil {
+		return
+	}
+
+	f, err := os.OpenFile(s.historyFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(f, entry)
+	f.Close()
+}
+
+const (
+	lsqlShellPrompt         = "lsql> "
+	lsqlShellContinuePrompt = "   -> "
+)
+
+// run reads and executes the statements and the meta-commands until `\q` or the end of the input (Ctrl+D),
+// the failures are printed and the shell continues.
+func (s *lsqlShell) run(in io.Reader) error {
+	s.reader = newLineReader(in, s.out, s.complete)
+	s.reader.history = s.history
+
+	var statement []string
+	for {
+		prompt := lsqlShellPrompt
+		if len(statement) > 0 {
+			prompt = lsqlShellContinuePrompt
+		}
+
+		line, err := s.reader.readLine(prompt)
+		if err == errInterrupted {
+			statement = nil
+			continue
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if len(statement) == 0 {
+			if trimmed == "" {
+				continue
+			}
+
+			if strings.HasPrefix(trimmed, `\`) {
+				s.saveHistory(trimmed)
+				if quit := s.meta(trimmed); quit {
+					return nil
+				}
+				continue
+			}
+
+			if lower := strings.ToLower(strings.TrimSuffix(trimmed, ";")); lower == "exit" || lower == "quit" {
+				return nil
+			}
+		}
+
+		statement = append(statement, line)
+		if !strings.HasSuffix(trimmed, ";") {
+			continue
+		}
+
+		sql := strings.TrimSpace(strings.Join(statement, "\n"))
+		statement = nil
+
+		// save the multi-line statements as one line, so they can be recalled and executed at once.
+		s.saveHistory(strings.Join(strings.Fields(sql), " "))
+		if err = s.execute(strings.TrimSuffix(sql, ";")); err != nil {
+			fmt.Fprintf(s.out, "Error: %v\n", err)
+		}
+	}
+}
+
+const lsqlShellHelp = `Statements end with ';' and they can span multiple lines.
+Meta-commands:
+  \topics            list the topics
+  \describe <topic>  print the topic's types, partitions, schemas and configs
+  \running           list the running queries
+  \cancel <id>       cancel a running query
+  \validate <sql>    validate the query without executing it
+  \help              print this help
+  \q                 quit, same as Ctrl+D
+`
+
+// meta executes a meta-command, i.e `\topics`, it returns true on `\q`.
+func (s *lsqlShell) meta(line string) (quit bool) {
+	fields := strings.Fields(line)
+	name, arg := fields[0], strings.TrimSpace(strings.TrimPrefix(line, fields[0]))
+
+	var err error
+	switch name {
+	case `\q`, `\quit`:
+		return true
+	case `\help`, `\?`:
+		fmt.Fprint(s.out, lsqlShellHelp)
+	case `\topics`:
+		s.topics = nil
+		var topics []string
+		if topics, err = s.topicNames(); err == nil {
+			fmt.Fprintln(s.out, strings.Join(topics, "\n"))
+		}
+	case `\describe`:
+		if arg == "" {
+			err = fmt.Errorf("topic name is missing, i.e \\describe reddit_posts")
+			break
+		}
+
+		var topic lenses.Topic
+		if topic, err = s.api.GetTopic(arg); err == nil {
+			err = printJSON(s.cmd, topic)
+		}
+	case `\running`:
+		var queries []lenses.LSQLRunningQuery
+		if queries, err = s.api.GetRunningQueries(); err == nil {
+			err = printJSON(s.cmd, queries)
+		}
+	case `\cancel`:
+		var id int64
+		if id, err = strconv.ParseInt(arg, 10, 64); err != nil {
+			err = fmt.Errorf("invalid query id '%s'", arg)
+			break
+		}
+
+		var cancelled bool
+		if cancelled, err = s.api.CancelQuery(id); err == nil {
+			fmt.Fprintln(s.out, cancelled)
+		}
+	case `\validate`:
+		sql := strings.TrimSuffix(arg, ";")
+		var valid bool
+		if valid, err = s.validate(sql); err == nil && valid {
+			fmt.Fprintln(s.out, "Valid")
+		}
+	default:
+		err = fmt.Errorf("unknown meta-command '%s', type \\help for the available ones", name)
+	}
+
+	if err != nil {
+		fmt.Fprintf(s.out, "Error: %v\n", err)
+	}
+
+	return false
+}
+
+// validate validates the query and prints the invalid position, if any.
+func (s *lsqlShell) validate(sql string) (bool, error) {
+	validation, err := s.api.ValidateLSQL(sql)
+	if err != nil {
+		return false, err
+	}
+
+	if !validation.IsValid {
+		printLSQLErrorPosition(s.out, sql, validation.Line, validation.Column, validation.Column)
+		fmt.Fprintln(s.out, validation.Message)
+	}
+
+	return validation.IsValid, nil
+}
+
+// execute validates and runs the query, the records' values are printed as json,
+// Ctrl+C cancels the query and returns to the prompt.
+func (s *lsqlShell) execute(sql string) error {
+	if valid, err := s.validate(sql); err != nil || !valid {
+		return err
+	}
+
+	ctx, cancel := contextWithSignals(context.Background(), os.Interrupt)
+	defer cancel()
+
+	rows, err := s.api.Query(ctx, sql, lenses.QueryOptions{})
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var n int
+	for rows.Next() {
+		var value interface{}
+		if err = json.Unmarshal([]byte(rows.Record().Value), &value); err != nil {
+			value = rows.Record().Value
+		}
+
+		if err = printJSON(s.cmd, value); err != nil {
+			return err
+		}
+		n++
+	}
+
+	if err = rows.Err(); err != nil {
+		if ctx.Err() != nil {
+			fmt.Fprintf(s.out, "Cancelled, %d records\n", n)
+			return nil
+		}
+
+		if errRecord, ok := err.(lenses.LSQLError); ok {
+			printLSQLErrorPosition(s.out, sql, errRecord.FromLine, errRecord.FromColumn, errRecord.ToColumn)
+		}
+		return err
+	}
+
+	fmt.Fprintf(s.out, "%d records\n", n)
+	return nil
+}
+
+// printLSQLErrorPosition prints the line of the query and, under it, a marker from the "fromColumn" to the "toColumn",
+// the line and the columns start from 1, as the `LSQLValidation` and `LSQLError` ones.
+func printLSQLErrorPosition(w io.Writer, sql string, line, fromColumn, toColumn int) {
+	lines := strings.Split(sql, "\n")
+	if line < 1 || line > len(lines) || fromColumn < 1 {
+		return
+	}
+
+	if toColumn < fromColumn {
+		toColumn = fromColumn
+	}
+
+	fmt.Fprintf(w, "%s\n%s^%s\n", lines[line-1], strings.Repeat(" ", fromColumn-1), strings.Repeat("~", toColumn-fromColumn))
+}
+
+func (s *lsqlShell) topicNames() ([]string, error) {
+	if s.topics == nil {
+		topics, err := s.api.GetTopicsNames()
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(topics)
+		s.topics = topics
+	}
+
+	return s.topics, nil
+}
+
+var (
+	lsqlKeywords = []string{
+		"SELECT", "FROM", "WHERE", "AND", "OR", "NOT", "LIMIT", "INSERT", "INTO", "SET", "AS",
+		"GROUP", "BY", "ORDER", "ASC", "DESC", "HAVING", "JOIN", "INNER", "LEFT", "RIGHT", "OUTER", "ON",
+		"WITH", "STREAM", "TABLE", "CREATE", "VALUES", "LIKE", "IN", "IS", "NULL", "TRUE", "FALSE",
+		"DISTINCT", "COUNT", "SUM", "AVG", "MIN", "MAX", "CAST", "WINDOW", "EMIT",
+	}
+	lsqlFields       = []string{"_key", "_value", "_ktype", "_vtype", "_partition", "_offset"}
+	lsqlMetaCommands = []string{`\topics`, `\describe`, `\running`, `\cancel`, `\validate`, `\help`, `\q`}
+)
+
+// complete completes the last word of the line, the topics after the FROM, JOIN, INTO and `\describe`,
+// the fields if it starts with "_", the meta-commands if it starts with "\" and the keywords or the topics otherwise.
+func (s *lsqlShell) complete(line string) (string, []string) {
+	fields := strings.Fields(line)
+	word := ""
+	if len(fields) > 0 && !strings.HasSuffix(line, " ") {
+		word, fields = fields[len(fields)-1], fields[:len(fields)-1]
+	}
+
+	var previous string
+	if len(fields) > 0 {
+		previous = strings.ToUpper(fields[len(fields)-1])
+	}
+
+	topics, _ := s.topicNames() // completion is best effort.
+
+	var sources [][]string
+	switch {
+	case len(fields) == 0 && strings.HasPrefix(word, `\`):
+		sources = [][]string{lsqlMetaCommands}
+	case previous == "FROM" || previous == "JOIN" || previous == "INTO" || previous == `\DESCRIBE`:
+		sources = [][]string{topics}
+	case strings.HasPrefix(word, "_"):
+		sources = [][]string{lsqlFields}
+	default:
+		sources = [][]string{lsqlKeywords, topics}
+	}
+
+	var candidates []string
+	for _, source := range sources {
+		for _, candidate := range source {
+			if len(candidate) >= len(word) && strings.EqualFold(candidate[:len(word)], word) {
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+
+	return word, candidates
+}
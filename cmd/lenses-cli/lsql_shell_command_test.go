@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/landoop/lenses-go"
+	"github.com/landoop/lenses-go/lensestest"
+)
+
+func TestLSQLShell(t *testing.T) {
+	srv := lensestest.NewServer()
+	defer srv.Close()
+
+	srv.AddRecords("reddit_posts",
+		lenses.LSQLRecord{Key: "1", Value: `{"title":"first"}`},
+		lenses.LSQLRecord{Key: "2", Value: `{"title":"second"}`},
+	)
+
+	client, err := lenses.OpenConnection(srv.Configuration())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := new(bytes.Buffer)
+	cmd := newLSQLShellCommand(client)
+	cmd.SetOutput(out)
+
+	shell := newLSQLShell(cmd, client)
+	input := "\\topics\nSELECT *\n  FROM reddit_posts;\nSELEC * FROM reddit_posts;\n\\q\nSELECT * FROM reddit_posts;\n"
+	if err = shell.run(strings.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+
+	got := out.String()
+	for _, expected := range []string{
+		"reddit_posts\n",
+		`"title": "first"`,
+		`"title": "second"`,
+		"2 records\n",
+		"SELEC * FROM reddit_posts\n^\nInvalid syntax.Encountered \"SELEC\" at line 1, column 1.",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Fatalf("expected the output to contain %q but got:\n%s", expected, got)
+		}
+	}
+
+	// the statement after the \q should not run.
+	if n := strings.Count(got, "records\n"); n != 1 {
+		t.Fatalf("expected one executed query but got %d:\n%s", n, got)
+	}
+
+	if expected, got := "SELECT * FROM reddit_posts;", shell.reader.history[1]; expected != got {
+		t.Fatalf("expected the multi-line statement to be saved as %q but got %q", expected, got)
+	}
+
+	tests := []struct {
+		line, word, candidates string
+	}{
+		{"sel", "sel", "SELECT"},
+		{"SELECT * FROM red", "red", "reddit_posts"},
+		{"SELECT _o", "_o", "_offset"},
+		{`\desc`, `\desc`, `\describe`},
+		{`\describe `, "", "reddit_posts"},
+	}
+
+	for i, tt := range tests {
+		word, candidates := shell.complete(tt.line)
+		if word != tt.word || strings.Join(candidates, ",") != tt.candidates {
+			t.Fatalf("[%d] expected %q and %q but got %q and %q", i, tt.word, tt.candidates, word, strings.Join(candidates, ","))
+		}
+	}
+}
@@ -2,9 +2,12 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -25,9 +28,18 @@ var (
 	buildTime = ""
 )
 
-var (
-	client *lenses.Client
-)
+// api is the Lenses API that the commands depend on,
+// each command accepts only the part of it that it uses, i.e the `lenses.TopicsAPI`,
+// so it can be tested with a fake one, see the `lensestest` package.
+//
+// The commands are registered before the connection to the Lenses server,
+// therefore its implementation, the `lenses.Client`, is set later on, by the `setupClient`.
+var api = new(lazyAPI)
+
+// lazyAPI is a `lenses.API` whose implementation is set after its creation.
+type lazyAPI struct {
+	lenses.API
+}
 
 const examplePrefix = `lenses-cli %s`
 
@@ -46,6 +58,11 @@ var rootCmd = &cobra.Command{
 	TraverseChildren:           true,
 	SuggestionsMinimumDistance: 1,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) (err error) {
+		// the completion functions load the configuration and connect only when they need to, see `completionValues`.
+		if name := cmd.Name(); name == "completion" || name == cobra.ShellCompRequestCmd || name == cobra.ShellCompNoDescRequestCmd {
+			return nil
+		}
+
 		// check for old config, if found then convert to its new format before anything else.
 		if err := configManager.applyCompatibility(); err != nil {
 			return err
@@ -84,7 +101,7 @@ var rootCmd = &cobra.Command{
 			currentConfig.Token = ""
 
 			//  and fire any errors if host or user or pass are not there.
-			if currentConfig.User == "" || currentConfig.Password == "" || currentConfig.Host == "" {
+			if currentConfig.User == "" || currentConfig.Password == "" || len(currentConfig.GetHosts()) == 0 {
 				// return fmt.Errorf("cannot retrieve credentials, please setup the configuration using the '%s' command first", "configure")
 				//
 				if err := newConfigureCommand().Execute(); err != nil {
@@ -102,8 +119,9 @@ var rootCmd = &cobra.Command{
 		// 	cmd.DebugFlags()
 		// }
 
-		// don't connect to the HTTP REST API when command is "live" (websocket).
-		if cmd.Name() == "live" {
+		// don't connect to the HTTP REST API when command is "live" (websocket)
+		// or "diff", which connects to the contexts of its flags.
+		if name := cmd.Name(); name == "live" || name == "diff" {
 			return
 		}
 
@@ -111,15 +129,48 @@ var rootCmd = &cobra.Command{
 	},
 }
 
-func setupClient() (err error) {
+// clientOptions are the options of the clients that the commands connect with,
+// the `setupClient`'s one and the `diff`'s ones.
+func clientOptions() []lenses.ConnectionOption {
+	return []lenses.ConnectionOption{lenses.UsingInterceptors(logActiveHost)}
+}
+
+func setupClient() error {
 	currentConfig := configManager.getCurrent()
 	currentConfig.FormatHost()
-	client, err = lenses.OpenConnection(*currentConfig)
-	// if err == nil {
-	// 	currentConfig.Token = client.GetAccessToken()
-	// }
+	client, err := lenses.OpenConnection(*currentConfig, clientOptions()...)
+	if err != nil {
+		return err
+	}
+
+	api.API = client
+	return nil
+}
+
+// applyClientOptions applies the "options" to the connected client of a command,
+// the commands' fake APIs of the tests are left as they are.
+func applyClientOptions(api interface{}, options ...lenses.ConnectionOption) {
+	lazy, ok := api.(*lazyAPI)
+	if !ok {
+		return
+	}
 
-	return
+	if client, ok := lazy.API.(*lenses.Client); ok {
+		for _, opt := range options {
+			opt(client)
+		}
+	}
+}
+
+// logActiveHost prints the host that each request is sent to, it's visible only on --debug,
+// it may change on failover when more than one hosts are configured.
+// It writes to the stderr so the command's output can still be piped.
+func logActiveHost(op string, req *http.Request, next lenses.RoundTripFunc) (*http.Response, error) {
+	if configManager.getCurrent().Debug {
+		fmt.Fprintf(os.Stderr, "%s: active host: %s\n", op, req.URL.Host)
+	}
+
+	return next(req)
 }
 
 // timeLayout defines the datetime layout for the `buildTime`.
@@ -161,25 +212,95 @@ var (
 
 type errorMap map[error]string
 
+// isError reports whether the "err" is the "target" or
+// it matches the "target" through its `Is` method, i.e the `lenses.APIError`.
+func isError(err, target error) bool {
+	if err == target {
+		return true
+	}
+
+	if e, ok := err.(interface{ Is(error) bool }); ok {
+		return e.Is(target)
+	}
+
+	return false
+}
+
+// mapError returns the message of the "err"'s target, if any, the exact target wins,
+// the rest are checked through the `isError` in the order of their text, so the result does not depend on the map's order.
 func mapError(err error, messages errorMap) error {
-	if messages == nil {
-		return err
+	targets := make([]error, 0, len(messages))
+	for target, errMsg := range messages {
+		if errMsg == "" {
+			continue
+		}
+
+		// compare instead of messages[err], the "err" may be of a non comparable type.
+		if err == target {
+			return fmt.Errorf(errMsg)
+		}
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Error() < targets[j].Error() })
+
+	for _, target := range targets {
+		if isError(err, target) {
+			return fmt.Errorf(messages[target])
+		}
 	}
 
-	if errMsg, ok := messages[err]; ok {
-		return fmt.Errorf(errMsg)
+	if apiErr, ok := err.(*lenses.APIError); ok {
+		return mapAPIError(apiErr)
 	}
 
 	return err // otherwise just print the error as it's.
 }
 
+// mapAPIError returns a friendly error message based on the status code of the failed API call.
+func mapAPIError(apiErr *lenses.APIError) error {
+	if configManager != nil && configManager.getCurrent().Debug {
+		return apiErr // print the whole information on debug mode.
+	}
+
+	var msg string
+
+	switch code := apiErr.StatusCode; {
+	case code == http.StatusUnauthorized:
+		msg = "authentication failed, please check your credentials or use the 'login' command"
+	case code == http.StatusForbidden:
+		msg = "permission denied, the user has no access to this action"
+	case code == http.StatusNotFound:
+		msg = "resource does not exist"
+	case code == http.StatusConflict:
+		msg = "resource already exists"
+	case code == http.StatusBadRequest:
+		msg = "invalid request"
+	case code >= http.StatusInternalServerError:
+		msg = fmt.Sprintf("lenses server failed with status code %d", code)
+	default:
+		return apiErr
+	}
+
+	if apiErr.Message != "" {
+		msg += ": " + apiErr.Message
+	}
+
+	return errors.New(msg)
+}
+
 var configManager *configurationManager
 
 func main() {
 	rootCmd.SetVersionTemplate(buildVersionTmpl())
 	configManager = newConfigurationManager(rootCmd)
+	registerCompletions(rootCmd)
 
 	if err := rootCmd.Execute(); err != nil {
+		exitCode := 1
+		if err == errDrift || err == errDiff {
+			exitCode = 2 // so the CI can tell a drift from a failure.
+		}
+
 		// catch any errors that should be described by the command that gave that error.
 		// each errResourceXXXMessage should be declared inside the command,
 		// they are global variables and that's because we don't want to get dirdy on each resource command, don't change it unless discussion.
@@ -189,6 +310,6 @@ func main() {
 
 		// always new line because of the unix terminal.
 		fmt.Fprintln(os.Stderr, err.Error())
-		os.Exit(1)
+		os.Exit(exitCode)
 	}
 }
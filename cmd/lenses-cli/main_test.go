@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/landoop/lenses-go"
+)
+
+// anyTargetError matches every target through its `Is`.
+type anyTargetError struct{ name string }
+
+func (err *anyTargetError) Error() string        { return err.name }
+func (err *anyTargetError) Is(target error) bool { return true }
+
+func TestMapError(t *testing.T) {
+	errExact := &anyTargetError{"exact target"}
+	messages := errorMap{
+		lenses.ErrResourceNotFound:   "resource not found",
+		lenses.ErrCredentialsMissing: "credentials missing",
+		errExact:                     "exact",
+	}
+
+	// repeat it, a result that depends on the map's order would differ between the runs.
+	for i := 0; i < 20; i++ {
+		if expected, got := "exact", mapError(errExact, messages).Error(); expected != got {
+			t.Fatalf("[%d] expected the exact target's message %q but got %q", i, expected, got)
+		}
+
+		// the ErrResourceNotFound's text, "404", is ordered first.
+		if expected, got := "resource not found", mapError(&anyTargetError{"other target"}, messages).Error(); expected != got {
+			t.Fatalf("[%d] expected the first target's message %q but got %q", i, expected, got)
+		}
+	}
+
+	if expected, got := "credentials missing", mapError(&lenses.APIError{StatusCode: 401}, messages).Error(); expected != got {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+}
@@ -0,0 +1,758 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/landoop/lenses-go"
+
+	"gopkg.in/yaml.v2"
+)
+
+// manifest is a resource loaded from a file, its kind is set by the "kind" field, i.e `kind: Topic`,
+// the rest of the fields are the same as the ones that the resource's create command loads from a file.
+//
+// A file can contain more than one manifest, separated by "---" for yaml or as an array for json.
+type manifest struct {
+	Kind *resourceKind
+	// Source is the file that the manifest is loaded from.
+	Source string
+	// Resource is a pointer to the kind's payload, see `resourceKind#new`.
+	Resource interface{}
+}
+
+const manifestKindField = "kind"
+
+var manifestExtensions = map[string]bool{".yml": true, ".yaml": true, ".json": true}
+
+// loadManifests loads the manifests of the "paths",
+// the .yml, .yaml and .json files of a directory are loaded, sorted by their names.
+func loadManifests(paths []string) ([]manifest, error) {
+	var manifests []manifest
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		files := []string{path}
+		if info.IsDir() {
+			files = files[:0]
+			err = filepath.Walk(path, func(filename string, fi os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+
+				if !fi.IsDir() && manifestExtensions[strings.ToLower(filepath.Ext(filename))] {
+					files = append(files, filename)
+				}
+
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			sort.Strings(files)
+		}
+
+		for _, filename := range files {
+			fileManifests, err := loadManifestFile(filename)
+			if err != nil {
+				return nil, err
+			}
+
+			manifests = append(manifests, fileManifests...)
+		}
+	}
+
+	return manifests, nil
+}
+
+func loadManifestFile(filename string) ([]manifest, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		docs      [][]byte
+		unmarshal func([]byte, interface{}) error
+	)
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		unmarshal = json.Unmarshal
+		docs, err = splitJSONDocuments(b)
+	default:
+		unmarshal = yaml.Unmarshal
+		docs, err = splitYAMLDocuments(b)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+
+	manifests := make([]manifest, 0, len(docs))
+	for i, doc := range docs {
+		var header map[string]interface{}
+		if err = unmarshal(doc, &header); err != nil {
+			return nil, fmt.Errorf("%s: document %d: %v", filename, i+1, err)
+		}
+
+		if len(header) == 0 {
+			continue // empty document.
+		}
+
+		kindName, _ := header[manifestKindField].(string)
+		if kindName == "" {
+			kindName, _ = header[strings.Title(manifestKindField)].(string)
+		}
+
+		kind := findResourceKind(kindName)
+		if kind == nil {
+			return nil, fmt.Errorf("%s: document %d: unknown kind '%s', available kinds: %s", filename, i+1, kindName, strings.Join(resourceKindNames(), ", "))
+		}
+
+		resource := kind.new()
+		if err = unmarshal(doc, resource); err != nil {
+			return nil, fmt.Errorf("%s: document %d: %v", filename, i+1, err)
+		}
+
+		if kind.id(resource) == "" {
+			return nil, fmt.Errorf("%s: document %d: %s has no name", filename, i+1, kind.name)
+		}
+
+		manifests = append(manifests, manifest{Kind: kind, Source: filename, Resource: resource})
+	}
+
+	return manifests, nil
+}
+
+func splitYAMLDocuments(b []byte) ([][]byte, error) {
+	var docs [][]byte
+
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var doc yaml.MapSlice
+		if err := dec.Decode(&doc); err != nil {
+			if err == io.EOF {
+				return docs, nil
+			}
+			return nil, err
+		}
+
+		out, err := yaml.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		docs = append(docs, out)
+	}
+}
+
+// splitJSONDocuments returns the objects of a json file, the file can contain one or more objects or arrays of objects.
+func splitJSONDocuments(b []byte) ([][]byte, error) {
+	var docs [][]byte
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if err == io.EOF {
+				return docs, nil
+			}
+			return nil, err
+		}
+
+		if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+			var list []json.RawMessage
+			if err := json.Unmarshal(trimmed, &list); err != nil {
+				return nil, err
+			}
+
+			for _, item := range list {
+				docs = append(docs, item)
+			}
+			continue
+		}
+
+		docs = append(docs, raw)
+	}
+}
+
+// resourceKind describes a kind of the manifests,
+// how its resources are identified, read from the server and created, updated or deleted.
+type resourceKind struct {
+	// name is the manifest's kind, i.e "Topic".
+	name string
+	// new returns a pointer to the payload that the manifests of the kind are decoded to.
+	new func() interface{}
+	// id returns the identifier of a resource, unique per kind.
+	id func(resource interface{}) string
+	// list returns the resources of the kind that exist on the server.
+	list func(api lenses.API) ([]interface{}, error)
+	// normalize fills the defaults and removes the read-only fields of a resource, optional.
+	normalize func(resource interface{})
+
+	create func(api lenses.API, resource interface{}) error
+	// update is nil when all the fields of the resource are part of its identifier, i.e the ACLs.
+	update func(api lenses.API, resource interface{}) error
+	delete func(api lenses.API, resource interface{}) error
+
+	// immutable are the fields that cannot be updated, a change on them requires a replace,
+	// if the kind is `replaceable`, otherwise the resource cannot be applied.
+	immutable   []string
+	replaceable bool
+}
+
+// resourceKinds are the kinds of the manifests, in the order that they are created.
+var resourceKinds = []*resourceKind{
+	topicKind,
+	schemaKind,
+	connectorKind,
+	processorKind,
+	aclKind,
+	quotaKind,
+	alertSettingConditionKind,
+}
+
+func findResourceKind(name string) *resourceKind {
+	for _, kind := range resourceKinds {
+		if strings.EqualFold(kind.name, name) {
+			return kind
+		}
+	}
+
+	return nil
+}
+
+func resourceKindNames() []string {
+	names := make([]string, len(resourceKinds))
+	for i, kind := range resourceKinds {
+		names[i] = kind.name
+	}
+
+	return names
+}
+
+// topicManifest is the manifest's form of a topic, the fields are the ones of the `lenses.CreateTopicPayload`,
+// the omitted partitions and replication are not compared to the existing topics, they default to 1 on create.
+type topicManifest struct {
+	TopicName   string    `json:"topicName" yaml:"Name"`
+	Replication int       `json:"replication,omitempty" yaml:"Replication"`
+	Partitions  int       `json:"partitions,omitempty" yaml:"Partitions"`
+	Configs     lenses.KV `json:"configs" yaml:"Configs"`
+}
+
+var topicKind = &resourceKind{
+	name: "Topic",
+	new:  func() interface{} { return &topicManifest{} },
+	id:   func(r interface{}) string { return r.(*topicManifest).TopicName },
+	list: func(api lenses.API) ([]interface{}, error) {
+		topics, err := api.GetTopics()
+		if err != nil {
+			return nil, err
+		}
+
+		var resources []interface{}
+		for _, topic := range topics {
+			if topic.IsControlTopic {
+				continue // managed by Kafka and Lenses.
+			}
+
+			resources = append(resources, &topicManifest{
+				TopicName:   topic.TopicName,
+				Partitions:  topic.Partitions,
+				Replication: topic.Replication,
+				Configs:     topicConfigs(topic.Config),
+			})
+		}
+
+		return resources, nil
+	},
+	create: func(api lenses.API, r interface{}) error {
+		topic := r.(*topicManifest)
+
+		// the defaults are filled on create only, the omitted fields are not compared to the existing topics.
+		replication, partitions := topic.Replication, topic.Partitions
+		if replication <= 0 {
+			replication = 1
+		}
+		if partitions <= 0 {
+			partitions = 1
+		}
+
+		return api.CreateTopic(topic.TopicName, replication, partitions, topic.Configs)
+	},
+	update: func(api lenses.API, r interface{}) error {
+		topic := r.(*topicManifest)
+
+		keys := make([]string, 0, len(topic.Configs))
+		for key := range topic.Configs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		configs := make([]lenses.KV, 0, len(keys))
+		for _, key := range keys {
+			configs = append(configs, lenses.KV{"key": key, "value": formatCell(topic.Configs[key])})
+		}
+
+		return api.UpdateTopic(topic.TopicName, configs)
+	},
+	delete: func(api lenses.API, r interface{}) error {
+		return api.DeleteTopic(r.(*topicManifest).TopicName)
+	},
+	immutable: []string{"partitions", "replication"},
+}
+
+// topicConfigs converts the topic's config entries, i.e [{"key": "cleanup.policy", "value": "compact"}] to a map,
+// the entries with the default values are skipped.
+func topicConfigs(entries []lenses.KV) lenses.KV {
+	configs := make(lenses.KV, len(entries))
+	for _, entry := range entries {
+		if isDefault, _ := entry["isDefault"].(bool); isDefault {
+			continue
+		}
+
+		if defaultValue, ok := entry["defaultValue"]; ok && formatCell(defaultValue) == formatCell(entry["value"]) {
+			continue
+		}
+
+		for _, nameKey := range []string{"key", "configuration", "name"} {
+			if name, ok := entry[nameKey].(string); ok && name != "" {
+				configs[name] = entry["value"]
+				break
+			}
+		}
+	}
+
+	return configs
+}
+
+// schemaManifest is the manifest's form of a schema subject,
+// it's a `lenses.Schema`, its Name and AvroSchema, the latest schema of the subject, plus the optional fields below,
+// so the manifests of the `lenses.Schema` form are loaded as they are.
+type schemaManifest struct {
+	lenses.Schema `yaml:",inline"`
+	// Versions are the previous schemas of the subject, oldest first, they are registered before the `AvroSchema`.
+	Versions []string `json:"versions,omitempty" yaml:"Versions,omitempty"`
+	// Compatibility is the subject's compatibility level, the global one is used if empty.
+	Compatibility lenses.CompatibilityLevel `json:"compatibility,omitempty" yaml:"Compatibility,omitempty"`
+}
+
+var schemaKind = &resourceKind{
+	name: "Schema",
+	new:  func() interface{} { return &schemaManifest{} },
+	id:   func(r interface{}) string { return r.(*schemaManifest).Name },
+	list: func(api lenses.API) ([]interface{}, error) {
+		subjects, err := api.GetSubjects()
+		if err != nil {
+			return nil, err
+		}
+
+		resources := make([]interface{}, 0, len(subjects))
+		for _, subject := range subjects {
+			versions, err := api.GetSubjectVersions(subject)
+			if err != nil {
+				return nil, err
+			}
+			sort.Ints(versions)
+
+			schema := &schemaManifest{Schema: lenses.Schema{Name: subject}}
+			for i, version := range versions {
+				s, err := api.GetSchemaAtVersion(subject, version)
+				if err != nil {
+					return nil, err
+				}
+
+				if i == len(versions)-1 {
+					schema.AvroSchema = s.AvroSchema
+					break
+				}
+				schema.Versions = append(schema.Versions, s.AvroSchema)
+			}
+
+			level, err := api.GetSubjectCompatibilityLevel(subject)
+			if err != nil && !isNotFound(err) { // not found means that the subject uses the global level.
+				return nil, err
+			}
+			schema.Compatibility = level
+
+			resources = append(resources, schema)
+		}
+
+		return resources, nil
+	},
+	normalize: func(r interface{}) {
+		schema := r.(*schemaManifest)
+		schema.ID, schema.Version = 0, 0 // set by the registry.
+		schema.AvroSchema = compactJSON(schema.AvroSchema)
+		for i := range schema.Versions {
+			schema.Versions[i] = compactJSON(schema.Versions[i])
+		}
+	},
+	create: registerSchema,
+	update: registerSchema,
+	delete: func(api lenses.API, r interface{}) error {
+		_, err := api.DeleteSubject(r.(*schemaManifest).Name)
+		return err
+	},
+}
+
+// registerSchema registers the versions of the schema, in order, and sets its compatibility level.
+// The registry does not register again a schema that is already registered under the subject.
+func registerSchema(api lenses.API, r interface{}) error {
+	schema := r.(*schemaManifest)
+	for _, avroSchema := range append(schema.Versions, schema.AvroSchema) {
+		if _, err := api.RegisterSchema(schema.Name, avroSchema); err != nil {
+			return err
+		}
+	}
+
+	if schema.Compatibility != "" {
+		return api.UpdateSubjectCompatibilityLevel(schema.Name, schema.Compatibility)
+	}
+
+	return nil
+}
+
+func compactJSON(s string) string {
+	compact := new(bytes.Buffer)
+	if err := json.Compact(compact, []byte(s)); err != nil {
+		return s
+	}
+
+	return compact.String()
+}
+
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*lenses.APIError)
+	return ok && apiErr.Is(lenses.ErrResourceNotFound)
+}
+
+var connectorKind = &resourceKind{
+	name: "Connector",
+	new: func() interface{} {
+		return &lenses.CreateUpdateConnectorPayload{Config: make(lenses.ConnectorConfig)}
+	},
+	id: func(r interface{}) string {
+		connector := r.(*lenses.CreateUpdateConnectorPayload)
+		if connector.Name == "" {
+			return ""
+		}
+		return connector.ClusterName + "/" + connector.Name
+	},
+	list: func(api lenses.API) ([]interface{}, error) {
+		clusters, err := api.GetConnectClusters()
+		if err != nil {
+			return nil, err
+		}
+
+		var resources []interface{}
+		for _, cluster := range clusters {
+			names, err := api.GetConnectors(cluster.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, name := range names {
+				config, err := api.GetConnectorConfig(cluster.Name, name)
+				if err != nil {
+					return nil, err
+				}
+
+				resources = append(resources, &lenses.CreateUpdateConnectorPayload{
+					ClusterName: cluster.Name,
+					Name:        name,
+					Config:      config,
+				})
+			}
+		}
+
+		return resources, nil
+	},
+	normalize: func(r interface{}) {
+		connector := r.(*lenses.CreateUpdateConnectorPayload)
+		if name, ok := connector.Config["name"]; ok && name == connector.Name {
+			delete(connector.Config, "name") // the server adds it.
+		}
+	},
+	create: func(api lenses.API, r interface{}) error {
+		connector := r.(*lenses.CreateUpdateConnectorPayload)
+		_, err := api.CreateConnector(connector.ClusterName, connector.Name, connector.Config)
+		return err
+	},
+	update: func(api lenses.API, r interface{}) error {
+		connector := r.(*lenses.CreateUpdateConnectorPayload)
+		_, err := api.UpdateConnector(connector.ClusterName, connector.Name, connector.Config)
+		return err
+	},
+	delete: func(api lenses.API, r interface{}) error {
+		connector := r.(*lenses.CreateUpdateConnectorPayload)
+		return api.DeleteConnector(connector.ClusterName, connector.Name)
+	},
+}
+
+var processorKind = &resourceKind{
+	name: "Processor",
+	new:  func() interface{} { return &lenses.CreateProcessorPayload{} },
+	id: func(r interface{}) string {
+		processor := r.(*lenses.CreateProcessorPayload)
+		if processor.Name == "" {
+			return ""
+		}
+
+		var parts []string
+		for _, part := range []string{processor.ClusterName, processor.Namespace, processor.Name} {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+		return strings.Join(parts, "/")
+	},
+	list: func(api lenses.API) ([]interface{}, error) {
+		result, err := api.GetProcessors()
+		if err != nil {
+			return nil, err
+		}
+
+		resources := make([]interface{}, 0, len(result.Streams))
+		for _, stream := range result.Streams {
+			resources = append(resources, &lenses.CreateProcessorPayload{
+				Name:        stream.Name,
+				SQL:         stream.SQL,
+				Runners:     stream.Runners,
+				ClusterName: stream.ClusterName,
+				Namespace:   stream.Namespace,
+				Pipeline:    stream.Pipeline,
+			})
+		}
+
+		return resources, nil
+	},
+	normalize: func(r interface{}) {
+		processor := r.(*lenses.CreateProcessorPayload)
+		if processor.Runners <= 0 {
+			processor.Runners = 1
+		}
+		if processor.Pipeline == "" {
+			processor.Pipeline = processor.Name
+		}
+	},
+	create: func(api lenses.API, r interface{}) error {
+		processor := r.(*lenses.CreateProcessorPayload)
+		return api.CreateProcessor(processor.Name, processor.SQL, processor.Runners, processor.ClusterName, processor.Namespace, processor.Pipeline)
+	},
+	update: func(api lenses.API, r interface{}) error {
+		processor := r.(*lenses.CreateProcessorPayload)
+		id, err := api.LookupProcessorIdentifier("", processor.Name, processor.ClusterName, processor.Namespace)
+		if err != nil {
+			return err
+		}
+
+		return api.UpdateProcessorRunners(id, processor.Runners)
+	},
+	delete: func(api lenses.API, r interface{}) error {
+		processor := r.(*lenses.CreateProcessorPayload)
+		id, err := api.LookupProcessorIdentifier("", processor.Name, processor.ClusterName, processor.Namespace)
+		if err != nil {
+			return err
+		}
+
+		return api.DeleteProcessor(id)
+	},
+	immutable:   []string{"sql", "clusterName", "namespace", "pipeline"},
+	replaceable: true,
+}
+
+var aclKind = &resourceKind{
+	name: "ACL",
+	new:  func() interface{} { return &lenses.ACL{} },
+	id: func(r interface{}) string {
+		acl := r.(*lenses.ACL)
+		if acl.ResourceName == "" {
+			return ""
+		}
+		return fmt.Sprintf("%s:%s:%s:%s:%s:%s", acl.ResourceType, acl.ResourceName, acl.Principal, acl.PermissionType, acl.Host, acl.Operation)
+	},
+	list: func(api lenses.API) ([]interface{}, error) {
+		acls, err := api.GetACLs()
+		if err != nil {
+			return nil, err
+		}
+
+		resources := make([]interface{}, len(acls))
+		for i := range acls {
+			resources[i] = &acls[i]
+		}
+
+		return resources, nil
+	},
+	create: func(api lenses.API, r interface{}) error {
+		return api.CreateOrUpdateACL(*r.(*lenses.ACL))
+	},
+	delete: func(api lenses.API, r interface{}) error {
+		return api.DeleteACL(*r.(*lenses.ACL))
+	},
+}
+
+// quotaManifest is the manifest's form of a quota, the entity fields are the same as the `lenses.Quota`'s ones.
+type quotaManifest struct {
+	EntityType lenses.QuotaEntityType `json:"entityType" yaml:"EntityType"`
+	// EntityName is the user or the client id, "<default>" for the default quotas.
+	EntityName string `json:"entityName" yaml:"EntityName"`
+	// Child is the client id of a "USERCLIENT" quota, "<default>" for all the user's clients.
+	Child  string             `json:"child,omitempty" yaml:"Child,omitempty"`
+	Config lenses.QuotaConfig `json:"config" yaml:"Config"`
+}
+
+const quotaDefaultEntityName = "<default>"
+
+// quotaProperties are the properties of the `lenses.QuotaConfig`, used to delete a quota completely.
+var quotaProperties = []string{"producer_byte_rate", "consumer_byte_rate", "request_percentage"}
+
+var quotaKind = &resourceKind{
+	name: "Quota",
+	new:  func() interface{} { return &quotaManifest{} },
+	id: func(r interface{}) string {
+		quota := r.(*quotaManifest)
+		if quota.EntityType == "" {
+			return ""
+		}
+
+		id := string(quota.EntityType) + "/" + quota.EntityName
+		if quota.Child != "" {
+			id += "/" + quota.Child
+		}
+		return id
+	},
+	list: func(api lenses.API) ([]interface{}, error) {
+		quotas, err := api.GetQuotas()
+		if err != nil {
+			return nil, err
+		}
+
+		resources := make([]interface{}, len(quotas))
+		for i, quota := range quotas {
+			resources[i] = &quotaManifest{
+				EntityType: quota.EnityType,
+				EntityName: quota.EntityName,
+				Child:      quota.Child,
+				Config:     quota.Properties,
+			}
+		}
+
+		return resources, nil
+	},
+	normalize: func(r interface{}) {
+		quota := r.(*quotaManifest)
+		switch quota.EntityType {
+		case lenses.QuotaEntityUsersDefault, lenses.QuotaEntityClientsDefault:
+			quota.EntityName = quotaDefaultEntityName
+		}
+	},
+	create: setQuota,
+	update: setQuota,
+	delete: func(api lenses.API, r interface{}) error {
+		quota := r.(*quotaManifest)
+		switch quota.EntityType {
+		case lenses.QuotaEntityUsersDefault:
+			return api.DeleteQuotaForAllUsers(quotaProperties...)
+		case lenses.QuotaEntityUser:
+			return api.DeleteQuotaForUser(quota.EntityName, quotaProperties...)
+		case lenses.QuotaEntityUserClient:
+			if quota.Child == "" || quota.Child == quotaDefaultEntityName {
+				return api.DeleteQuotaForUserAllClients(quota.EntityName, quotaProperties...)
+			}
+			return api.DeleteQuotaForUserClient(quota.EntityName, quota.Child, quotaProperties...)
+		case lenses.QuotaEntityClientsDefault:
+			return api.DeleteQuotaForAllClients(quotaProperties...)
+		case lenses.QuotaEntityClient:
+			return api.DeleteQuotaForClient(quota.EntityName, quotaProperties...)
+		default:
+			return fmt.Errorf("unknown quota entity type '%s'", quota.EntityType)
+		}
+	},
+}
+
+func setQuota(api lenses.API, r interface{}) error {
+	quota := r.(*quotaManifest)
+	switch quota.EntityType {
+	case lenses.QuotaEntityUsersDefault:
+		return api.CreateOrUpdateQuotaForAllUsers(quota.Config)
+	case lenses.QuotaEntityUser:
+		return api.CreateOrUpdateQuotaForUser(quota.EntityName, quota.Config)
+	case lenses.QuotaEntityUserClient:
+		if quota.Child == "" || quota.Child == quotaDefaultEntityName {
+			return api.CreateOrUpdateQuotaForUserAllClients(quota.EntityName, quota.Config)
+		}
+		return api.CreateOrUpdateQuotaForUserClient(quota.EntityName, quota.Child, quota.Config)
+	case lenses.QuotaEntityClientsDefault:
+		return api.CreateOrUpdateQuotaForAllClients(quota.Config)
+	case lenses.QuotaEntityClient:
+		return api.CreateOrUpdateQuotaForClient(quota.EntityName, quota.Config)
+	default:
+		return fmt.Errorf("unknown quota entity type '%s'", quota.EntityType)
+	}
+}
+
+var alertSettingConditionKind = &resourceKind{
+	name: "AlertSettingCondition",
+	new:  func() interface{} { return &alertSettingConditionPayload{} },
+	id: func(r interface{}) string {
+		cond := r.(*alertSettingConditionPayload)
+		if cond.Condition == "" {
+			return ""
+		}
+		return fmt.Sprintf("%d/%s", cond.AlertID, cond.Condition)
+	},
+	list: func(api lenses.API) ([]interface{}, error) {
+		settings, err := api.GetAlertSettings()
+		if err != nil {
+			return nil, err
+		}
+
+		var resources []interface{}
+		for _, setting := range append(settings.Categories.Infrastructure, settings.Categories.Consumers...) {
+			conds, err := api.GetAlertSettingConditions(setting.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, condition := range conds {
+				resources = append(resources, &alertSettingConditionPayload{AlertID: setting.ID, Condition: condition})
+			}
+		}
+
+		return resources, nil
+	},
+	create: func(api lenses.API, r interface{}) error {
+		cond := r.(*alertSettingConditionPayload)
+		return api.CreateOrUpdateAlertSettingCondition(cond.AlertID, cond.Condition)
+	},
+	delete: func(api lenses.API, r interface{}) error {
+		cond := r.(*alertSettingConditionPayload)
+		conds, err := api.GetAlertSettingConditions(cond.AlertID)
+		if err != nil {
+			return err
+		}
+
+		for uuid, condition := range conds {
+			if condition == cond.Condition {
+				return api.DeleteAlertSettingCondition(cond.AlertID, uuid)
+			}
+		}
+
+		return nil // already deleted.
+	},
+}
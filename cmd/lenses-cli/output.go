@@ -0,0 +1,443 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/landoop/lenses-go"
+
+	"gopkg.in/yaml.v2"
+)
+
+// The output formats that the `--output` flag accepts.
+const (
+	outputJSON   = "json"
+	outputNDJSON = "ndjson"
+	outputYAML   = "yaml"
+	outputCSV    = "csv"
+	outputTable  = "table"
+	outputWide   = "wide"
+)
+
+var outputFormats = []string{outputJSON, outputNDJSON, outputYAML, outputCSV, outputTable, outputWide}
+
+// isTabularOutput reports whether the results are printed as rows of columns, i.e a table.
+func isTabularOutput() bool {
+	return outputFormat == outputTable || outputFormat == outputWide || outputFormat == outputCSV
+}
+
+// column describes a table's column, the "path" is the json field of the printed resource, i.e "topicName"
+// or "connector.state" for the nested ones.
+type column struct {
+	header string
+	path   string
+	// wide columns are printed only by the "wide" and "csv" outputs.
+	wide bool
+}
+
+// tableColumns are the default columns per resource.
+// The resources that are not registered here are printed with the columns of their json fields.
+var tableColumns = map[reflect.Type][]column{
+	reflect.TypeOf(lenses.Topic{}): {
+		{header: "NAME", path: "topicName"},
+		{header: "PARTITIONS", path: "partitions"},
+		{header: "REPLICATION", path: "replication"},
+		{header: "MESSAGES", path: "totalMessages"},
+		{header: "MESSAGES/SEC", path: "messagesPerSecond", wide: true},
+		{header: "KEY TYPE", path: "keyType", wide: true},
+		{header: "VALUE TYPE", path: "valueType", wide: true},
+		{header: "CONTROL", path: "isControlTopic", wide: true},
+	},
+	reflect.TypeOf(connectorState{}): {
+		{header: "CLUSTER", path: "clusterName"},
+		{header: "NAME", path: "name"},
+		{header: "STATE", path: "state"},
+		{header: "TASKS", path: "tasks"},
+		{header: "CLASS", path: "class", wide: true},
+		{header: "WORKER", path: "workerId", wide: true},
+	},
+	reflect.TypeOf(lenses.ConnectorStatus{}): {
+		{header: "NAME", path: "name"},
+		{header: "STATE", path: "connector.state"},
+		{header: "WORKER", path: "connector.worker_id", wide: true},
+	},
+	reflect.TypeOf(lenses.ProcessorStream{}): {
+		{header: "NAME", path: "name"},
+		{header: "STATE", path: "deploymentState"},
+		{header: "RUNNERS", path: "runners"},
+		{header: "TO TOPIC", path: "toTopic"},
+		{header: "ID", path: "id", wide: true},
+		{header: "CLUSTER", path: "clusterName", wide: true},
+		{header: "NAMESPACE", path: "namespace", wide: true},
+		{header: "USER", path: "user", wide: true},
+		{header: "SQL", path: "sql", wide: true},
+	},
+	reflect.TypeOf(lenses.ACL{}): {
+		{header: "RESOURCE TYPE", path: "resourceType"},
+		{header: "RESOURCE NAME", path: "resourceName"},
+		{header: "PRINCIPAL", path: "principal"},
+		{header: "PERMISSION", path: "permissionType"},
+		{header: "HOST", path: "host"},
+		{header: "OPERATION", path: "operation"},
+	},
+	reflect.TypeOf(lenses.Quota{}): {
+		{header: "ENTITY TYPE", path: "entityType"},
+		{header: "ENTITY NAME", path: "entityName"},
+		{header: "CHILD", path: "child"},
+		{header: "PRODUCER BYTE RATE", path: "properties.producer_byte_rate"},
+		{header: "CONSUMER BYTE RATE", path: "properties.consumer_byte_rate"},
+		{header: "REQUEST PERCENTAGE", path: "properties.request_percentage"},
+		{header: "URL", path: "url", wide: true},
+	},
+	reflect.TypeOf(lenses.LSQLRunningQuery{}): {
+		{header: "ID", path: "id"},
+		{header: "USER", path: "user"},
+		{header: "SQL", path: "sql"},
+		{header: "TIMESTAMP", path: "ts", wide: true},
+	},
+	reflect.TypeOf(lenses.Alert{}): {
+		{header: "ID", path: "alertId"},
+		{header: "SEVERITY", path: "labels.severity"},
+		{header: "SUMMARY", path: "annotations.summary"},
+		{header: "STARTS AT", path: "startsAt"},
+		{header: "INSTANCE", path: "labels.instance", wide: true},
+		{header: "SOURCE", path: "annotations.source", wide: true},
+	},
+	reflect.TypeOf(lenses.AlertSetting{}): {
+		{header: "ID", path: "id"},
+		{header: "CATEGORY", path: "category"},
+		{header: "ENABLED", path: "enabled"},
+		{header: "DESCRIPTION", path: "description"},
+	},
+}
+
+// connectorState is the table form of a connector, see the `connectors` command.
+type connectorState struct {
+	ClusterName string `json:"clusterName"`
+	Name        string `json:"name"`
+	State       string `json:"state"`
+	Tasks       int    `json:"tasks"`
+	Class       string `json:"class"`
+	WorkerID    string `json:"workerId"`
+}
+
+// printOutput prints the "v" based on the `--output` flag, the `--query` is already applied to the "v".
+func printOutput(w io.Writer, v interface{}) error {
+	switch outputFormat {
+	case outputNDJSON:
+		return printNDJSON(w, v)
+	case outputYAML:
+		return printYAML(w, v)
+	case outputCSV, outputTable, outputWide:
+		return printTable(w, v)
+	default:
+		return fmt.Errorf("unknown output format '%s', available formats: %s", outputFormat, strings.Join(outputFormats, ", "))
+	}
+}
+
+// toGeneric converts the "v" to its json form, so the maps' keys are the json fields
+// and the numbers are `json.Number`, not float64.
+func toGeneric(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var generic interface{}
+	err = dec.Decode(&generic)
+	return generic, err
+}
+
+// items returns the elements of the "v" if it's a slice, otherwise the "v" is the only element.
+func items(v interface{}) []interface{} {
+	val := reflect.Indirect(reflect.ValueOf(v))
+	if !val.IsValid() {
+		return nil
+	}
+
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return []interface{}{v}
+	}
+
+	list := make([]interface{}, val.Len())
+	for i := range list {
+		list[i] = val.Index(i).Interface()
+	}
+
+	return list
+}
+
+func printNDJSON(w io.Writer, v interface{}) error {
+	for _, item := range items(v) {
+		b, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+
+		if _, err = fmt.Fprintln(w, string(b)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func printYAML(w io.Writer, v interface{}) error {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return err
+	}
+
+	b, err := yaml.Marshal(generic)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+func printTable(w io.Writer, v interface{}) error {
+	list := items(v)
+
+	// a map, i.e the configs, is printed as a table of keys and values.
+	if len(list) == 1 && reflect.Indirect(reflect.ValueOf(list[0])).Kind() == reflect.Map {
+		generic, err := toGeneric(list[0])
+		if err != nil {
+			return err
+		}
+
+		if m, ok := generic.(map[string]interface{}); ok {
+			list = list[:0]
+			for _, key := range sortedKeys(m) {
+				list = append(list, map[string]interface{}{"key": key, "value": m[key]})
+			}
+		}
+	}
+
+	rows := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		generic, err := toGeneric(item)
+		if err != nil {
+			return err
+		}
+
+		row, ok := generic.(map[string]interface{})
+		if !ok {
+			row = map[string]interface{}{"value": generic}
+		}
+
+		rows = append(rows, row)
+	}
+
+	rw := newRowsWriter(w)
+	if len(list) > 0 {
+		rw.columns = columnsOf(list[0], rows)
+	}
+
+	for _, row := range rows {
+		if err := rw.writeRow(row); err != nil {
+			return err
+		}
+	}
+
+	return rw.Flush()
+}
+
+// rowsWriter prints the rows of a table in the format of the `--output`, one by one,
+// the header is printed before the first row.
+// The streamed results, i.e the `sql`'s records, are printed as the rows of one table,
+// their columns are the fields of the first one.
+type rowsWriter struct {
+	w       io.Writer
+	columns []column
+	// started reports whether the header is printed.
+	started bool
+	csv     *csv.Writer
+	tw      *tabwriter.Writer
+}
+
+func newRowsWriter(w io.Writer) *rowsWriter {
+	rw := &rowsWriter{w: w}
+	if outputFormat == outputCSV {
+		rw.csv = csv.NewWriter(w)
+	} else {
+		rw.tw = tabwriter.NewWriter(w, 0, 4, 3, ' ', 0)
+	}
+
+	return rw
+}
+
+// Write prints the "v" as a row, or its elements if it's a slice.
+func (rw *rowsWriter) Write(v interface{}) error {
+	for _, item := range items(v) {
+		generic, err := toGeneric(item)
+		if err != nil {
+			return err
+		}
+
+		row, ok := generic.(map[string]interface{})
+		if !ok {
+			row = map[string]interface{}{"value": generic}
+		}
+
+		if rw.columns == nil {
+			rw.columns = columnsOf(item, []map[string]interface{}{row})
+		}
+
+		if err = rw.writeRow(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (rw *rowsWriter) writeRow(row map[string]interface{}) error {
+	withWide := outputFormat != outputTable
+
+	if !rw.started {
+		rw.started = true
+
+		var headers []string
+		for _, col := range rw.columns {
+			if col.wide && !withWide {
+				continue
+			}
+			headers = append(headers, col.header)
+		}
+
+		if len(headers) > 0 {
+			if err := rw.writeRecord(headers); err != nil {
+				return err
+			}
+		}
+	}
+
+	record := make([]string, 0, len(rw.columns))
+	for _, col := range rw.columns {
+		if col.wide && !withWide {
+			continue
+		}
+		record = append(record, formatCell(lookupPath(row, col.path)))
+	}
+
+	return rw.writeRecord(record)
+}
+
+func (rw *rowsWriter) writeRecord(record []string) error {
+	if rw.csv != nil {
+		// flush on each row, the streamed rows are printed as they come.
+		rw.csv.Write(record)
+		rw.csv.Flush()
+		return rw.csv.Error()
+	}
+
+	_, err := fmt.Fprintln(rw.tw, strings.Join(record, "\t"))
+	return err
+}
+
+// Flush prints the buffered rows of the table, they are aligned by their widths, the csv ones are already printed.
+func (rw *rowsWriter) Flush() error {
+	if rw.csv != nil {
+		rw.csv.Flush()
+		return rw.csv.Error()
+	}
+
+	return rw.tw.Flush()
+}
+
+// columnsOf returns the registered columns of the "item"'s type, if any,
+// otherwise the columns are the fields of the rows, the nested ones are wide.
+func columnsOf(item interface{}, rows []map[string]interface{}) []column {
+	if typ := reflect.TypeOf(item); typ != nil {
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+
+		if columns, ok := tableColumns[typ]; ok {
+			return columns
+		}
+	}
+
+	fields := make(map[string]bool) // field:nested.
+	for _, row := range rows {
+		for key, value := range row {
+			switch value.(type) {
+			case map[string]interface{}, []interface{}:
+				fields[key] = true
+			default:
+				if _, exists := fields[key]; !exists {
+					fields[key] = false
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	columns := make([]column, 0, len(names))
+	for _, name := range names {
+		columns = append(columns, column{header: strings.ToUpper(name), path: name, wide: fields[name]})
+	}
+
+	return columns
+}
+
+func lookupPath(row map[string]interface{}, path string) interface{} {
+	var value interface{} = row
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+
+	return value
+}
+
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(b)
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
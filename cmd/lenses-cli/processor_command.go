@@ -10,12 +10,19 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// processorsAPI is the part of the Lenses API that the processor commands depend on,
+// the execution mode is read from the Lenses configuration.
+type processorsAPI interface {
+	lenses.ProcessorsAPI
+	lenses.ConfigAPI
+}
+
 func init() {
-	rootCmd.AddCommand(newGetProcessorsCommand())
-	rootCmd.AddCommand(newProcessorGroupCommand())
+	rootCmd.AddCommand(newGetProcessorsCommand(api))
+	rootCmd.AddCommand(newProcessorGroupCommand(api))
 }
 
-func newGetProcessorsCommand() *cobra.Command {
+func newGetProcessorsCommand(api processorsAPI) *cobra.Command {
 	var name, clusterName, namespace string
 
 	cmd := &cobra.Command{
@@ -25,12 +32,12 @@ func newGetProcessorsCommand() *cobra.Command {
 		SilenceErrors:    true,
 		TraverseChildren: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			result, err := client.GetProcessors()
+			result, err := api.GetProcessors()
 			if err != nil {
 				return err
 			}
 
-			mode, err := client.GetExecutionMode()
+			mode, err := api.GetExecutionMode()
 			if err != nil {
 				return err
 			}
@@ -82,7 +89,7 @@ func newGetProcessorsCommand() *cobra.Command {
 	return cmd
 }
 
-func newProcessorGroupCommand() *cobra.Command {
+func newProcessorGroupCommand(api processorsAPI) *cobra.Command {
 	root := &cobra.Command{
 		Use:              "processor",
 		Short:            "Work with a particular processor based on the processor id; pause, resume, update runners, delete or create a new processor",
@@ -92,16 +99,16 @@ func newProcessorGroupCommand() *cobra.Command {
 	}
 
 	// subcommands
-	root.AddCommand(newProcessorCreateCommand())
-	root.AddCommand(newProcessorPauseCommand())
-	root.AddCommand(newProcessorResumeCommand())
-	root.AddCommand(newProcessorUpdateRunnersCommand())
-	root.AddCommand(newProcessorDeleteCommand())
+	root.AddCommand(newProcessorCreateCommand(api))
+	root.AddCommand(newProcessorPauseCommand(api))
+	root.AddCommand(newProcessorResumeCommand(api))
+	root.AddCommand(newProcessorUpdateRunnersCommand(api))
+	root.AddCommand(newProcessorDeleteCommand(api))
 
 	return root
 }
 
-func newProcessorCreateCommand() *cobra.Command {
+func newProcessorCreateCommand(api processorsAPI) *cobra.Command {
 	// the processorName and sql are the required.
 	var processor lenses.CreateProcessorPayload
 
@@ -116,7 +123,7 @@ func newProcessorCreateCommand() *cobra.Command {
 				return err
 			}
 
-			err := client.CreateProcessor(processor.Name, processor.SQL, processor.Runners, processor.ClusterName, processor.Namespace, processor.Pipeline)
+			err := api.CreateProcessor(processor.Name, processor.SQL, processor.Runners, processor.ClusterName, processor.Namespace, processor.Pipeline)
 
 			if err != nil {
 				return err
@@ -139,7 +146,7 @@ func newProcessorCreateCommand() *cobra.Command {
 	return cmd
 }
 
-func newProcessorPauseCommand() *cobra.Command {
+func newProcessorPauseCommand(api processorsAPI) *cobra.Command {
 	var processorID, processorName, clusterName, namespace string
 
 	cmd := &cobra.Command{
@@ -149,12 +156,12 @@ func newProcessorPauseCommand() *cobra.Command {
 		SilenceErrors:    true,
 		TraverseChildren: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			identifier, err := client.LookupProcessorIdentifier(processorID, processorName, clusterName, namespace)
+			identifier, err := api.LookupProcessorIdentifier(processorID, processorName, clusterName, namespace)
 			if err != nil {
 				return err
 			}
 
-			if err := client.PauseProcessor(identifier); err != nil {
+			if err := api.PauseProcessor(identifier); err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("unable to pause, processor '%s' does not exist", identifier)
 				return err
 			}
@@ -172,7 +179,7 @@ func newProcessorPauseCommand() *cobra.Command {
 	return cmd
 }
 
-func newProcessorResumeCommand() *cobra.Command {
+func newProcessorResumeCommand(api processorsAPI) *cobra.Command {
 	var processorID, processorName, clusterName, namespace string
 
 	cmd := &cobra.Command{
@@ -182,12 +189,12 @@ func newProcessorResumeCommand() *cobra.Command {
 		SilenceErrors:    true,
 		TraverseChildren: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			identifier, err := client.LookupProcessorIdentifier(processorID, processorName, clusterName, namespace)
+			identifier, err := api.LookupProcessorIdentifier(processorID, processorName, clusterName, namespace)
 			if err != nil {
 				return err
 			}
 
-			if err := client.ResumeProcessor(identifier); err != nil {
+			if err := api.ResumeProcessor(identifier); err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("unable to resume, processor '%s' does not exist", identifier)
 				return err
 			}
@@ -205,7 +212,7 @@ func newProcessorResumeCommand() *cobra.Command {
 	return cmd
 }
 
-func newProcessorUpdateRunnersCommand() *cobra.Command {
+func newProcessorUpdateRunnersCommand(api processorsAPI) *cobra.Command {
 
 	var (
 		runners                                            int
@@ -223,12 +230,12 @@ func newProcessorUpdateRunnersCommand() *cobra.Command {
 				return err
 			}
 
-			identifier, err := client.LookupProcessorIdentifier(processorID, processorName, clusterName, namespace)
+			identifier, err := api.LookupProcessorIdentifier(processorID, processorName, clusterName, namespace)
 			if err != nil {
 				return err
 			}
 
-			if err := client.UpdateProcessorRunners(identifier, runners); err != nil {
+			if err := api.UpdateProcessorRunners(identifier, runners); err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("unable to scale to %d runners, processor '%s' does not exist", runners, identifier)
 				return err
 			}
@@ -248,7 +255,7 @@ func newProcessorUpdateRunnersCommand() *cobra.Command {
 	return cmd
 }
 
-func newProcessorDeleteCommand() *cobra.Command {
+func newProcessorDeleteCommand(api processorsAPI) *cobra.Command {
 	var processorID, processorName, clusterName, namespace string
 
 	cmd := &cobra.Command{
@@ -258,13 +265,13 @@ func newProcessorDeleteCommand() *cobra.Command {
 		SilenceErrors:    true,
 		TraverseChildren: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			identifier, err := client.LookupProcessorIdentifier(processorID, processorName, clusterName, namespace)
+			identifier, err := api.LookupProcessorIdentifier(processorID, processorName, clusterName, namespace)
 			if err != nil {
 				return err
 			}
 
 			// delete the processor based on the identifier, based on the current running mode.
-			if err := client.DeleteProcessor(identifier); err != nil {
+			if err := api.DeleteProcessor(identifier); err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("unable to delete, processor '%s' does not exist", identifier)
 				return err
 			}
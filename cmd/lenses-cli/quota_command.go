@@ -9,11 +9,11 @@ import (
 )
 
 func init() {
-	rootCmd.AddCommand(newGetQuotasCommand())
-	rootCmd.AddCommand(newQuotaGroupCommand())
+	rootCmd.AddCommand(newGetQuotasCommand(api))
+	rootCmd.AddCommand(newQuotaGroupCommand(api))
 }
 
-func newGetQuotasCommand() *cobra.Command {
+func newGetQuotasCommand(api lenses.QuotaAPI) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:              "quotas",
 		Short:            "List of all available quotas",
@@ -22,13 +22,13 @@ func newGetQuotasCommand() *cobra.Command {
 	}
 
 	shouldPrintJSON(cmd, func() (interface{}, error) {
-		return client.GetQuotas()
+		return api.GetQuotas()
 	})
 
 	return cmd
 }
 
-func newQuotaGroupCommand() *cobra.Command {
+func newQuotaGroupCommand(api lenses.QuotaAPI) *cobra.Command {
 	root := &cobra.Command{
 		Use:              "quota",
 		Short:            "Work with particular a quota, create a new quota or update and delete an existing one",
@@ -37,8 +37,8 @@ func newQuotaGroupCommand() *cobra.Command {
 		SilenceErrors:    true,
 	}
 
-	root.AddCommand(newQuotaUsersSubGroupCommand())
-	root.AddCommand(newQuotaClientsSubGroupCommand())
+	root.AddCommand(newQuotaUsersSubGroupCommand(api))
+	root.AddCommand(newQuotaClientsSubGroupCommand(api))
 
 	return root
 }
@@ -52,7 +52,7 @@ type createQuotaPayload struct {
 	ClientID string `yaml:"Client"`
 }
 
-func newQuotaUsersSubGroupCommand() *cobra.Command {
+func newQuotaUsersSubGroupCommand(api lenses.QuotaAPI) *cobra.Command {
 	var (
 		configRaw string
 		quota     createQuotaPayload
@@ -81,7 +81,7 @@ func newQuotaUsersSubGroupCommand() *cobra.Command {
 			if quota.User != "" {
 				if clientID := quota.ClientID; clientID != "" {
 					if clientID == "all" || clientID == "*" {
-						if err := client.CreateOrUpdateQuotaForUserAllClients(quota.User, quota.Config); err != nil {
+						if err := api.CreateOrUpdateQuotaForUserAllClients(quota.User, quota.Config); err != nil {
 							return err
 						}
 
@@ -89,21 +89,21 @@ func newQuotaUsersSubGroupCommand() *cobra.Command {
 
 					}
 
-					if err := client.CreateOrUpdateQuotaForUserClient(quota.User, clientID, quota.Config); err != nil {
+					if err := api.CreateOrUpdateQuotaForUserClient(quota.User, clientID, quota.Config); err != nil {
 						return err
 					}
 
 					return echo(cmd, "Quota for user %s and client %s set", quota.User, clientID)
 				}
 
-				if err := client.CreateOrUpdateQuotaForUser(quota.User, quota.Config); err != nil {
+				if err := api.CreateOrUpdateQuotaForUser(quota.User, quota.Config); err != nil {
 					return err
 				}
 
 				return echo(cmd, "Quota for user %s created/updated", quota.User)
 			}
 
-			if err := client.CreateOrUpdateQuotaForAllUsers(quota.Config); err != nil {
+			if err := api.CreateOrUpdateQuotaForAllUsers(quota.Config); err != nil {
 				return err
 			}
 
@@ -138,7 +138,7 @@ func newQuotaUsersSubGroupCommand() *cobra.Command {
 			if user != "" {
 				if clientID != "" {
 					if clientID == "all" || clientID == "*" {
-						if err := client.DeleteQuotaForUserAllClients(user, args...); err != nil {
+						if err := api.DeleteQuotaForUserAllClients(user, args...); err != nil {
 							errResourceNotFoundMessage = fmt.Sprintf("unable to %s, quota for user: '%s' does not exist", actionMsg, user)
 							return err
 						}
@@ -146,7 +146,7 @@ func newQuotaUsersSubGroupCommand() *cobra.Command {
 						return echo(cmd, "Quota for user %s deleted for all clients", user)
 					}
 
-					if err := client.DeleteQuotaForUserClient(user, clientID, args...); err != nil {
+					if err := api.DeleteQuotaForUserClient(user, clientID, args...); err != nil {
 						errResourceNotFoundMessage = fmt.Sprintf("unable to %s, quota for user: '%s' and client: '%s' does not exist", actionMsg, user, clientID)
 						return err
 					}
@@ -154,7 +154,7 @@ func newQuotaUsersSubGroupCommand() *cobra.Command {
 					return echo(cmd, "Quota for user %s deleted for client %s", user, clientID)
 				}
 
-				if err := client.DeleteQuotaForUser(user, args...); err != nil {
+				if err := api.DeleteQuotaForUser(user, args...); err != nil {
 					errResourceNotFoundMessage = fmt.Sprintf("unable to %s, quota for user: '%s' does not exist", actionMsg, user)
 					return err
 				}
@@ -162,7 +162,7 @@ func newQuotaUsersSubGroupCommand() *cobra.Command {
 				return echo(cmd, "Quota for user %s %sd", user, actionMsg)
 			}
 
-			if err := client.DeleteQuotaForAllUsers(args...); err != nil {
+			if err := api.DeleteQuotaForAllUsers(args...); err != nil {
 				return err
 			}
 
@@ -178,7 +178,7 @@ func newQuotaUsersSubGroupCommand() *cobra.Command {
 	return rootSub
 }
 
-func newQuotaClientsSubGroupCommand() *cobra.Command {
+func newQuotaClientsSubGroupCommand(api lenses.QuotaAPI) *cobra.Command {
 	var (
 		configRaw string
 		quota     createQuotaPayload
@@ -205,14 +205,14 @@ func newQuotaClientsSubGroupCommand() *cobra.Command {
 			}
 
 			if id := quota.ClientID; id != "" && id != "all" && id != "*" {
-				if err := client.CreateOrUpdateQuotaForClient(quota.ClientID, quota.Config); err != nil {
+				if err := api.CreateOrUpdateQuotaForClient(quota.ClientID, quota.Config); err != nil {
 					return err
 				}
 
 				return echo(cmd, "Quota for client %s created/updated", quota.ClientID)
 			}
 
-			if err := client.CreateOrUpdateQuotaForAllClients(quota.Config); err != nil {
+			if err := api.CreateOrUpdateQuotaForAllClients(quota.Config); err != nil {
 				return err
 			}
 
@@ -240,7 +240,7 @@ func newQuotaClientsSubGroupCommand() *cobra.Command {
 			}
 
 			if id := quota.ClientID; id != "" && id != "all" && id != "*" {
-				if err := client.DeleteQuotaForClient(id, args...); err != nil {
+				if err := api.DeleteQuotaForClient(id, args...); err != nil {
 					errResourceNotFoundMessage = fmt.Sprintf("unable to %s, quota for client: '%s' does not exist", actionMsg, id)
 					return err
 				}
@@ -248,7 +248,7 @@ func newQuotaClientsSubGroupCommand() *cobra.Command {
 				return echo(cmd, "Quota for client %s %sd", id, actionMsg)
 			}
 
-			if err := client.DeleteQuotaForAllClients(args...); err != nil {
+			if err := api.DeleteQuotaForAllClients(args...); err != nil {
 				return err
 			}
 
@@ -12,11 +12,11 @@ import (
 )
 
 func init() {
-	rootCmd.AddCommand(newSchemasGroupCommand())
-	rootCmd.AddCommand(newSchemaGroupCommand())
+	rootCmd.AddCommand(newSchemasGroupCommand(api))
+	rootCmd.AddCommand(newSchemaGroupCommand(api))
 }
 
-func newSchemasGroupCommand() *cobra.Command {
+func newSchemasGroupCommand(api lenses.SchemaRegistryAPI) *cobra.Command {
 	var noJSON bool
 
 	root := &cobra.Command{
@@ -25,7 +25,7 @@ func newSchemasGroupCommand() *cobra.Command {
 		Example:       exampleString("schemas"),
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			subjects, err := client.GetSubjects()
+			subjects, err := api.GetSubjects()
 			if err != nil {
 				return err
 			}
@@ -44,12 +44,12 @@ func newSchemasGroupCommand() *cobra.Command {
 	canPrintJSON(root)
 
 	root.Flags().BoolVar(&noJSON, "no-json", false, "disable json printing, prints only the names as a list of strings")
-	root.AddCommand(newGlobalCompatibilityLevelGroupCommand())
+	root.AddCommand(newGlobalCompatibilityLevelGroupCommand(api))
 
 	return root
 }
 
-func newGlobalCompatibilityLevelGroupCommand() *cobra.Command {
+func newGlobalCompatibilityLevelGroupCommand(api lenses.SchemaRegistryAPI) *cobra.Command {
 	rootSub := &cobra.Command{
 		Use:              "compatibility [?set [compatibility]]",
 		Short:            "Get the global compatibility level",
@@ -57,7 +57,7 @@ func newGlobalCompatibilityLevelGroupCommand() *cobra.Command {
 		SilenceErrors:    true,
 		TraverseChildren: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			lv, err := client.GetGlobalCompatibilityLevel()
+			lv, err := api.GetGlobalCompatibilityLevel()
 			if err != nil {
 				return err
 			}
@@ -67,12 +67,12 @@ func newGlobalCompatibilityLevelGroupCommand() *cobra.Command {
 		},
 	}
 
-	rootSub.AddCommand(newUpdateGlobalCompatibilityLevelCommand())
+	rootSub.AddCommand(newUpdateGlobalCompatibilityLevelCommand(api))
 	return rootSub
 
 }
 
-func newUpdateGlobalCompatibilityLevelCommand() *cobra.Command {
+func newUpdateGlobalCompatibilityLevelCommand(api lenses.SchemaRegistryAPI) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:           "set",
 		Short:         "Change the global compatibility level",
@@ -87,7 +87,7 @@ func newUpdateGlobalCompatibilityLevelCommand() *cobra.Command {
 				return fmt.Errorf("compatibility value is not valid, use one of those: %s", joinValidCompatibilityLevels(", "))
 			}
 
-			if err := client.UpdateGlobalCompatibilityLevel(lenses.CompatibilityLevel(lv)); err != nil {
+			if err := api.UpdateGlobalCompatibilityLevel(lenses.CompatibilityLevel(lv)); err != nil {
 				return err
 			}
 
@@ -104,7 +104,7 @@ func newUpdateGlobalCompatibilityLevelCommand() *cobra.Command {
 // --name="..." --version=1
 // --name="..." == --name="..." --version="latest"
 // register --name="..." --avro="..."
-func newSchemaGroupCommand() *cobra.Command {
+func newSchemaGroupCommand(api lenses.SchemaRegistryAPI) *cobra.Command {
 	var (
 		name               string
 		versionStringOrInt string
@@ -148,17 +148,17 @@ func newSchemaGroupCommand() *cobra.Command {
 	canPrintJSON(root)
 
 	// subcommands.
-	root.AddCommand(newRegisterSchemaCommand())
-	root.AddCommand(newGetSchemaVersionsCommand())
-	root.AddCommand(newDeleteSchemaCommand())
-	root.AddCommand(newDeleteSchemaVersionCommand())
-	root.AddCommand(newSchemaCompatibilityLevelGroupCommand()) // includes subcommands.
+	root.AddCommand(newRegisterSchemaCommand(api))
+	root.AddCommand(newGetSchemaVersionsCommand(api))
+	root.AddCommand(newDeleteSchemaCommand(api))
+	root.AddCommand(newDeleteSchemaVersionCommand(api))
+	root.AddCommand(newSchemaCompatibilityLevelGroupCommand(api)) // includes subcommands.
 
 	return root
 }
 
 func getSchemaByID(cmd *cobra.Command, id int) error {
-	result, err := client.GetSchema(id)
+	result, err := api.GetSchema(id)
 	if err != nil {
 		return err
 	}
@@ -191,10 +191,10 @@ func getSchemaByVersion(cmd *cobra.Command, name, versionStringOrInt string, pre
 
 	readSchema := func(versionStringOrInt string) (schema lenses.Schema, err error) {
 		err = latestOrInt(name, versionStringOrInt, func(_ string) error {
-			schema, err = client.GetLatestSchema(name)
+			schema, err = api.GetLatestSchema(name)
 			return err
 		}, func(version int) error {
-			schema, err = client.GetSchemaAtVersion(name, version)
+			schema, err = api.GetSchemaAtVersion(name, version)
 			return err
 		})
 
@@ -217,7 +217,7 @@ func getSchemaByVersion(cmd *cobra.Command, name, versionStringOrInt string, pre
 	}{schema, rawJSONSchema})
 }
 
-func newRegisterSchemaCommand() *cobra.Command {
+func newRegisterSchemaCommand(api lenses.SchemaRegistryAPI) *cobra.Command {
 	var schema lenses.Schema
 
 	cmd := &cobra.Command{
@@ -231,7 +231,7 @@ func newRegisterSchemaCommand() *cobra.Command {
 				return err
 			}
 
-			id, err := client.RegisterSchema(schema.Name, schema.AvroSchema)
+			id, err := api.RegisterSchema(schema.Name, schema.AvroSchema)
 			if err != nil {
 				return err
 			}
@@ -249,7 +249,7 @@ func newRegisterSchemaCommand() *cobra.Command {
 	return cmd
 }
 
-func newGetSchemaVersionsCommand() *cobra.Command {
+func newGetSchemaVersionsCommand(api lenses.SchemaRegistryAPI) *cobra.Command {
 	var name string
 
 	cmd := &cobra.Command{
@@ -262,7 +262,7 @@ func newGetSchemaVersionsCommand() *cobra.Command {
 				return err
 			}
 
-			versions, err := client.GetSubjectVersions(name)
+			versions, err := api.GetSubjectVersions(name)
 			if err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("schema with name: '%s` does not exist", name)
 				return err
@@ -279,7 +279,7 @@ func newGetSchemaVersionsCommand() *cobra.Command {
 	return cmd
 }
 
-func newDeleteSchemaCommand() *cobra.Command {
+func newDeleteSchemaCommand(api lenses.SchemaRegistryAPI) *cobra.Command {
 	var name string
 
 	cmd := &cobra.Command{
@@ -292,7 +292,7 @@ func newDeleteSchemaCommand() *cobra.Command {
 				return err
 			}
 
-			deletedVersions, err := client.DeleteSubject(name)
+			deletedVersions, err := api.DeleteSubject(name)
 			if err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("schema with name: '%s` does not exist", name)
 				return err
@@ -312,7 +312,7 @@ func newDeleteSchemaCommand() *cobra.Command {
 	return cmd
 }
 
-func newDeleteSchemaVersionCommand() *cobra.Command {
+func newDeleteSchemaVersionCommand(api lenses.SchemaRegistryAPI) *cobra.Command {
 	var name, versionStringOrInt string
 
 	cmd := &cobra.Command{
@@ -331,11 +331,11 @@ func newDeleteSchemaVersionCommand() *cobra.Command {
 			)
 
 			err = latestOrInt(name, versionStringOrInt, func(_ string) error {
-				deletedVersion, err = client.DeleteLatestSubjectVersion(name)
+				deletedVersion, err = api.DeleteLatestSubjectVersion(name)
 				return err
 			}, func(version int) error {
 
-				deletedVersion, err = client.DeleteSubjectVersion(name, version)
+				deletedVersion, err = api.DeleteSubjectVersion(name, version)
 				return err
 			})
 
@@ -371,7 +371,7 @@ func joinValidCompatibilityLevels(sep string) string {
 	return b.String()
 }
 
-func newSchemaCompatibilityLevelGroupCommand() *cobra.Command {
+func newSchemaCompatibilityLevelGroupCommand(api lenses.SchemaRegistryAPI) *cobra.Command {
 	var name string
 	rootSub := &cobra.Command{
 		Use:              "compatibility [?set [compatibility]]",
@@ -384,7 +384,7 @@ func newSchemaCompatibilityLevelGroupCommand() *cobra.Command {
 				return err
 			}
 
-			lv, err := client.GetSubjectCompatibilityLevel(name)
+			lv, err := api.GetSubjectCompatibilityLevel(name)
 			if err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("unable retrieve the compatibility level, schema '%s' does not exist", name)
 				return err
@@ -396,12 +396,12 @@ func newSchemaCompatibilityLevelGroupCommand() *cobra.Command {
 
 	rootSub.Flags().StringVar(&name, "name", "", `--name="name"`)
 
-	rootSub.AddCommand(newUpdateSchemaCompatibilityLevelCommand())
+	rootSub.AddCommand(newUpdateSchemaCompatibilityLevelCommand(api))
 
 	return rootSub
 }
 
-func newUpdateSchemaCompatibilityLevelCommand() *cobra.Command {
+func newUpdateSchemaCompatibilityLevelCommand(api lenses.SchemaRegistryAPI) *cobra.Command {
 	var name string
 
 	cmd := &cobra.Command{
@@ -423,7 +423,7 @@ func newUpdateSchemaCompatibilityLevelCommand() *cobra.Command {
 				return fmt.Errorf("compatibility value is not valid, use one of those %s", joinValidCompatibilityLevels(", "))
 			}
 
-			if err := client.UpdateSubjectCompatibilityLevel(name, lenses.CompatibilityLevel(lv)); err != nil {
+			if err := api.UpdateSubjectCompatibilityLevel(name, lenses.CompatibilityLevel(lv)); err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("unable to change the compatibility level of the schema, schema '%s' does not exist", name)
 				return err
 			}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+var (
+	// templateText is a go template to print the results with, i.e `{{range .}}{{.TopicName}}{{"\n"}}{{end}}`.
+	// It's not a global flag, but it's a common one, all commands that return results
+	// set that via command flag binding.
+	templateText string
+	// templateFile same as `templateText` but the template is loaded from a file.
+	templateFile string
+)
+
+// templateFuncs are the helper functions that the `--template` and `--template-file` can use.
+var templateFuncs = template.FuncMap{
+	"join":  joinValues,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"humanizeBytes": humanizeBytes,
+	"millis":        formatUnixMillis,
+}
+
+// hasTemplate reports whether the results should be printed through a go template.
+func hasTemplate() bool {
+	return templateText != "" || templateFile != ""
+}
+
+var (
+	parsedTemplateMu sync.Mutex
+	// parsedTemplate is the `templateText` or the `templateFile` parsed by the first `printTemplate`,
+	// the results that are printed one by one, i.e the query's records, use the same one.
+	parsedTemplate *template.Template
+	// parsedTemplateFrom are the `templateText` and the `templateFile` of the `parsedTemplate`,
+	// it's parsed again if they are changed, i.e when more than one command runs in the same process.
+	parsedTemplateFrom [2]string
+)
+
+func printTemplate(w io.Writer, v interface{}) error {
+	tmpl, err := getTemplate()
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, v)
+}
+
+// getTemplate returns the cached `parsedTemplate` or it reads and parses the `templateText` or the `templateFile`.
+func getTemplate() (*template.Template, error) {
+	parsedTemplateMu.Lock()
+	defer parsedTemplateMu.Unlock()
+
+	from := [2]string{templateText, templateFile}
+	if parsedTemplate != nil && parsedTemplateFrom == from {
+		return parsedTemplate, nil
+	}
+
+	text := templateText
+	if templateFile != "" {
+		b, err := ioutil.ReadFile(templateFile)
+		if err != nil {
+			return nil, err
+		}
+		text = string(b)
+	}
+
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTemplate, parsedTemplateFrom = tmpl, from
+	return tmpl, nil
+}
+
+// joinValues joins the elements of any slice, i.e []string or []int, with the "sep".
+func joinValues(sep string, list interface{}) string {
+	val := reflect.ValueOf(list)
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return fmt.Sprintf("%v", list)
+	}
+
+	values := make([]string, val.Len())
+	for i := range values {
+		values[i] = fmt.Sprintf("%v", val.Index(i).Interface())
+	}
+
+	return strings.Join(values, sep)
+}
+
+// humanizeBytes returns the size in bytes as a human readable text, i.e 1.5 KiB.
+func humanizeBytes(size interface{}) string {
+	n, err := toInt64(size)
+	if err != nil {
+		return fmt.Sprintf("%v", size)
+	}
+
+	const unit = 1024
+	if n < unit && n > -unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit || m <= -unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// formatUnixMillis formats the unix milliseconds, i.e the `Topic.Timestamp`, using the RFC3339 or the optional "layout".
+func formatUnixMillis(millis interface{}, layout ...string) string {
+	n, err := toInt64(millis)
+	if err != nil {
+		return fmt.Sprintf("%v", millis)
+	}
+
+	format := time.RFC3339
+	if len(layout) > 0 {
+		format = layout[0]
+	}
+
+	return time.Unix(0, n*int64(time.Millisecond)).UTC().Format(format)
+}
+
+func toInt64(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case json.Number:
+		return n.Int64()
+	case float32:
+		return int64(n), nil
+	case float64:
+		return int64(n), nil
+	}
+
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return val.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(val.Uint()), nil
+	}
+
+	return 0, fmt.Errorf("not a number: %v", v)
+}
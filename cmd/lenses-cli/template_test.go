@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestPrintTemplateFileParsedOnce(t *testing.T) {
+	f, err := ioutil.TempFile("", "lenses-cli-template")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err = f.WriteString(`{{upper .}}{{"\n"}}`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	templateFile = f.Name()
+	defer func() { templateFile = "" }()
+
+	out := new(bytes.Buffer)
+	if err = printTemplate(out, "first"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the next results are printed with the parsed template, the file is not read again.
+	if err = os.Remove(f.Name()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = printTemplate(out, "second"); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "FIRST\nSECOND\n", out.String(); expected != got {
+		t.Fatalf("expected output:\n%s\nbut got:\n%s", expected, got)
+	}
+
+	// a different template is parsed again.
+	templateFile, templateText = "", "{{lower .}}"
+	defer func() { templateText = "" }()
+
+	out.Reset()
+	if err = printTemplate(out, "THIRD"); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "third", out.String(); expected != got {
+		t.Fatalf("expected output:\n%s\nbut got:\n%s", expected, got)
+	}
+}
@@ -10,11 +10,11 @@ import (
 )
 
 func init() {
-	rootCmd.AddCommand(newTopicsCommand())
-	rootCmd.AddCommand(newTopicGroupCommand())
+	rootCmd.AddCommand(newTopicsCommand(api))
+	rootCmd.AddCommand(newTopicGroupCommand(api))
 }
 
-func newTopicsCommand() *cobra.Command {
+func newTopicsCommand(api lenses.TopicsAPI) *cobra.Command {
 	var namesOnly, noJSON bool
 
 	cmd := &cobra.Command{
@@ -24,7 +24,7 @@ func newTopicsCommand() *cobra.Command {
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if namesOnly {
-				topicNames, err := client.GetTopicsNames()
+				topicNames, err := api.GetTopicsNames()
 				if err != nil {
 					return err
 				}
@@ -40,7 +40,7 @@ func newTopicsCommand() *cobra.Command {
 				return printJSON(cmd, outlineStringResults("name", topicNames))
 			}
 
-			topics, err := client.GetTopics()
+			topics, err := api.GetTopics()
 			if err != nil {
 				return err
 			}
@@ -60,7 +60,7 @@ func newTopicsCommand() *cobra.Command {
 	return cmd
 }
 
-func newTopicGroupCommand() *cobra.Command {
+func newTopicGroupCommand(api lenses.TopicsAPI) *cobra.Command {
 	var topicName string
 
 	root := &cobra.Command{
@@ -75,7 +75,7 @@ func newTopicGroupCommand() *cobra.Command {
 			}
 
 			// default is the retrieval of the particular topic info.
-			topic, err := client.GetTopic(topicName)
+			topic, err := api.GetTopic(topicName)
 			if err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("topic with name: '%s' does not exist", topicName)
 				return err
@@ -89,14 +89,14 @@ func newTopicGroupCommand() *cobra.Command {
 	canPrintJSON(root)
 
 	// subcommands
-	root.AddCommand(newTopicCreateCommand())
-	root.AddCommand(newTopicDeleteCommand())
-	root.AddCommand(newTopicUpdateCommand())
+	root.AddCommand(newTopicCreateCommand(api))
+	root.AddCommand(newTopicDeleteCommand(api))
+	root.AddCommand(newTopicUpdateCommand(api))
 
 	return root
 }
 
-func newTopicCreateCommand() *cobra.Command {
+func newTopicCreateCommand(api lenses.TopicsAPI) *cobra.Command {
 	var (
 		configsRaw string
 		topic      = lenses.CreateTopicPayload{
@@ -117,7 +117,7 @@ func newTopicCreateCommand() *cobra.Command {
 				return err
 			}
 
-			if err := client.CreateTopic(topic.TopicName, topic.Replication, topic.Partitions, topic.Configs); err != nil {
+			if err := api.CreateTopic(topic.TopicName, topic.Replication, topic.Partitions, topic.Configs); err != nil {
 				return err
 			}
 
@@ -137,7 +137,7 @@ func newTopicCreateCommand() *cobra.Command {
 	return cmd
 }
 
-func newTopicDeleteCommand() *cobra.Command {
+func newTopicDeleteCommand(api lenses.TopicsAPI) *cobra.Command {
 	var topicName string
 
 	cmd := &cobra.Command{
@@ -151,7 +151,7 @@ func newTopicDeleteCommand() *cobra.Command {
 				return err
 			}
 
-			if err := client.DeleteTopic(topicName); err != nil {
+			if err := api.DeleteTopic(topicName); err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("unable to delete, topic '%s' does not exist", topicName)
 				return err
 			}
@@ -166,7 +166,7 @@ func newTopicDeleteCommand() *cobra.Command {
 	return cmd
 }
 
-func newTopicUpdateCommand() *cobra.Command {
+func newTopicUpdateCommand(api lenses.TopicsAPI) *cobra.Command {
 	var (
 		configsArrayRaw string
 		topic           = lenses.UpdateTopicPayload{
@@ -185,7 +185,7 @@ func newTopicUpdateCommand() *cobra.Command {
 				return err
 			}
 
-			if err := client.UpdateTopic(topic.Name, topic.Configs); err != nil {
+			if err := api.UpdateTopic(topic.Name, topic.Configs); err != nil {
 				errResourceNotFoundMessage = fmt.Sprintf("unable to update configs, topic '%s' does not exist", topic.Name)
 				return err
 			}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/landoop/lenses-go"
+	"github.com/landoop/lenses-go/lensestest"
+)
+
+func TestTopicsCommandNames(t *testing.T) {
+	api := &lensestest.FakeTopicsAPI{
+		GetTopicsNamesContextFunc: func(ctx context.Context) ([]string, error) {
+			return []string{"topic2", "topic1"}, nil
+		},
+		GetTopicsContextFunc: func(ctx context.Context) ([]lenses.Topic, error) {
+			t.Fatal("expected only the topics' names to be requested")
+			return nil, nil
+		},
+	}
+
+	out := new(bytes.Buffer)
+	cmd := newTopicsCommand(api)
+	cmd.SetOutput(out)
+	cmd.SetArgs([]string{"--names", "--no-json"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "topic1\ntopic2\n", out.String(); expected != got {
+		t.Fatalf("expected output:\n%s\nbut got:\n%s", expected, got)
+	}
+}
+
+func TestTopicsCommandOutput(t *testing.T) {
+	api := &lensestest.FakeTopicsAPI{
+		GetTopicsContextFunc: func(ctx context.Context) ([]lenses.Topic, error) {
+			return []lenses.Topic{
+				{TopicName: "topic2", Partitions: 3, Replication: 1, TotalMessages: 10},
+				{TopicName: "topic1", Partitions: 1, Replication: 2, TotalMessages: 9007199254740993},
+			}, nil
+		},
+	}
+
+	defer func() { outputFormat, jmespathQuery = outputJSON, "" }()
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"--output", "table"}, "NAME     PARTITIONS   REPLICATION   MESSAGES\ntopic1   1            2             9007199254740993\ntopic2   3            1             10\n"},
+		{[]string{"-o", "csv", "--query", "[?TopicName == 'topic2']"}, "NAME,PARTITIONS,REPLICATION,MESSAGES,MESSAGES/SEC,KEY TYPE,VALUE TYPE,CONTROL\ntopic2,3,1,10,0,,,false\n"},
+		{[]string{"-o", "ndjson", "--query", "[].TopicName"}, "\"topic1\"\n\"topic2\"\n"},
+		{[]string{"-o", "yaml", "--query", "[0].{name: TopicName}"}, "name: topic1\n"},
+	}
+
+	for _, tt := range tests {
+		out := new(bytes.Buffer)
+		cmd := newTopicsCommand(api)
+		cmd.SetOutput(out)
+		cmd.SetArgs(tt.args)
+
+		if err := cmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := out.String(); tt.expected != got {
+			t.Fatalf("[%v] expected output:\n%s\nbut got:\n%s", tt.args, tt.expected, got)
+		}
+	}
+}
+
+func TestTopicsCommandTemplate(t *testing.T) {
+	api := &lensestest.FakeTopicsAPI{
+		GetTopicsContextFunc: func(ctx context.Context) ([]lenses.Topic, error) {
+			return []lenses.Topic{
+				{TopicName: "topic1", Partitions: 1, Timestamp: 1500000000000},
+			}, nil
+		},
+	}
+
+	defer func() { templateText = "" }()
+
+	out := new(bytes.Buffer)
+	cmd := newTopicsCommand(api)
+	cmd.SetOutput(out)
+	cmd.SetArgs([]string{"--template", `{{range .}}{{upper .TopicName}} {{.Partitions}} {{millis .Timestamp}} {{humanizeBytes 1536}}{{"\n"}}{{end}}`})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "TOPIC1 1 2017-07-14T02:40:00Z 1.5 KiB\n", out.String(); expected != got {
+		t.Fatalf("expected output:\n%s\nbut got:\n%s", expected, got)
+	}
+}
@@ -2,14 +2,15 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"os/signal"
-	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/landoop/lenses-go"
 
@@ -21,7 +22,7 @@ func init() {
 	rootCmd.AddCommand(newLiveLSQLCommand())
 }
 
-func readAndQuoteQueries(args []string) ([]string, error) {
+func readLiveQueries(args []string) ([]string, error) {
 	if n := len(args); n > 0 {
 		queries := make([]string, n, n)
 		for i, arg := range args {
@@ -32,7 +33,7 @@ func readAndQuoteQueries(args []string) ([]string, error) {
 			// replace all new line with spaces and trim any trailing space.
 			query = bytes.Replace(query, []byte("\n"), []byte(" "), -1)
 			query = bytes.TrimSpace(query)
-			queries[i] = strconv.Quote(string(query))
+			queries[i] = string(query)
 		}
 		return queries, nil
 	}
@@ -48,10 +49,13 @@ func readAndQuoteQueries(args []string) ([]string, error) {
 		return nil, fmt.Errorf("sql argument is missing and input pipe has no data to read from")
 	}
 
-	query := strconv.Quote(string(b))
-	return []string{query}, nil
+	return []string{string(b)}, nil
 }
 
+// liveHeartbeatTimeout is the time without any message, including the server's heartbeats,
+// that the `live sql` considers the connection lost and reconnects.
+const liveHeartbeatTimeout = time.Minute
+
 func newLiveLSQLCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:              "live sql [query]",
@@ -85,7 +89,7 @@ func newLiveLSQLCommand() *cobra.Command {
 			if len(queryArgs) == 0 {
 				queryArgs = args[1:] // -> omit the "sql" because it parsed as argument.
 			}
-			queries, err := readAndQuoteQueries(queryArgs)
+			queries, err := readLiveQueries(queryArgs)
 			if err != nil {
 				return err
 			}
@@ -96,11 +100,20 @@ func newLiveLSQLCommand() *cobra.Command {
 
 			currentConfig := configManager.getCurrent()
 
+			tlsConfig, err := currentConfig.TLSConfig()
+			if err != nil {
+				return err
+			}
+
 			conn, err := lenses.OpenLiveConnection(lenses.LiveConfiguration{
-				User:     currentConfig.User,
-				Password: currentConfig.Password,
-				Host:     currentConfig.Host,
-				Debug:    currentConfig.Debug,
+				User:            currentConfig.User,
+				Password:        currentConfig.Password,
+				Host:            currentConfig.GetHosts()[0], // the websocket connection has no failover, use the first one.
+				Debug:           currentConfig.Debug,
+				TLSClientConfig: tlsConfig,
+				// survive the lenses restarts, the queries are subscribed again on reconnection.
+				Reconnect:        true,
+				HeartbeatTimeout: liveHeartbeatTimeout,
 			})
 
 			if err != nil {
@@ -110,12 +123,22 @@ func newLiveLSQLCommand() *cobra.Command {
 			go func() {
 				// print each error on screen, do not exit because
 				// a query may be errored but another, most important may running for a long time.
-				select {
-				case err := <-conn.Err():
+				for err := range conn.Err() {
 					fmt.Fprintf(cmd.OutOrStderr(), "%s\n", err)
 				}
 			}()
 
+			conn.OnLifecycle(func(event lenses.LiveLifecycleEvent) {
+				switch event.Type {
+				case lenses.LiveReconnecting:
+					fmt.Fprintf(cmd.OutOrStderr(), "Reconnecting, attempt %d\n", event.Attempt)
+				case lenses.LiveResubscribed:
+					fmt.Fprintf(cmd.OutOrStderr(), "Reconnected, %d subscriptions restored\n", event.Subscriptions)
+				case lenses.LiveGaveUp:
+					fmt.Fprintf(cmd.OutOrStderr(), "Unable to reconnect after %d attempts\n", event.Attempt)
+				}
+			})
+
 			// we exit on error, the only one place that we directly exit from here.
 			errorReporter := func(_ lenses.LivePublisher, resp lenses.LiveResponse) error {
 				// parse it, otherwise it shows it very ungly.
@@ -143,9 +166,8 @@ func newLiveLSQLCommand() *cobra.Command {
 				}
 
 				for i := range data {
-					b := []byte(data[i].Value)
 					var in interface{}
-					if err := json.Unmarshal(b, &in); err != nil {
+					if err := data[i].DecodeValue(&in); err != nil {
 						return err // fail on first error.
 					}
 
@@ -163,36 +185,33 @@ func newLiveLSQLCommand() *cobra.Command {
 				return nil
 			})
 
-			// on login(`conn.Listen`) success send the lsql queries.
-			conn.OnSuccess(func(pub lenses.LivePublisher, resp lenses.LiveResponse) error {
-				if resp.CorrelationID == 2 {
-					// if it comes as a result of the subscribe action,
-					// print the topic(s) name.
-
-					var name string
-					if err := json.Unmarshal(resp.Content, &name); err != nil {
-						return err
-					}
+			// send the lsql queries, the subscribe waits for the login.
+			// we can use it to return results from many lsqueries,
+			// it works, it returns results but it's not recommended, cpu goes really high!
+			// lenses-cli live sql
+			// "SELECT * FROM cc_payments WHERE _vtype='AVRO' AND _ktype='STRING' AND _sample=2 AND _sampleWindow=200"
+			// "SELECT * FROM reddit_posts WHERE _vtype='AVRO' AND _ktype='AVRO' AND _sample=2 AND _sampleWindow=200"
+			resp, err := conn.Subscribe(context.Background(), queries...).Wait()
+			if err != nil {
+				conn.Close()
+				return err
+			}
 
-					title := "Topic"
-					if len(queries) > 1 {
-						title += "s"
-					}
+			// print the topic(s) name.
+			var name string
+			if err = json.Unmarshal(resp.Content, &name); err != nil {
+				conn.Close()
+				return err
+			}
 
-					// ignore the topic names from the standard output
-					// use the stderr for it:
-					fmt.Fprintf(cmd.OutOrStderr(), "%s: %s\n", title, name)
-					return nil
-				}
+			title := "Topic"
+			if len(queries) > 1 {
+				title += "s"
+			}
 
-				// we can use it to return results from many lsqueries,
-				// it works, it returns results but it's not recommended, cpu goes really high!
-				// lenses-cli live sql
-				// "SELECT * FROM cc_payments WHERE _vtype='AVRO' AND _ktype='STRING' AND _sample=2 AND _sampleWindow=200"
-				// "SELECT * FROM reddit_posts WHERE _vtype='AVRO' AND _ktype='AVRO' AND _sample=2 AND _sampleWindow=200"
-				content := fmt.Sprintf(`{"sqls": [%s]}`, strings.Join(queries, ","))
-				return pub.Publish(lenses.SubscribeRequest, 2, content)
-			})
+			// ignore the topic names from the standard output
+			// use the stderr for it:
+			fmt.Fprintf(cmd.OutOrStderr(), "%s: %s\n", title, name)
 
 			ch := make(chan os.Signal, 1)
 			signal.Notify(ch,
@@ -1,6 +1,8 @@
 package lenses
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -22,6 +24,12 @@ import (
 type Configuration struct {
 	// Host is the network address that your lenses backend is listening for incoming requests.
 	Host string `json:"host" yaml:"Host" toml:"Host" survey:"host"`
+	// Hosts is an optional, ordered, list of more network addresses of the same lenses backend.
+	// The client fails over to the next healthy one, after the `Host`, when it's unreachable,
+	// the idempotent requests fail over on connection resets, timeouts, 502, 503 and 504 responses too.
+	//
+	// See `GetHosts` and `Client#ActiveHost` too.
+	Hosts []string `json:"hosts,omitempty" yaml:"Hosts" toml:"Hosts" survey:"-"`
 
 	// Auth fields
 	// we need those in order to generate the access token.
@@ -34,7 +42,9 @@ type Configuration struct {
 	// Token is the "X-Kafka-Lenses-Token" request header's value.
 	// Overrides the `User` and `Password` settings.
 	//
-	// If `Token` is expired then all the calls will result on 403 forbidden error HTTP code
+	// If `Token` is expired and `User` and `Password` are filled then the client
+	// logs in again and replays the failed request once, see `UsingTokenRefreshHandler` too.
+	// Otherwise all the calls will result on 401 unauthorized error HTTP code
 	// and a manual renewal will be demanded.
 	//
 	// For general-purpose usecase the recommendation is to let this field empty and
@@ -49,6 +59,25 @@ type Configuration struct {
 	// Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h".
 	// Example: "5s" for 5 seconds, "5m" for 5 minutes and so on.
 	Timeout string `json:"timeout,omitempty" yaml:"Timeout" toml:"Timeout" survey:"timeout"`
+
+	// TLS fields
+	// used when the `Host` is served over https, see `TLSConfig`.
+
+	// CAFile is the path of a PEM encoded certificate authority bundle,
+	// the certificates are used to verify the server in addition to the system ones.
+	CAFile string `json:"caFile,omitempty" yaml:"CAFile" toml:"CAFile" survey:"caFile"`
+	// CertFile and KeyFile are the paths of the PEM encoded client certificate and its private key,
+	// they are used for the mutual TLS authentication and they should be filled together.
+	CertFile string `json:"certFile,omitempty" yaml:"CertFile" toml:"CertFile" survey:"certFile"`
+	KeyFile  string `json:"keyFile,omitempty" yaml:"KeyFile" toml:"KeyFile" survey:"keyFile"`
+	// ServerName overrides the host name that is used to verify the server's certificate.
+	ServerName string `json:"serverName,omitempty" yaml:"ServerName" toml:"ServerName" survey:"serverName"`
+	// Insecure disables the verification of the server's certificate chain and host name,
+	// the connection is open to man-in-the-middle attacks, use it only for testing.
+	//
+	// Defaults to false.
+	Insecure bool `json:"insecure,omitempty" yaml:"Insecure" toml:"Insecure" survey:"insecure"`
+
 	// Debug activates the debug mode, it logs every request, the configuration (except the `Password`)
 	// and its raw response before decoded but after gzip reading.
 	//
@@ -63,8 +92,8 @@ type Configuration struct {
 	 which performs x3 times faster than the alternatives.
 	 Zero performance cost if a logger is not responsible to actually print/write the message,
 	 on `Debugf` not even the `fmt.Spritnf` is called in that case.
-	 The user of the lenses client can change its output source(s) and even inject the log messaging
-	 by using the `golog.Default` instance.
+	 Each client has its own golog instance, the process-wide `golog.Default` is not modified,
+	 the user of the lenses client can inject a custom logger by using the `UsingLogger` option.
 	*/
 } /* Why a whole Configuration struct while we could just pass those 3 params?
 Because we may need more fields in the future,
@@ -74,57 +103,90 @@ we can load them via files, i.e in `OpenConnection`, we pass out options that ar
 functions, they can't load via files.
 */
 
-// FormatHost will try to make sure that the schema:host:port pattern is followed on the `Host` field.
+// FormatHost will try to make sure that the schema:host:port pattern is followed on the `Host` field
+// and on each one of the `Hosts`.
 func (c *Configuration) FormatHost() {
-	if len(c.Host) == 0 {
-		return
+	c.Host = formatHost(c.Host)
+	for i, host := range c.Hosts {
+		c.Hosts[i] = formatHost(host)
+	}
+}
+
+func formatHost(host string) string {
+	if len(host) == 0 {
+		return host
 	}
 
 	// remove last slash, so the API can append the path with ease.
-	if c.Host[len(c.Host)-1] == '/' {
-		c.Host = c.Host[0 : len(c.Host)-1]
+	if host[len(host)-1] == '/' {
+		host = host[0 : len(host)-1]
 	}
 
-	portIdx := strings.LastIndexByte(c.Host, ':')
+	portIdx := strings.LastIndexByte(host, ':')
 
-	schemaIdx := strings.Index(c.Host, "://")
+	schemaIdx := strings.Index(host, "://")
 	hasSchema := schemaIdx >= 0
 	hasPort := portIdx > schemaIdx+1
 
 	var port = "80"
 	if hasPort {
-		port = c.Host[portIdx+1:]
+		port = host[portIdx+1:]
 	}
 
 	// find the schema based on the port.
 	if !hasSchema {
 		if port == "443" {
-			c.Host = "https://" + c.Host
+			host = "https://" + host
 		} else {
-			c.Host = "http://" + c.Host
+			host = "http://" + host
 		}
 	} else if !hasPort {
 		// has schema but not port.
-		if strings.HasPrefix(c.Host, "https://") {
+		if strings.HasPrefix(host, "https://") {
 			port = "443"
 		}
 	}
 
 	// finally, append the port part if it wasn't there.
 	if !hasPort {
-		c.Host += ":" + port
+		host += ":" + port
+	}
+
+	return host
+}
+
+// GetHosts returns the ordered list of the hosts, the `Host` comes first, if any, and then the `Hosts`,
+// duplicated and empty entries are removed.
+func (c *Configuration) GetHosts() []string {
+	var hosts []string
+
+	seen := make(map[string]struct{})
+	for _, host := range append([]string{c.Host}, c.Hosts...) {
+		host = formatHost(host)
+		if host == "" {
+			continue
+		}
+
+		if _, ok := seen[host]; ok {
+			continue
+		}
+
+		seen[host] = struct{}{}
+		hosts = append(hosts, host)
 	}
+
+	return hosts
 }
 
 // IsValid returns true if the configuration contains the necessary fields, otherwise false.
 func (c *Configuration) IsValid() bool {
-	if len(c.Host) == 0 {
+	if len(c.Host) == 0 && len(c.Hosts) == 0 {
 		return false
 	}
 
 	c.FormatHost()
 
-	return c.Host != "" && (c.Token != "" || (c.User != "" && c.Password != ""))
+	return len(c.GetHosts()) > 0 && (c.Token != "" || (c.User != "" && c.Password != ""))
 }
 
 // Fill iterates over the "other" Configuration's fields
@@ -141,6 +203,10 @@ func (c *Configuration) Fill(other Configuration) bool {
 		c.Host = v
 	}
 
+	if v := other.Hosts; len(v) > 0 {
+		c.Hosts = v
+	}
+
 	if v := other.Token; v != "" && v != c.Token {
 		c.Token = v
 	}
@@ -157,6 +223,26 @@ func (c *Configuration) Fill(other Configuration) bool {
 		c.Timeout = v
 	}
 
+	if v := other.CAFile; v != "" && v != c.CAFile {
+		c.CAFile = v
+	}
+
+	if v := other.CertFile; v != "" && v != c.CertFile {
+		c.CertFile = v
+	}
+
+	if v := other.KeyFile; v != "" && v != c.KeyFile {
+		c.KeyFile = v
+	}
+
+	if v := other.ServerName; v != "" && v != c.ServerName {
+		c.ServerName = v
+	}
+
+	if c.Insecure != other.Insecure {
+		c.Insecure = other.Insecure
+	}
+
 	if c.Debug != other.Debug {
 		c.Debug = other.Debug
 	}
@@ -164,6 +250,57 @@ func (c *Configuration) Fill(other Configuration) bool {
 	return c.IsValid()
 }
 
+// TLSConfig returns the TLS configuration based on the `CAFile`, `CertFile`, `KeyFile`,
+// `ServerName` and `Insecure` fields, it's used by the `OpenConnection` for the REST API calls and
+// the streams and it can be passed to the `LiveConfiguration#TLSClientConfig` for the websocket connection.
+//
+// It returns a nil configuration and a nil error if none of these fields are filled,
+// so the default one is used instead.
+func (c *Configuration) TLSConfig() (*tls.Config, error) {
+	if c.CAFile == "" && c.CertFile == "" && c.KeyFile == "" && c.ServerName == "" && !c.Insecure {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.Insecure,
+	}
+
+	if c.CAFile != "" {
+		b, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls: unable to read the certificate authority file: %v", err)
+		}
+
+		// add to the system's ones, if not available then start with an empty pool.
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("tls: no valid PEM encoded certificates found in '%s'", c.CAFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, fmt.Errorf("tls: both client certificate and key files are required")
+		}
+
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls: unable to load the client certificate: %v", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
 // UnmarshalFunc is the most standard way to declare a Decoder/Unmarshaler to read the configurations and more.
 // See `ReadConfiguration` and `ReadConfigurationFromFile` for more.
 type UnmarshalFunc func(in []byte, outPtr interface{}) error
@@ -111,3 +111,20 @@ func TestReadConfigurationFromTOML(t *testing.T) {
 		expectedConfiguration.Debug)
 	testConfigurationFile(t, "configuration.tml", contents, lenses.ReadConfigurationFromTOML)
 }
+
+func TestConfigurationFillInsecure(t *testing.T) {
+	c := lenses.Configuration{Host: "https://localhost:9991", Token: "token", Insecure: true}
+
+	if !c.Fill(lenses.Configuration{Insecure: false}) {
+		t.Fatal("expected the filled configuration to be valid")
+	}
+
+	if c.Insecure {
+		t.Fatal("expected the insecure to be switched back to false")
+	}
+
+	c.Fill(lenses.Configuration{Insecure: true})
+	if !c.Insecure {
+		t.Fatal("expected the insecure to be true")
+	}
+}
@@ -0,0 +1,114 @@
+package lenses
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// APIError is being fired from all API calls when the server responds with a non-successful status code.
+//
+// It can be checked against the `ErrResourceNotFound` (404) and `ErrCredentialsMissing` (401)
+// using the `errors.Is` and the rest of the information can be retrieved using the `errors.As`.
+//
+// Usage:
+// var apiErr *lenses.APIError
+// if errors.As(err, &apiErr) { println(apiErr.StatusCode) }
+type APIError struct {
+	// Method is the HTTP method of the failed request, i.e "GET".
+	Method string `json:"method"`
+	// URL is the full, unescaped, request URL.
+	URL string `json:"url"`
+	// StatusCode is the HTTP status code that the server responded with, i.e 404.
+	StatusCode int `json:"statusCode"`
+	// Body is the raw response body.
+	Body []byte `json:"-"`
+	// Message is the error message parsed from the response body, if any.
+	Message string `json:"message"`
+}
+
+// newAPIError creates a new APIError based on the failed response, it reads and closes the response body.
+func (c *Client) newAPIError(method string, resp *http.Response) *APIError {
+	unescapedURI, _ := url.QueryUnescape(resp.Request.URL.String())
+
+	apiErr := &APIError{
+		Method:     method,
+		URL:        unescapedURI,
+		StatusCode: resp.StatusCode,
+	}
+
+	b, err := c.readResponseBody(resp)
+	if err != nil {
+		apiErr.Message = "unable to read body: " + err.Error()
+		return apiErr
+	}
+
+	apiErr.Body = b
+	apiErr.Message = parseErrorMessage(b)
+	return apiErr
+}
+
+// parseErrorMessage returns the error message from the body of a failed response,
+// Lenses, the Kafka Connect and the Schema Registry respond with a json object
+// that contains a "message" or an "error" field, otherwise the body is plain text.
+func parseErrorMessage(body []byte) string {
+	var errData = struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}{}
+
+	if err := json.Unmarshal(body, &errData); err == nil {
+		if errData.Message != "" {
+			return errData.Message
+		}
+
+		if errData.Error != "" {
+			return errData.Error
+		}
+	}
+
+	msg := strings.TrimSpace(string(body))
+	if strings.HasPrefix(msg, "<") { // html error pages from proxies are not useful.
+		return ""
+	}
+
+	return msg
+}
+
+func (err *APIError) Error() string {
+	msg := fmt.Sprintf("client: (%s: %s) failed with status code %d", err.Method, err.URL, err.StatusCode)
+	if err.Message != "" {
+		msg += ": " + err.Message
+	}
+
+	return msg
+}
+
+// Is reports whether the "target" error is the `ErrResourceNotFound` and the status code is 404
+// or the `ErrCredentialsMissing` and the status code is 401.
+// It's used by the `errors.Is`.
+func (err *APIError) Is(target error) bool {
+	switch target {
+	case ErrResourceNotFound:
+		return err.StatusCode == http.StatusNotFound
+	case ErrCredentialsMissing:
+		return err.StatusCode == http.StatusUnauthorized
+	default:
+		return false
+	}
+}
+
+// Unwrap returns the `ErrResourceNotFound` on 404 and the `ErrCredentialsMissing` on 401, otherwise nil.
+// It's used by the `errors.Unwrap`.
+func (err *APIError) Unwrap() error {
+	switch err.StatusCode {
+	case http.StatusNotFound:
+		return ErrResourceNotFound
+	case http.StatusUnauthorized:
+		return ErrCredentialsMissing
+	default:
+		return nil
+	}
+}
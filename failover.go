@@ -0,0 +1,115 @@
+package lenses
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+)
+
+// HealthProbe describes the form of the function that checks if a host is healthy,
+// it's used on failover to select the next host, see `Configuration#Hosts`.
+// The "host" contains the schema and the port, i.e "https://lenses.example.com:443".
+type HealthProbe func(ctx context.Context, host string) error
+
+// UsingHealthProbe sets a custom health probe, which is used to select the next healthy host on failover.
+//
+// The default health probe sends a GET request to the host's root path
+// and it reports the host as healthy if the server responded with a status code lower than 500.
+func UsingHealthProbe(probe HealthProbe) ConnectionOption {
+	return func(c *Client) {
+		c.healthProbe = probe
+	}
+}
+
+const healthProbeTimeout = 5 * time.Second
+
+func (c *Client) probeHost(ctx context.Context, host string) error {
+	ctx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodGet, host+"/", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("health probe failed with status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// ActiveHost returns the host that the client sends the requests to.
+// It's the first one of the configuration's hosts and it changes only on failover,
+// the selection is sticky, the client doesn't switch back to a previous host while the active one is healthy.
+func (c *Client) ActiveHost() string {
+	return c.hosts[atomic.LoadInt32(&c.activeHostIdx)]
+}
+
+// shouldFailover reports whether a request to the active host failed because of the host itself.
+// The idempotent requests fail over on the `IsRetryable` failures, i.e connection resets, timeouts or 502, 503 and 504,
+// the rest of them only when the connection failed, the request never reached the server and it's safe to send it to another host.
+func (c *Client) shouldFailover(ctx context.Context, method string, resp *http.Response, err error) bool {
+	if len(c.hosts) < 2 || ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil && isDialError(err) {
+		return true
+	}
+
+	return IsRetryable(method, resp, err)
+}
+
+// isDialError reports whether the request failed to connect to the host, i.e connection refused.
+func isDialError(err error) bool {
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+
+	opErr, ok := err.(*net.OpError)
+	return ok && opErr.Op == "dial"
+}
+
+// failover selects the next healthy host, after the "failedIdx" one, based on the configuration's order.
+// It returns false if there is no healthy host.
+func (c *Client) failover(ctx context.Context, failedIdx int32) bool {
+	c.failoverMu.Lock()
+	defer c.failoverMu.Unlock()
+
+	if atomic.LoadInt32(&c.activeHostIdx) != failedIdx {
+		return true // another request already selected a new one.
+	}
+
+	probe := c.healthProbe
+	if probe == nil {
+		probe = c.probeHost
+	}
+
+	n := int32(len(c.hosts))
+	for i := int32(1); i < n; i++ {
+		idx := (failedIdx + i) % n
+		host := c.hosts[idx]
+
+		if err := probe(ctx, host); err != nil {
+			c.logger.Debug("Client#failover: host is unhealthy", F("host", host), F("error", err))
+			continue
+		}
+
+		atomic.StoreInt32(&c.activeHostIdx, idx)
+		c.logger.Debug("Client#failover: active host changed", F("from", c.hosts[failedIdx]), F("to", host))
+		return true
+	}
+
+	return false
+}
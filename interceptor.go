@@ -0,0 +1,64 @@
+package lenses
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// RoundTripFunc describes the form of the function that sends a request and returns its response,
+// it's the "next" step that an `Interceptor` should call to continue the chain.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Interceptor describes the form of a middleware that wraps the sending of each request to the Lenses server.
+// The "op" is the logical operation name, it's the name of the `Client`'s method, i.e "CreateTopic"
+// and "Login" for the authentication request.
+//
+// An interceptor can modify the request, i.e add headers, and it should call the "next" to send it,
+// then it can inspect the response and the error, i.e for auditing and metrics.
+// Note that the response's body should not be consumed, it's read by the client afterwards.
+//
+// Example:
+//
+//	func logInterceptor(op string, req *http.Request, next lenses.RoundTripFunc) (*http.Response, error) {
+//	    start := time.Now()
+//	    resp, err := next(req)
+//	    log.Printf("%s: %s %s took %s", op, req.Method, req.URL, time.Since(start))
+//	    return resp, err
+//	}
+//
+// Look `UsingInterceptors` for more.
+type Interceptor func(op string, req *http.Request, next RoundTripFunc) (*http.Response, error)
+
+// UsingInterceptors registers one or more interceptors, which are fired for each request that is sent
+// to the Lenses server, including the retries and the re-authentication ones.
+// The interceptors are executed by their registration order, the first one wraps the rest.
+func UsingInterceptors(interceptors ...Interceptor) ConnectionOption {
+	return func(c *Client) {
+		for _, interceptor := range interceptors {
+			if interceptor == nil {
+				continue
+			}
+
+			c.interceptors = append(c.interceptors, interceptor)
+		}
+	}
+}
+
+// roundTrip sends the request through the registered interceptors, if any.
+func (c *Client) roundTrip(op string, req *http.Request) (*http.Response, error) {
+	next := c.client.Do
+
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := c.interceptors[i], next
+		next = func(req *http.Request) (*http.Response, error) {
+			return interceptor(op, req, inner)
+		}
+	}
+
+	resp, err := next(req)
+	if resp == nil && err == nil { // protect the client from a bad interceptor.
+		return nil, fmt.Errorf("client: %s: interceptor returned no response and no error", op)
+	}
+
+	return resp, err
+}
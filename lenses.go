@@ -1,13 +1,13 @@
 package lenses
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
+	"strings"
 	"time"
-
-	"github.com/kataras/golog"
 )
 
 // ConnectionOption describes an optional runtime configurator that can be passed on `OpenConnection`.
@@ -26,7 +26,7 @@ func getTimeout(httpClient *http.Client, timeoutStr string) time.Duration {
 	return httpClient.Timeout
 }
 
-func getTransportLayer(httpClient *http.Client, timeout time.Duration) (t http.RoundTripper) {
+func getTransportLayer(httpClient *http.Client, timeout time.Duration, tlsConfig *tls.Config) (t http.RoundTripper) {
 	if t := httpClient.Transport; t != nil {
 		return t
 	}
@@ -34,7 +34,8 @@ func getTransportLayer(httpClient *http.Client, timeout time.Duration) (t http.R
 	httpTransport := &http.Transport{
 		// Disable HTTP/2.
 		TLSNextProto: make(map[string]func(authority string, c *tls.Conn) http.RoundTripper),
-		// TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		// nil means the default configuration, see `Configuration#TLSConfig`.
+		TLSClientConfig: tlsConfig,
 	}
 
 	if timeout > 0 {
@@ -47,6 +48,7 @@ func getTransportLayer(httpClient *http.Client, timeout time.Duration) (t http.R
 }
 
 // UsingClient modifies the underline HTTP Client that lenses is using for contact with the backend server.
+// If the "httpClient" has a custom `Transport` then the configuration's TLS fields are not applied to it.
 func UsingClient(httpClient *http.Client) ConnectionOption {
 	return func(c *Client) {
 		if httpClient == nil {
@@ -56,7 +58,7 @@ func UsingClient(httpClient *http.Client) ConnectionOption {
 		// config's timeout has priority if the httpClient passed has smaller or not-seted timeout.
 		timeout := getTimeout(httpClient, c.config.Timeout)
 
-		transport := getTransportLayer(httpClient, timeout)
+		transport := getTransportLayer(httpClient, timeout, c.tlsConfig)
 		httpClient.Transport = transport
 
 		c.client = httpClient
@@ -76,6 +78,23 @@ func UsingToken(tok string) ConnectionOption {
 
 }
 
+// TokenRefreshHandler describes the form of the function that is fired
+// when the client logged in again because its access token was expired, it accepts the new token.
+// See `UsingTokenRefreshHandler`.
+type TokenRefreshHandler func(token string)
+
+// UsingTokenRefreshHandler registers a function which is fired when the token is renewed.
+//
+// The client logs in again and replays the failed request once when
+// a request failed because of an expired token, this is possible only when the
+// configuration contains the `User` and `Password`.
+// It may be useful for callers that need to persist the latest token.
+func UsingTokenRefreshHandler(handler TokenRefreshHandler) ConnectionOption {
+	return func(c *Client) {
+		c.tokenRefreshHandler = handler
+	}
+}
+
 // OpenConnection creates & returns a new Landoop's Lenses API bridge interface
 // based on the passed Configuration and the (optional) options.
 // OpenConnection authenticates the user and returns a valid ready-to-use `*lenses.Client`.
@@ -89,15 +108,45 @@ func UsingToken(tok string) ConnectionOption {
 //
 // Read more by navigating to the `Client` type documentation.
 func OpenConnection(config Configuration, options ...ConnectionOption) (*Client, error) {
-	c := &Client{config: config} // we need the timeout.
+	return OpenConnectionContext(context.Background(), config, options...)
+}
+
+// OpenConnectionContext same as `OpenConnection` but it accepts a context.Context,
+// which can cancel or set a deadline on the login request.
+//
+// The context is used only for the authentication,
+// each API call accepts its own context through its `XXXContext` method, i.e `GetTopicsContext`.
+func OpenConnectionContext(ctx context.Context, config Configuration, options ...ConnectionOption) (*Client, error) {
+	c := &Client{
+		config:             config, // we need the timeout.
+		lsqlWaitMaxRecords: defaultLSQLWaitMaxRecords,
+		lsqlWaitMaxBytes:   defaultLSQLWaitMaxBytes,
+	}
+
+	// we need the TLS configuration before the options, see `UsingClient`.
+	tlsConfig, err := config.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	c.tlsConfig = tlsConfig
+
 	for _, opt := range options {
 		opt(c)
 	}
 
+	// if logger is not set-ed by any option, create a new one,
+	// the process-wide `golog.Default` is never modified.
+	if c.logger == nil {
+		c.logger = newDefaultLogger(config.Debug)
+	}
+
 	if !config.IsValid() {
 		return nil, fmt.Errorf("invalid configuration: Token or (User or Password) missing")
 	}
 
+	// the first one is the active host, see `failover`.
+	c.hosts = config.GetHosts()
+
 	// if client is not set-ed by any option, set it to a new one,
 	// a good idea could be to use the `http.DefaultClient`
 	// but this has some limitations so we start with a new, to be clear and simple.
@@ -107,22 +156,38 @@ func OpenConnection(config Configuration, options ...ConnectionOption) (*Client,
 	}
 
 	if c.config.Token != "" {
-		golog.Debugf("Connecting using just the token: %s", config.Token)
+		c.logger.Debug("Connecting using just the token", F("token", c.unredacted.redact(config.Token)))
 		// User will be empty but it does its job.
 		return c, nil
 	}
 
-	// retrieve token.
+	if err := c.login(ctx); err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("Connected",
+		F("host", c.ActiveHost()),
+		F("token", c.unredacted.redact(c.GetAccessToken())),
+		F("user", fmt.Sprintf("%#+v", c.User())))
+
+	return c, nil
+}
+
+const loginPath = "api/login"
+
+// login retrieves a new token based on the configuration's `User` and `Password`
+// and sets the token and the user model received from the server.
+func (c *Client) login(ctx context.Context) error {
 	userAuthJSON := fmt.Sprintf(`{"user":"%s", "password": "%s"}`, c.config.User, c.config.Password)
 
-	resp, err := c.do(http.MethodPost, "api/login", contentTypeJSON, []byte(userAuthJSON))
+	resp, err := c.do(ctx, "Login", http.MethodPost, loginPath, contentTypeJSON, []byte(userAuthJSON))
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusUnauthorized {
-		return nil, fmt.Errorf("http: StatusUnauthorized 401")
+		return fmt.Errorf("http: StatusUnauthorized 401")
 	}
 
 	// set the token we received.
@@ -134,26 +199,53 @@ func OpenConnection(config Configuration, options ...ConnectionOption) (*Client,
 	}{}
 
 	if err := c.readJSON(resp, &loginData); err != nil {
-		return nil, err
+		return err
 	}
 
 	if !loginData.Success {
-		return nil, fmt.Errorf("http: login failed")
+		return fmt.Errorf("http: login failed")
 	}
 
 	if loginData.Token == "" { // this should never happen.
-		return nil, fmt.Errorf("http: token is undefinied")
-	}
-
-	if config.Debug {
-		golog.SetLevel("debug")
-		golog.Debugf("Connected on %s with token: %s.\nUser details: %#+v",
-			c.config.Host, loginData.Token, loginData.User)
+		return fmt.Errorf("http: token is undefinied")
 	}
 
 	// set the generated token and the user model retrieved from server.
+	c.authMu.Lock()
 	c.config.Token = loginData.Token
 	c.user = loginData.User
+	c.authMu.Unlock()
 
-	return c, nil
+	return nil
+}
+
+// canReauthenticate reports whether a request to the "path" that failed with 401
+// can be replayed after a new login, the credentials should be there
+// and the request should not be a login or logout one.
+func (c *Client) canReauthenticate(path string) bool {
+	return c.config.User != "" && c.config.Password != "" &&
+		!strings.HasPrefix(path, loginPath) && !strings.HasPrefix(path, logoutPath)
+}
+
+// reauthenticate logs in again if the "staleToken" is still the current one,
+// if not then another request has already renewed the token and there is nothing to do.
+func (c *Client) reauthenticate(ctx context.Context, staleToken string) error {
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+
+	if c.GetAccessToken() != staleToken {
+		return nil
+	}
+
+	c.logger.Debug("Client#reauthenticate: token expired, login again", F("user", c.config.User))
+
+	if err := c.login(ctx); err != nil {
+		return err
+	}
+
+	if c.tokenRefreshHandler != nil {
+		c.tokenRefreshHandler(c.GetAccessToken())
+	}
+
+	return nil
 }
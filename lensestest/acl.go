@@ -0,0 +1,191 @@
+package lensestest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/landoop/lenses-go"
+)
+
+// aclAPI serves the "api/acl", the ACLs are unique, the PUT of an existing one is a no-op.
+func (s *Server) aclAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		s.mu.Lock()
+		acls := append(make([]lenses.ACL, 0, len(s.acls)), s.acls...)
+		s.mu.Unlock()
+
+		writeJSON(w, http.StatusOK, acls)
+		return
+	}
+
+	var acl lenses.ACL
+	if !readJSON(w, r, &acl) {
+		return
+	}
+
+	if err := acl.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := -1
+	for i, existing := range s.acls {
+		if existing == acl {
+			idx = i
+			break
+		}
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		if idx == -1 {
+			s.acls = append(s.acls, acl)
+		}
+	case http.MethodDelete:
+		if idx == -1 {
+			writeError(w, http.StatusNotFound, "ACL does not exist")
+			return
+		}
+
+		s.acls = append(s.acls[:idx], s.acls[idx+1:]...)
+	default:
+		methodNotAllowed(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// quotaDefaultName is the entity name (or the child) of the default quotas, as Kafka reports them.
+const quotaDefaultName = "<default>"
+
+// quotaEntity returns the entity's type, name and child based on the path after the "api/quotas".
+func quotaEntity(rest []string) (entityType lenses.QuotaEntityType, name, child string, ok bool) {
+	if len(rest) == 0 {
+		return
+	}
+
+	switch {
+	case rest[0] == "users" && len(rest) == 1:
+		return lenses.QuotaEntityUsersDefault, quotaDefaultName, "", true
+	case rest[0] == "users" && len(rest) == 2:
+		return lenses.QuotaEntityUser, rest[1], "", true
+	case rest[0] == "users" && len(rest) == 3 && rest[2] == "clients":
+		return lenses.QuotaEntityUserClient, rest[1], quotaDefaultName, true
+	case rest[0] == "users" && len(rest) == 4 && rest[2] == "clients":
+		return lenses.QuotaEntityUserClient, rest[1], rest[3], true
+	case rest[0] == "clients" && len(rest) == 1:
+		return lenses.QuotaEntityClientsDefault, quotaDefaultName, "", true
+	case rest[0] == "clients" && len(rest) == 2:
+		return lenses.QuotaEntityClient, rest[1], "", true
+	}
+
+	return
+}
+
+// quotasAPI serves the "api/quotas" and the per entity "api/quotas/users[/...]" and "api/quotas/clients[/...]".
+// The PUT sets the non-empty properties and the DELETE removes the properties given in its body,
+// the quota is removed when all of its properties are removed.
+func (s *Server) quotasAPI(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) == 0 {
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+
+		s.mu.Lock()
+		quotas := append(make([]lenses.Quota, 0, len(s.quotas)), s.quotas...)
+		s.mu.Unlock()
+
+		writeJSON(w, http.StatusOK, quotas)
+		return
+	}
+
+	entityType, name, child, ok := quotaEntity(rest)
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	var (
+		config     lenses.QuotaConfig
+		properties []string
+	)
+
+	switch r.Method {
+	case http.MethodPut:
+		if !readJSON(w, r, &config) {
+			return
+		}
+	case http.MethodDelete:
+		if !readJSON(w, r, &properties) {
+			return
+		}
+	default:
+		methodNotAllowed(w)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := -1
+	for i, q := range s.quotas {
+		if q.EnityType == entityType && q.EntityName == name && q.Child == child {
+			idx = i
+			break
+		}
+	}
+
+	if r.Method == http.MethodPut {
+		if idx == -1 {
+			s.quotas = append(s.quotas, lenses.Quota{
+				EnityType:  entityType,
+				EntityName: name,
+				Child:      child,
+				URL:        "/" + strings.Trim(r.URL.Path, "/"),
+			})
+			idx = len(s.quotas) - 1
+		}
+
+		properties := &s.quotas[idx].Properties
+		if config.ProducerByteRate != "" {
+			properties.ProducerByteRate = config.ProducerByteRate
+		}
+		if config.ConsumerByteRate != "" {
+			properties.ConsumerByteRate = config.ConsumerByteRate
+		}
+		if config.RequestPercentage != "" {
+			properties.RequestPercentage = config.RequestPercentage
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if idx == -1 {
+		writeError(w, http.StatusNotFound, "quota does not exist")
+		return
+	}
+
+	q := &s.quotas[idx]
+	for _, property := range properties {
+		switch property {
+		case "producer_byte_rate":
+			q.Properties.ProducerByteRate = ""
+		case "consumer_byte_rate":
+			q.Properties.ConsumerByteRate = ""
+		case "request_percentage":
+			q.Properties.RequestPercentage = ""
+		}
+	}
+
+	if q.Properties == (lenses.QuotaConfig{}) {
+		s.quotas = append(s.quotas[:idx], s.quotas[idx+1:]...)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
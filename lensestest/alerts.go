@@ -0,0 +1,213 @@
+package lensestest
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/landoop/lenses-go"
+)
+
+const (
+	alertCategoryInfrastructure = "Infrastructure"
+	alertCategoryConsumers      = "Consumers"
+)
+
+func defaultAlertSettings() []lenses.AlertSetting {
+	return []lenses.AlertSetting{
+		{
+			ID:          1000,
+			Description: "Kafka Broker is down",
+			Category:    alertCategoryInfrastructure,
+			Enabled:     true,
+			IsAvailable: true,
+		},
+		{
+			ID:                2000,
+			Description:       "Consumer Lag exceeded",
+			Category:          alertCategoryConsumers,
+			ConditionTemplate: "lag >= $LAG on group $GROUP and topic $TOPIC",
+			ConditionRegex:    `lag >= ([0-9]+) on group (.+) and topic (.+)`,
+			Conditions:        make(map[string]string),
+			IsAvailable:       true,
+		},
+	}
+}
+
+// PushAlert registers an alert, as the `lenses.Client#RegisterAlert` does,
+// and sends it to the clients that listen to the alerts stream.
+func (s *Server) PushAlert(alert lenses.Alert) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.alerts = append(s.alerts, alert)
+	for listener := range s.alertListeners {
+		select {
+		case listener <- alert:
+		default: // slow listener, the fake server drops the alert instead of blocking.
+		}
+	}
+}
+
+func (s *Server) alertSetting(id int) *lenses.AlertSetting {
+	for i := range s.alertSettings {
+		if s.alertSettings[i].ID == id {
+			return &s.alertSettings[i]
+		}
+	}
+
+	return nil
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// alertsAPI serves the "api/alerts" and the "api/alerts/settings[/{id}[/condition[/{uuid}]]]".
+func (s *Server) alertsAPI(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			s.mu.Lock()
+			alerts := append(make([]lenses.Alert, 0, len(s.alerts)), s.alerts...)
+			s.mu.Unlock()
+
+			writeJSON(w, http.StatusOK, alerts)
+		case http.MethodPost:
+			var alert lenses.Alert
+			if !readJSON(w, r, &alert) {
+				return
+			}
+
+			s.PushAlert(alert)
+			w.WriteHeader(http.StatusOK)
+		default:
+			methodNotAllowed(w)
+		}
+
+		return
+	}
+
+	if rest[0] != "settings" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(rest) == 1 {
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+
+		var settings lenses.AlertSettings
+		for _, setting := range s.alertSettings {
+			if setting.Category == alertCategoryConsumers {
+				settings.Categories.Consumers = append(settings.Categories.Consumers, setting)
+			} else {
+				settings.Categories.Infrastructure = append(settings.Categories.Infrastructure, setting)
+			}
+		}
+
+		writeJSON(w, http.StatusOK, settings)
+		return
+	}
+
+	id, _ := strconv.Atoi(rest[1])
+	setting := s.alertSetting(id)
+	if setting == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Alert setting '%s' does not exist", rest[1]))
+		return
+	}
+
+	switch action := rest[2:]; {
+	case len(action) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, setting)
+	case len(action) == 0 && r.Method == http.MethodPut:
+		setting.Enabled = true
+		w.WriteHeader(http.StatusOK)
+	case len(action) == 1 && action[0] == "condition" && r.Method == http.MethodGet:
+		conditions := setting.Conditions
+		if conditions == nil {
+			conditions = make(map[string]string)
+		}
+
+		writeJSON(w, http.StatusOK, conditions)
+	case len(action) == 1 && action[0] == "condition" && r.Method == http.MethodPost:
+		b, err := ioutil.ReadAll(r.Body)
+		condition := strings.TrimSpace(string(b))
+		if err != nil || condition == "" {
+			writeError(w, http.StatusBadRequest, "condition is required")
+			return
+		}
+
+		if setting.ConditionRegex == "" {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Alert setting '%d' does not accept conditions", setting.ID))
+			return
+		}
+
+		if setting.Conditions == nil {
+			setting.Conditions = make(map[string]string)
+		}
+		setting.Conditions[newUUID()] = condition
+
+		w.WriteHeader(http.StatusOK)
+	case len(action) == 2 && action[0] == "condition" && r.Method == http.MethodDelete:
+		if _, ok := setting.Conditions[action[1]]; !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Condition '%s' does not exist", action[1]))
+			return
+		}
+
+		delete(setting.Conditions, action[1])
+		w.WriteHeader(http.StatusOK)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// alertsStream streams the alerts that are registered after the client connected, in the "data:<alert>" form.
+func (s *Server) alertsStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	listener := make(chan lenses.Alert, 64)
+	s.mu.Lock()
+	s.alertListeners[listener] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.alertListeners, listener)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.closed:
+			return
+		case alert := <-listener:
+			b, _ := json.Marshal(alert)
+			fmt.Fprintf(w, "data:%s\n\n", b)
+			flusher.Flush()
+		}
+	}
+}
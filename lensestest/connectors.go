@@ -0,0 +1,205 @@
+package lensestest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/landoop/lenses-go"
+)
+
+const connectWorkerID = "lensestest:8083"
+
+type connector struct {
+	config lenses.ConnectorConfig
+	state  lenses.ConnectorState
+}
+
+func (c *connector) tasksMax() int {
+	n, _ := strconv.Atoi(fmt.Sprintf("%v", c.config["tasks.max"]))
+	if n <= 0 {
+		return 1
+	}
+
+	return n
+}
+
+func (c *connector) info(name string) lenses.Connector {
+	tasks := make([]lenses.ConnectorTaskReadOnly, c.tasksMax())
+	for i := range tasks {
+		tasks[i] = lenses.ConnectorTaskReadOnly{Connector: name, Task: i}
+	}
+
+	return lenses.Connector{Name: name, Config: c.config, Tasks: tasks}
+}
+
+func (c *connector) status(name string) lenses.ConnectorStatus {
+	status := lenses.ConnectorStatus{
+		Name:      name,
+		Connector: lenses.ConnectorStatusConnectorField{State: string(c.state), WorkerID: connectWorkerID},
+		Tasks:     make([]lenses.ConnectorStatusTask, c.tasksMax()),
+	}
+
+	for i := range status.Tasks {
+		status.Tasks[i] = lenses.ConnectorStatusTask{ID: i, State: string(c.state), WorkerID: connectWorkerID}
+	}
+
+	return status
+}
+
+// connectorPlugins are the plugins that every connect cluster reports.
+var connectorPlugins = []lenses.ConnectorPlugin{
+	{Class: "org.apache.kafka.connect.file.FileStreamSinkConnector", Type: "sink", Version: "1.1.0"},
+	{Class: "org.apache.kafka.connect.file.FileStreamSourceConnector", Type: "source", Version: "1.1.0"},
+}
+
+// connectAPI serves the "api/proxy-connect/{cluster}/connectors[/...]" and "api/proxy-connect/{cluster}/connector-plugins",
+// the errors are in the form of the Kafka Connect ones.
+func (s *Server) connectAPI(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) < 2 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clusterName := rest[0]
+	connectors, ok := s.connectors[clusterName]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Connect cluster '%s' does not exist", clusterName))
+		return
+	}
+
+	switch rest[1] {
+	case "connector-plugins":
+		if len(rest) != 2 || r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, connectorPlugins)
+		return
+	case "connectors":
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	if len(rest) == 2 {
+		switch r.Method {
+		case http.MethodGet:
+			names := make([]string, 0, len(connectors))
+			for name := range connectors {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			writeJSON(w, http.StatusOK, names)
+		case http.MethodPost:
+			var payload lenses.CreateUpdateConnectorPayload
+			if !readJSON(w, r, &payload) {
+				return
+			}
+
+			if payload.Name == "" {
+				writeError(w, http.StatusBadRequest, "connector name is required")
+				return
+			}
+
+			if _, exists := connectors[payload.Name]; exists {
+				writeError(w, http.StatusConflict, fmt.Sprintf("Connector %s already exists", payload.Name))
+				return
+			}
+
+			c := &connector{config: payload.Config, state: lenses.RUNNING}
+			if c.config == nil {
+				c.config = make(lenses.ConnectorConfig)
+			}
+			c.config["name"] = payload.Name
+			connectors[payload.Name] = c
+
+			writeJSON(w, http.StatusCreated, c.info(payload.Name))
+		default:
+			methodNotAllowed(w)
+		}
+
+		return
+	}
+
+	name := rest[2]
+	c, ok := connectors[name]
+
+	// PUT /connectors/{name}/config creates the connector if it does not exist.
+	if len(rest) == 4 && rest[3] == "config" && r.Method == http.MethodPut {
+		var config lenses.ConnectorConfig
+		if !readJSON(w, r, &config) {
+			return
+		}
+
+		config["name"] = name
+		statusCode := http.StatusOK
+		if !ok {
+			c = &connector{state: lenses.RUNNING}
+			connectors[name] = c
+			statusCode = http.StatusCreated
+		}
+		c.config = config
+
+		writeJSON(w, statusCode, c.info(name))
+		return
+	}
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Connector %s not found", name))
+		return
+	}
+
+	switch action := rest[3:]; {
+	case len(action) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, c.info(name))
+	case len(action) == 0 && r.Method == http.MethodDelete:
+		delete(connectors, name)
+		w.WriteHeader(http.StatusNoContent)
+	case len(action) == 1 && action[0] == "config" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, c.config)
+	case len(action) == 1 && action[0] == "status" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, c.status(name))
+	case len(action) == 1 && action[0] == "pause" && r.Method == http.MethodPut:
+		c.state = lenses.PAUSED
+		w.WriteHeader(http.StatusAccepted)
+	case len(action) == 1 && action[0] == "resume" && r.Method == http.MethodPut:
+		c.state = lenses.RUNNING
+		w.WriteHeader(http.StatusAccepted)
+	case len(action) == 1 && action[0] == "restart" && r.Method == http.MethodPost:
+		w.WriteHeader(http.StatusNoContent)
+	case len(action) == 1 && action[0] == "tasks" && r.Method == http.MethodGet:
+		tasks := make([]map[string]interface{}, c.tasksMax())
+		for i := range tasks {
+			tasks[i] = map[string]interface{}{
+				"id":     lenses.ConnectorTaskReadOnly{Connector: name, Task: i},
+				"config": c.config,
+			}
+		}
+
+		writeJSON(w, http.StatusOK, tasks)
+	case len(action) == 3 && action[0] == "tasks":
+		taskID, err := strconv.Atoi(action[1])
+		if err != nil || taskID < 0 || taskID >= c.tasksMax() {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Task %s not found", action[1]))
+			return
+		}
+
+		switch {
+		case action[2] == "status" && r.Method == http.MethodGet:
+			writeJSON(w, http.StatusOK, c.status(name).Tasks[taskID])
+		case action[2] == "restart" && r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			methodNotAllowed(w)
+		}
+	default:
+		methodNotAllowed(w)
+	}
+}
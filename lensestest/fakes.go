@@ -0,0 +1,9 @@
+package lensestest
+
+import "errors"
+
+//go:generate go run ./internal/fakegen -src ../api.go -out fakes_generated.go
+
+// ErrNotImplemented is returned by the methods of the fakes, i.e `FakeTopicsAPI`,
+// whose function field is not set.
+var ErrNotImplemented = errors.New("lensestest: fake method is not implemented")
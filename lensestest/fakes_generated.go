@@ -0,0 +1,1380 @@
+// Code generated by fakegen; DO NOT EDIT.
+
+package lensestest
+
+import (
+	"context"
+	"time"
+
+	"github.com/landoop/lenses-go"
+)
+
+// FakeSessionAPI is a fake implementation of the `lenses.SessionAPI`.
+// Each method calls its function field, i.e the `GetAccessTokenFunc`,
+// if that field is nil then it returns the zero values, the error result is the `ErrNotImplemented`.
+// The methods that have a "Context" pair call that pair with the context.Background.
+type FakeSessionAPI struct {
+	GetAccessTokenFunc func() string
+	UserFunc           func() lenses.User
+	LogoutContextFunc  func(ctx context.Context) error
+}
+
+var _ lenses.SessionAPI = (*FakeSessionAPI)(nil)
+
+// GetAccessToken implements the `lenses.SessionAPI`.
+func (f *FakeSessionAPI) GetAccessToken() (r0 string) {
+	if f.GetAccessTokenFunc == nil {
+		return
+	}
+
+	return f.GetAccessTokenFunc()
+}
+
+// User implements the `lenses.SessionAPI`.
+func (f *FakeSessionAPI) User() (r0 lenses.User) {
+	if f.UserFunc == nil {
+		return
+	}
+
+	return f.UserFunc()
+}
+
+// Logout implements the `lenses.SessionAPI`.
+func (f *FakeSessionAPI) Logout() (err error) {
+	return f.LogoutContext(context.Background())
+}
+
+// LogoutContext implements the `lenses.SessionAPI`.
+func (f *FakeSessionAPI) LogoutContext(ctx context.Context) (err error) {
+	if f.LogoutContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.LogoutContextFunc(ctx)
+}
+
+// FakeConfigAPI is a fake implementation of the `lenses.ConfigAPI`.
+// Each method calls its function field, i.e the `GetLicenseInfoContextFunc`,
+// if that field is nil then it returns the zero values, the error result is the `ErrNotImplemented`.
+// The methods that have a "Context" pair call that pair with the context.Background.
+type FakeConfigAPI struct {
+	GetLicenseInfoContextFunc     func(ctx context.Context) (lenses.LicenseInfo, error)
+	GetConfigContextFunc          func(ctx context.Context) (map[string]interface{}, error)
+	GetConfigEntryContextFunc     func(ctx context.Context, outPtr interface{}, keys ...string) error
+	GetExecutionModeContextFunc   func(ctx context.Context) (lenses.ExecutionMode, error)
+	GetConnectClustersContextFunc func(ctx context.Context) ([]lenses.ConnectCluster, error)
+}
+
+var _ lenses.ConfigAPI = (*FakeConfigAPI)(nil)
+
+// GetLicenseInfo implements the `lenses.ConfigAPI`.
+func (f *FakeConfigAPI) GetLicenseInfo() (r0 lenses.LicenseInfo, err error) {
+	return f.GetLicenseInfoContext(context.Background())
+}
+
+// GetLicenseInfoContext implements the `lenses.ConfigAPI`.
+func (f *FakeConfigAPI) GetLicenseInfoContext(ctx context.Context) (r0 lenses.LicenseInfo, err error) {
+	if f.GetLicenseInfoContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetLicenseInfoContextFunc(ctx)
+}
+
+// GetConfig implements the `lenses.ConfigAPI`.
+func (f *FakeConfigAPI) GetConfig() (r0 map[string]interface{}, err error) {
+	return f.GetConfigContext(context.Background())
+}
+
+// GetConfigContext implements the `lenses.ConfigAPI`.
+func (f *FakeConfigAPI) GetConfigContext(ctx context.Context) (r0 map[string]interface{}, err error) {
+	if f.GetConfigContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetConfigContextFunc(ctx)
+}
+
+// GetConfigEntry implements the `lenses.ConfigAPI`.
+func (f *FakeConfigAPI) GetConfigEntry(outPtr interface{}, keys ...string) (err error) {
+	return f.GetConfigEntryContext(context.Background(), outPtr, keys...)
+}
+
+// GetConfigEntryContext implements the `lenses.ConfigAPI`.
+func (f *FakeConfigAPI) GetConfigEntryContext(ctx context.Context, outPtr interface{}, keys ...string) (err error) {
+	if f.GetConfigEntryContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetConfigEntryContextFunc(ctx, outPtr, keys...)
+}
+
+// GetExecutionMode implements the `lenses.ConfigAPI`.
+func (f *FakeConfigAPI) GetExecutionMode() (r0 lenses.ExecutionMode, err error) {
+	return f.GetExecutionModeContext(context.Background())
+}
+
+// GetExecutionModeContext implements the `lenses.ConfigAPI`.
+func (f *FakeConfigAPI) GetExecutionModeContext(ctx context.Context) (r0 lenses.ExecutionMode, err error) {
+	if f.GetExecutionModeContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetExecutionModeContextFunc(ctx)
+}
+
+// GetConnectClusters implements the `lenses.ConfigAPI`.
+func (f *FakeConfigAPI) GetConnectClusters() (clusters []lenses.ConnectCluster, err error) {
+	return f.GetConnectClustersContext(context.Background())
+}
+
+// GetConnectClustersContext implements the `lenses.ConfigAPI`.
+func (f *FakeConfigAPI) GetConnectClustersContext(ctx context.Context) (clusters []lenses.ConnectCluster, err error) {
+	if f.GetConnectClustersContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetConnectClustersContextFunc(ctx)
+}
+
+// FakeLSQLAPI is a fake implementation of the `lenses.LSQLAPI`.
+// Each method calls its function field, i.e the `ValidateLSQLContextFunc`,
+// if that field is nil then it returns the zero values, the error result is the `ErrNotImplemented`.
+// The methods that have a "Context" pair call that pair with the context.Background.
+type FakeLSQLAPI struct {
+	ValidateLSQLContextFunc       func(ctx context.Context, sql string) (lenses.LSQLValidation, error)
+	LSQLContextFunc               func(ctx context.Context, sql string, withOffsets bool, statsEvery time.Duration, recordHandler lenses.LSQLRecordHandler, stopHandler lenses.LSQLStopHandler, stopErrHandler lenses.LSQLStopErrorHandler, statsHandler lenses.LSQLStatsHandler) error
+	LSQLWaitContextFunc           func(ctx context.Context, sql string, withOffsets bool, statsEvery time.Duration) ([]lenses.LSQLRecord, lenses.LSQLStats, lenses.LSQLStop, error)
+	QueryFunc                     func(ctx context.Context, sql string, opts lenses.QueryOptions) (*lenses.Rows, error)
+	GetRunningQueriesContextFunc  func(ctx context.Context) ([]lenses.LSQLRunningQuery, error)
+	CancelQueryContextFunc        func(ctx context.Context, id int64) (bool, error)
+	CancelRunningQueryContextFunc func(ctx context.Context, sql string) (bool, error)
+}
+
+var _ lenses.LSQLAPI = (*FakeLSQLAPI)(nil)
+
+// ValidateLSQL implements the `lenses.LSQLAPI`.
+func (f *FakeLSQLAPI) ValidateLSQL(sql string) (v lenses.LSQLValidation, err error) {
+	return f.ValidateLSQLContext(context.Background(), sql)
+}
+
+// ValidateLSQLContext implements the `lenses.LSQLAPI`.
+func (f *FakeLSQLAPI) ValidateLSQLContext(ctx context.Context, sql string) (v lenses.LSQLValidation, err error) {
+	if f.ValidateLSQLContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.ValidateLSQLContextFunc(ctx, sql)
+}
+
+// LSQL implements the `lenses.LSQLAPI`.
+func (f *FakeLSQLAPI) LSQL(sql string, withOffsets bool, statsEvery time.Duration, recordHandler lenses.LSQLRecordHandler, stopHandler lenses.LSQLStopHandler, stopErrHandler lenses.LSQLStopErrorHandler, statsHandler lenses.LSQLStatsHandler) (err error) {
+	return f.LSQLContext(context.Background(), sql, withOffsets, statsEvery, recordHandler, stopHandler, stopErrHandler, statsHandler)
+}
+
+// LSQLContext implements the `lenses.LSQLAPI`.
+func (f *FakeLSQLAPI) LSQLContext(ctx context.Context, sql string, withOffsets bool, statsEvery time.Duration, recordHandler lenses.LSQLRecordHandler, stopHandler lenses.LSQLStopHandler, stopErrHandler lenses.LSQLStopErrorHandler, statsHandler lenses.LSQLStatsHandler) (err error) {
+	if f.LSQLContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.LSQLContextFunc(ctx, sql, withOffsets, statsEvery, recordHandler, stopHandler, stopErrHandler, statsHandler)
+}
+
+// LSQLWait implements the `lenses.LSQLAPI`.
+func (f *FakeLSQLAPI) LSQLWait(sql string, withOffsets bool, statsEvery time.Duration) (records []lenses.LSQLRecord, stats lenses.LSQLStats, stop lenses.LSQLStop, err error) {
+	return f.LSQLWaitContext(context.Background(), sql, withOffsets, statsEvery)
+}
+
+// LSQLWaitContext implements the `lenses.LSQLAPI`.
+func (f *FakeLSQLAPI) LSQLWaitContext(ctx context.Context, sql string, withOffsets bool, statsEvery time.Duration) (records []lenses.LSQLRecord, stats lenses.LSQLStats, stop lenses.LSQLStop, err error) {
+	if f.LSQLWaitContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.LSQLWaitContextFunc(ctx, sql, withOffsets, statsEvery)
+}
+
+// Query implements the `lenses.LSQLAPI`.
+func (f *FakeLSQLAPI) Query(ctx context.Context, sql string, opts lenses.QueryOptions) (r0 *lenses.Rows, err error) {
+	if f.QueryFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.QueryFunc(ctx, sql, opts)
+}
+
+// GetRunningQueries implements the `lenses.LSQLAPI`.
+func (f *FakeLSQLAPI) GetRunningQueries() (r0 []lenses.LSQLRunningQuery, err error) {
+	return f.GetRunningQueriesContext(context.Background())
+}
+
+// GetRunningQueriesContext implements the `lenses.LSQLAPI`.
+func (f *FakeLSQLAPI) GetRunningQueriesContext(ctx context.Context) (r0 []lenses.LSQLRunningQuery, err error) {
+	if f.GetRunningQueriesContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetRunningQueriesContextFunc(ctx)
+}
+
+// CancelQuery implements the `lenses.LSQLAPI`.
+func (f *FakeLSQLAPI) CancelQuery(id int64) (r0 bool, err error) {
+	return f.CancelQueryContext(context.Background(), id)
+}
+
+// CancelQueryContext implements the `lenses.LSQLAPI`.
+func (f *FakeLSQLAPI) CancelQueryContext(ctx context.Context, id int64) (r0 bool, err error) {
+	if f.CancelQueryContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.CancelQueryContextFunc(ctx, id)
+}
+
+// CancelRunningQuery implements the `lenses.LSQLAPI`.
+func (f *FakeLSQLAPI) CancelRunningQuery(sql string) (r0 bool, err error) {
+	return f.CancelRunningQueryContext(context.Background(), sql)
+}
+
+// CancelRunningQueryContext implements the `lenses.LSQLAPI`.
+func (f *FakeLSQLAPI) CancelRunningQueryContext(ctx context.Context, sql string) (r0 bool, err error) {
+	if f.CancelRunningQueryContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.CancelRunningQueryContextFunc(ctx, sql)
+}
+
+// FakeTopicsAPI is a fake implementation of the `lenses.TopicsAPI`.
+// Each method calls its function field, i.e the `GetTopicsContextFunc`,
+// if that field is nil then it returns the zero values, the error result is the `ErrNotImplemented`.
+// The methods that have a "Context" pair call that pair with the context.Background.
+type FakeTopicsAPI struct {
+	GetTopicsContextFunc      func(ctx context.Context) ([]lenses.Topic, error)
+	GetTopicsNamesContextFunc func(ctx context.Context) ([]string, error)
+	GetTopicContextFunc       func(ctx context.Context, topicName string) (lenses.Topic, error)
+	CreateTopicContextFunc    func(ctx context.Context, topicName string, replication int, partitions int, configs lenses.KV) error
+	UpdateTopicContextFunc    func(ctx context.Context, topicName string, configsSlice []lenses.KV) error
+	DeleteTopicContextFunc    func(ctx context.Context, topicName string) error
+}
+
+var _ lenses.TopicsAPI = (*FakeTopicsAPI)(nil)
+
+// GetTopics implements the `lenses.TopicsAPI`.
+func (f *FakeTopicsAPI) GetTopics() (topics []lenses.Topic, err error) {
+	return f.GetTopicsContext(context.Background())
+}
+
+// GetTopicsContext implements the `lenses.TopicsAPI`.
+func (f *FakeTopicsAPI) GetTopicsContext(ctx context.Context) (topics []lenses.Topic, err error) {
+	if f.GetTopicsContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetTopicsContextFunc(ctx)
+}
+
+// GetTopicsNames implements the `lenses.TopicsAPI`.
+func (f *FakeTopicsAPI) GetTopicsNames() (r0 []string, err error) {
+	return f.GetTopicsNamesContext(context.Background())
+}
+
+// GetTopicsNamesContext implements the `lenses.TopicsAPI`.
+func (f *FakeTopicsAPI) GetTopicsNamesContext(ctx context.Context) (r0 []string, err error) {
+	if f.GetTopicsNamesContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetTopicsNamesContextFunc(ctx)
+}
+
+// GetTopic implements the `lenses.TopicsAPI`.
+func (f *FakeTopicsAPI) GetTopic(topicName string) (topic lenses.Topic, err error) {
+	return f.GetTopicContext(context.Background(), topicName)
+}
+
+// GetTopicContext implements the `lenses.TopicsAPI`.
+func (f *FakeTopicsAPI) GetTopicContext(ctx context.Context, topicName string) (topic lenses.Topic, err error) {
+	if f.GetTopicContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetTopicContextFunc(ctx, topicName)
+}
+
+// CreateTopic implements the `lenses.TopicsAPI`.
+func (f *FakeTopicsAPI) CreateTopic(topicName string, replication int, partitions int, configs lenses.KV) (err error) {
+	return f.CreateTopicContext(context.Background(), topicName, replication, partitions, configs)
+}
+
+// CreateTopicContext implements the `lenses.TopicsAPI`.
+func (f *FakeTopicsAPI) CreateTopicContext(ctx context.Context, topicName string, replication int, partitions int, configs lenses.KV) (err error) {
+	if f.CreateTopicContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.CreateTopicContextFunc(ctx, topicName, replication, partitions, configs)
+}
+
+// UpdateTopic implements the `lenses.TopicsAPI`.
+func (f *FakeTopicsAPI) UpdateTopic(topicName string, configsSlice []lenses.KV) (err error) {
+	return f.UpdateTopicContext(context.Background(), topicName, configsSlice)
+}
+
+// UpdateTopicContext implements the `lenses.TopicsAPI`.
+func (f *FakeTopicsAPI) UpdateTopicContext(ctx context.Context, topicName string, configsSlice []lenses.KV) (err error) {
+	if f.UpdateTopicContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.UpdateTopicContextFunc(ctx, topicName, configsSlice)
+}
+
+// DeleteTopic implements the `lenses.TopicsAPI`.
+func (f *FakeTopicsAPI) DeleteTopic(topicName string) (err error) {
+	return f.DeleteTopicContext(context.Background(), topicName)
+}
+
+// DeleteTopicContext implements the `lenses.TopicsAPI`.
+func (f *FakeTopicsAPI) DeleteTopicContext(ctx context.Context, topicName string) (err error) {
+	if f.DeleteTopicContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.DeleteTopicContextFunc(ctx, topicName)
+}
+
+// FakeProcessorsAPI is a fake implementation of the `lenses.ProcessorsAPI`.
+// Each method calls its function field, i.e the `GetProcessorsContextFunc`,
+// if that field is nil then it returns the zero values, the error result is the `ErrNotImplemented`.
+// The methods that have a "Context" pair call that pair with the context.Background.
+type FakeProcessorsAPI struct {
+	GetProcessorsContextFunc             func(ctx context.Context) (lenses.ProcessorsResult, error)
+	LookupProcessorIdentifierContextFunc func(ctx context.Context, id string, name string, clusterName string, namespace string) (string, error)
+	CreateProcessorContextFunc           func(ctx context.Context, name string, sql string, runners int, clusterName string, namespace string, pipeline string) error
+	PauseProcessorContextFunc            func(ctx context.Context, processorID string) error
+	ResumeProcessorContextFunc           func(ctx context.Context, processorID string) error
+	UpdateProcessorRunnersContextFunc    func(ctx context.Context, processorID string, numberOfRunners int) error
+	DeleteProcessorContextFunc           func(ctx context.Context, processorNameOrID string) error
+}
+
+var _ lenses.ProcessorsAPI = (*FakeProcessorsAPI)(nil)
+
+// GetProcessors implements the `lenses.ProcessorsAPI`.
+func (f *FakeProcessorsAPI) GetProcessors() (r0 lenses.ProcessorsResult, err error) {
+	return f.GetProcessorsContext(context.Background())
+}
+
+// GetProcessorsContext implements the `lenses.ProcessorsAPI`.
+func (f *FakeProcessorsAPI) GetProcessorsContext(ctx context.Context) (r0 lenses.ProcessorsResult, err error) {
+	if f.GetProcessorsContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetProcessorsContextFunc(ctx)
+}
+
+// LookupProcessorIdentifier implements the `lenses.ProcessorsAPI`.
+func (f *FakeProcessorsAPI) LookupProcessorIdentifier(id string, name string, clusterName string, namespace string) (r0 string, err error) {
+	return f.LookupProcessorIdentifierContext(context.Background(), id, name, clusterName, namespace)
+}
+
+// LookupProcessorIdentifierContext implements the `lenses.ProcessorsAPI`.
+func (f *FakeProcessorsAPI) LookupProcessorIdentifierContext(ctx context.Context, id string, name string, clusterName string, namespace string) (r0 string, err error) {
+	if f.LookupProcessorIdentifierContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.LookupProcessorIdentifierContextFunc(ctx, id, name, clusterName, namespace)
+}
+
+// CreateProcessor implements the `lenses.ProcessorsAPI`.
+func (f *FakeProcessorsAPI) CreateProcessor(name string, sql string, runners int, clusterName string, namespace string, pipeline string) (err error) {
+	return f.CreateProcessorContext(context.Background(), name, sql, runners, clusterName, namespace, pipeline)
+}
+
+// CreateProcessorContext implements the `lenses.ProcessorsAPI`.
+func (f *FakeProcessorsAPI) CreateProcessorContext(ctx context.Context, name string, sql string, runners int, clusterName string, namespace string, pipeline string) (err error) {
+	if f.CreateProcessorContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.CreateProcessorContextFunc(ctx, name, sql, runners, clusterName, namespace, pipeline)
+}
+
+// PauseProcessor implements the `lenses.ProcessorsAPI`.
+func (f *FakeProcessorsAPI) PauseProcessor(processorID string) (err error) {
+	return f.PauseProcessorContext(context.Background(), processorID)
+}
+
+// PauseProcessorContext implements the `lenses.ProcessorsAPI`.
+func (f *FakeProcessorsAPI) PauseProcessorContext(ctx context.Context, processorID string) (err error) {
+	if f.PauseProcessorContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.PauseProcessorContextFunc(ctx, processorID)
+}
+
+// ResumeProcessor implements the `lenses.ProcessorsAPI`.
+func (f *FakeProcessorsAPI) ResumeProcessor(processorID string) (err error) {
+	return f.ResumeProcessorContext(context.Background(), processorID)
+}
+
+// ResumeProcessorContext implements the `lenses.ProcessorsAPI`.
+func (f *FakeProcessorsAPI) ResumeProcessorContext(ctx context.Context, processorID string) (err error) {
+	if f.ResumeProcessorContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.ResumeProcessorContextFunc(ctx, processorID)
+}
+
+// UpdateProcessorRunners implements the `lenses.ProcessorsAPI`.
+func (f *FakeProcessorsAPI) UpdateProcessorRunners(processorID string, numberOfRunners int) (err error) {
+	return f.UpdateProcessorRunnersContext(context.Background(), processorID, numberOfRunners)
+}
+
+// UpdateProcessorRunnersContext implements the `lenses.ProcessorsAPI`.
+func (f *FakeProcessorsAPI) UpdateProcessorRunnersContext(ctx context.Context, processorID string, numberOfRunners int) (err error) {
+	if f.UpdateProcessorRunnersContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.UpdateProcessorRunnersContextFunc(ctx, processorID, numberOfRunners)
+}
+
+// DeleteProcessor implements the `lenses.ProcessorsAPI`.
+func (f *FakeProcessorsAPI) DeleteProcessor(processorNameOrID string) (err error) {
+	return f.DeleteProcessorContext(context.Background(), processorNameOrID)
+}
+
+// DeleteProcessorContext implements the `lenses.ProcessorsAPI`.
+func (f *FakeProcessorsAPI) DeleteProcessorContext(ctx context.Context, processorNameOrID string) (err error) {
+	if f.DeleteProcessorContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.DeleteProcessorContextFunc(ctx, processorNameOrID)
+}
+
+// FakeConnectorsAPI is a fake implementation of the `lenses.ConnectorsAPI`.
+// Each method calls its function field, i.e the `GetConnectorsContextFunc`,
+// if that field is nil then it returns the zero values, the error result is the `ErrNotImplemented`.
+// The methods that have a "Context" pair call that pair with the context.Background.
+type FakeConnectorsAPI struct {
+	GetConnectorsContextFunc          func(ctx context.Context, clusterName string) ([]string, error)
+	GetConnectorContextFunc           func(ctx context.Context, clusterName string, name string) (lenses.Connector, error)
+	GetConnectorConfigContextFunc     func(ctx context.Context, clusterName string, name string) (lenses.ConnectorConfig, error)
+	GetConnectorStatusContextFunc     func(ctx context.Context, clusterName string, name string) (lenses.ConnectorStatus, error)
+	CreateConnectorContextFunc        func(ctx context.Context, clusterName string, name string, config lenses.ConnectorConfig) (lenses.Connector, error)
+	UpdateConnectorContextFunc        func(ctx context.Context, clusterName string, name string, config lenses.ConnectorConfig) (lenses.Connector, error)
+	PauseConnectorContextFunc         func(ctx context.Context, clusterName string, name string) error
+	ResumeConnectorContextFunc        func(ctx context.Context, clusterName string, name string) error
+	RestartConnectorContextFunc       func(ctx context.Context, clusterName string, name string) error
+	DeleteConnectorContextFunc        func(ctx context.Context, clusterName string, name string) error
+	GetConnectorTasksContextFunc      func(ctx context.Context, clusterName string, name string) ([]map[string]interface{}, error)
+	GetConnectorTaskStatusContextFunc func(ctx context.Context, clusterName string, name string, taskID int) (lenses.ConnectorStatusTask, error)
+	RestartConnectorTaskContextFunc   func(ctx context.Context, clusterName string, name string, taskID int) error
+	GetConnectorPluginsContextFunc    func(ctx context.Context, clusterName string) ([]lenses.ConnectorPlugin, error)
+}
+
+var _ lenses.ConnectorsAPI = (*FakeConnectorsAPI)(nil)
+
+// GetConnectors implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) GetConnectors(clusterName string) (names []string, err error) {
+	return f.GetConnectorsContext(context.Background(), clusterName)
+}
+
+// GetConnectorsContext implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) GetConnectorsContext(ctx context.Context, clusterName string) (names []string, err error) {
+	if f.GetConnectorsContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetConnectorsContextFunc(ctx, clusterName)
+}
+
+// GetConnector implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) GetConnector(clusterName string, name string) (connector lenses.Connector, err error) {
+	return f.GetConnectorContext(context.Background(), clusterName, name)
+}
+
+// GetConnectorContext implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) GetConnectorContext(ctx context.Context, clusterName string, name string) (connector lenses.Connector, err error) {
+	if f.GetConnectorContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetConnectorContextFunc(ctx, clusterName, name)
+}
+
+// GetConnectorConfig implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) GetConnectorConfig(clusterName string, name string) (cfg lenses.ConnectorConfig, err error) {
+	return f.GetConnectorConfigContext(context.Background(), clusterName, name)
+}
+
+// GetConnectorConfigContext implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) GetConnectorConfigContext(ctx context.Context, clusterName string, name string) (cfg lenses.ConnectorConfig, err error) {
+	if f.GetConnectorConfigContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetConnectorConfigContextFunc(ctx, clusterName, name)
+}
+
+// GetConnectorStatus implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) GetConnectorStatus(clusterName string, name string) (cs lenses.ConnectorStatus, err error) {
+	return f.GetConnectorStatusContext(context.Background(), clusterName, name)
+}
+
+// GetConnectorStatusContext implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) GetConnectorStatusContext(ctx context.Context, clusterName string, name string) (cs lenses.ConnectorStatus, err error) {
+	if f.GetConnectorStatusContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetConnectorStatusContextFunc(ctx, clusterName, name)
+}
+
+// CreateConnector implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) CreateConnector(clusterName string, name string, config lenses.ConnectorConfig) (connector lenses.Connector, err error) {
+	return f.CreateConnectorContext(context.Background(), clusterName, name, config)
+}
+
+// CreateConnectorContext implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) CreateConnectorContext(ctx context.Context, clusterName string, name string, config lenses.ConnectorConfig) (connector lenses.Connector, err error) {
+	if f.CreateConnectorContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.CreateConnectorContextFunc(ctx, clusterName, name, config)
+}
+
+// UpdateConnector implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) UpdateConnector(clusterName string, name string, config lenses.ConnectorConfig) (connector lenses.Connector, err error) {
+	return f.UpdateConnectorContext(context.Background(), clusterName, name, config)
+}
+
+// UpdateConnectorContext implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) UpdateConnectorContext(ctx context.Context, clusterName string, name string, config lenses.ConnectorConfig) (connector lenses.Connector, err error) {
+	if f.UpdateConnectorContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.UpdateConnectorContextFunc(ctx, clusterName, name, config)
+}
+
+// PauseConnector implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) PauseConnector(clusterName string, name string) (err error) {
+	return f.PauseConnectorContext(context.Background(), clusterName, name)
+}
+
+// PauseConnectorContext implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) PauseConnectorContext(ctx context.Context, clusterName string, name string) (err error) {
+	if f.PauseConnectorContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.PauseConnectorContextFunc(ctx, clusterName, name)
+}
+
+// ResumeConnector implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) ResumeConnector(clusterName string, name string) (err error) {
+	return f.ResumeConnectorContext(context.Background(), clusterName, name)
+}
+
+// ResumeConnectorContext implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) ResumeConnectorContext(ctx context.Context, clusterName string, name string) (err error) {
+	if f.ResumeConnectorContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.ResumeConnectorContextFunc(ctx, clusterName, name)
+}
+
+// RestartConnector implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) RestartConnector(clusterName string, name string) (err error) {
+	return f.RestartConnectorContext(context.Background(), clusterName, name)
+}
+
+// RestartConnectorContext implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) RestartConnectorContext(ctx context.Context, clusterName string, name string) (err error) {
+	if f.RestartConnectorContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.RestartConnectorContextFunc(ctx, clusterName, name)
+}
+
+// DeleteConnector implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) DeleteConnector(clusterName string, name string) (err error) {
+	return f.DeleteConnectorContext(context.Background(), clusterName, name)
+}
+
+// DeleteConnectorContext implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) DeleteConnectorContext(ctx context.Context, clusterName string, name string) (err error) {
+	if f.DeleteConnectorContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.DeleteConnectorContextFunc(ctx, clusterName, name)
+}
+
+// GetConnectorTasks implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) GetConnectorTasks(clusterName string, name string) (m []map[string]interface{}, err error) {
+	return f.GetConnectorTasksContext(context.Background(), clusterName, name)
+}
+
+// GetConnectorTasksContext implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) GetConnectorTasksContext(ctx context.Context, clusterName string, name string) (m []map[string]interface{}, err error) {
+	if f.GetConnectorTasksContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetConnectorTasksContextFunc(ctx, clusterName, name)
+}
+
+// GetConnectorTaskStatus implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) GetConnectorTaskStatus(clusterName string, name string, taskID int) (cst lenses.ConnectorStatusTask, err error) {
+	return f.GetConnectorTaskStatusContext(context.Background(), clusterName, name, taskID)
+}
+
+// GetConnectorTaskStatusContext implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) GetConnectorTaskStatusContext(ctx context.Context, clusterName string, name string, taskID int) (cst lenses.ConnectorStatusTask, err error) {
+	if f.GetConnectorTaskStatusContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetConnectorTaskStatusContextFunc(ctx, clusterName, name, taskID)
+}
+
+// RestartConnectorTask implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) RestartConnectorTask(clusterName string, name string, taskID int) (err error) {
+	return f.RestartConnectorTaskContext(context.Background(), clusterName, name, taskID)
+}
+
+// RestartConnectorTaskContext implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) RestartConnectorTaskContext(ctx context.Context, clusterName string, name string, taskID int) (err error) {
+	if f.RestartConnectorTaskContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.RestartConnectorTaskContextFunc(ctx, clusterName, name, taskID)
+}
+
+// GetConnectorPlugins implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) GetConnectorPlugins(clusterName string) (cp []lenses.ConnectorPlugin, err error) {
+	return f.GetConnectorPluginsContext(context.Background(), clusterName)
+}
+
+// GetConnectorPluginsContext implements the `lenses.ConnectorsAPI`.
+func (f *FakeConnectorsAPI) GetConnectorPluginsContext(ctx context.Context, clusterName string) (cp []lenses.ConnectorPlugin, err error) {
+	if f.GetConnectorPluginsContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetConnectorPluginsContextFunc(ctx, clusterName)
+}
+
+// FakeSchemaRegistryAPI is a fake implementation of the `lenses.SchemaRegistryAPI`.
+// Each method calls its function field, i.e the `GetSubjectsContextFunc`,
+// if that field is nil then it returns the zero values, the error result is the `ErrNotImplemented`.
+// The methods that have a "Context" pair call that pair with the context.Background.
+type FakeSchemaRegistryAPI struct {
+	GetSubjectsContextFunc                     func(ctx context.Context) ([]string, error)
+	GetSubjectVersionsContextFunc              func(ctx context.Context, subject string) ([]int, error)
+	DeleteSubjectContextFunc                   func(ctx context.Context, subject string) ([]int, error)
+	GetSchemaContextFunc                       func(ctx context.Context, subjectID int) (string, error)
+	GetLatestSchemaContextFunc                 func(ctx context.Context, subject string) (lenses.Schema, error)
+	GetSchemaAtVersionContextFunc              func(ctx context.Context, subject string, versionID int) (lenses.Schema, error)
+	RegisterSchemaContextFunc                  func(ctx context.Context, subject string, avroSchema string) (int, error)
+	DeleteSubjectVersionContextFunc            func(ctx context.Context, subject string, versionID int) (int, error)
+	DeleteLatestSubjectVersionContextFunc      func(ctx context.Context, subject string) (int, error)
+	GetGlobalCompatibilityLevelContextFunc     func(ctx context.Context) (lenses.CompatibilityLevel, error)
+	UpdateGlobalCompatibilityLevelContextFunc  func(ctx context.Context, level lenses.CompatibilityLevel) error
+	GetSubjectCompatibilityLevelContextFunc    func(ctx context.Context, subject string) (lenses.CompatibilityLevel, error)
+	UpdateSubjectCompatibilityLevelContextFunc func(ctx context.Context, subject string, level lenses.CompatibilityLevel) error
+}
+
+var _ lenses.SchemaRegistryAPI = (*FakeSchemaRegistryAPI)(nil)
+
+// GetSubjects implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) GetSubjects() (subjects []string, err error) {
+	return f.GetSubjectsContext(context.Background())
+}
+
+// GetSubjectsContext implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) GetSubjectsContext(ctx context.Context) (subjects []string, err error) {
+	if f.GetSubjectsContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetSubjectsContextFunc(ctx)
+}
+
+// GetSubjectVersions implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) GetSubjectVersions(subject string) (versions []int, err error) {
+	return f.GetSubjectVersionsContext(context.Background(), subject)
+}
+
+// GetSubjectVersionsContext implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) GetSubjectVersionsContext(ctx context.Context, subject string) (versions []int, err error) {
+	if f.GetSubjectVersionsContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetSubjectVersionsContextFunc(ctx, subject)
+}
+
+// DeleteSubject implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) DeleteSubject(subject string) (versions []int, err error) {
+	return f.DeleteSubjectContext(context.Background(), subject)
+}
+
+// DeleteSubjectContext implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) DeleteSubjectContext(ctx context.Context, subject string) (versions []int, err error) {
+	if f.DeleteSubjectContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.DeleteSubjectContextFunc(ctx, subject)
+}
+
+// GetSchema implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) GetSchema(subjectID int) (r0 string, err error) {
+	return f.GetSchemaContext(context.Background(), subjectID)
+}
+
+// GetSchemaContext implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) GetSchemaContext(ctx context.Context, subjectID int) (r0 string, err error) {
+	if f.GetSchemaContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetSchemaContextFunc(ctx, subjectID)
+}
+
+// GetLatestSchema implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) GetLatestSchema(subject string) (r0 lenses.Schema, err error) {
+	return f.GetLatestSchemaContext(context.Background(), subject)
+}
+
+// GetLatestSchemaContext implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) GetLatestSchemaContext(ctx context.Context, subject string) (r0 lenses.Schema, err error) {
+	if f.GetLatestSchemaContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetLatestSchemaContextFunc(ctx, subject)
+}
+
+// GetSchemaAtVersion implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) GetSchemaAtVersion(subject string, versionID int) (r0 lenses.Schema, err error) {
+	return f.GetSchemaAtVersionContext(context.Background(), subject, versionID)
+}
+
+// GetSchemaAtVersionContext implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) GetSchemaAtVersionContext(ctx context.Context, subject string, versionID int) (r0 lenses.Schema, err error) {
+	if f.GetSchemaAtVersionContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetSchemaAtVersionContextFunc(ctx, subject, versionID)
+}
+
+// RegisterSchema implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) RegisterSchema(subject string, avroSchema string) (r0 int, err error) {
+	return f.RegisterSchemaContext(context.Background(), subject, avroSchema)
+}
+
+// RegisterSchemaContext implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) RegisterSchemaContext(ctx context.Context, subject string, avroSchema string) (r0 int, err error) {
+	if f.RegisterSchemaContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.RegisterSchemaContextFunc(ctx, subject, avroSchema)
+}
+
+// DeleteSubjectVersion implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) DeleteSubjectVersion(subject string, versionID int) (r0 int, err error) {
+	return f.DeleteSubjectVersionContext(context.Background(), subject, versionID)
+}
+
+// DeleteSubjectVersionContext implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) DeleteSubjectVersionContext(ctx context.Context, subject string, versionID int) (r0 int, err error) {
+	if f.DeleteSubjectVersionContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.DeleteSubjectVersionContextFunc(ctx, subject, versionID)
+}
+
+// DeleteLatestSubjectVersion implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) DeleteLatestSubjectVersion(subject string) (r0 int, err error) {
+	return f.DeleteLatestSubjectVersionContext(context.Background(), subject)
+}
+
+// DeleteLatestSubjectVersionContext implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) DeleteLatestSubjectVersionContext(ctx context.Context, subject string) (r0 int, err error) {
+	if f.DeleteLatestSubjectVersionContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.DeleteLatestSubjectVersionContextFunc(ctx, subject)
+}
+
+// GetGlobalCompatibilityLevel implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) GetGlobalCompatibilityLevel() (level lenses.CompatibilityLevel, err error) {
+	return f.GetGlobalCompatibilityLevelContext(context.Background())
+}
+
+// GetGlobalCompatibilityLevelContext implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) GetGlobalCompatibilityLevelContext(ctx context.Context) (level lenses.CompatibilityLevel, err error) {
+	if f.GetGlobalCompatibilityLevelContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetGlobalCompatibilityLevelContextFunc(ctx)
+}
+
+// UpdateGlobalCompatibilityLevel implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) UpdateGlobalCompatibilityLevel(level lenses.CompatibilityLevel) (err error) {
+	return f.UpdateGlobalCompatibilityLevelContext(context.Background(), level)
+}
+
+// UpdateGlobalCompatibilityLevelContext implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) UpdateGlobalCompatibilityLevelContext(ctx context.Context, level lenses.CompatibilityLevel) (err error) {
+	if f.UpdateGlobalCompatibilityLevelContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.UpdateGlobalCompatibilityLevelContextFunc(ctx, level)
+}
+
+// GetSubjectCompatibilityLevel implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) GetSubjectCompatibilityLevel(subject string) (level lenses.CompatibilityLevel, err error) {
+	return f.GetSubjectCompatibilityLevelContext(context.Background(), subject)
+}
+
+// GetSubjectCompatibilityLevelContext implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) GetSubjectCompatibilityLevelContext(ctx context.Context, subject string) (level lenses.CompatibilityLevel, err error) {
+	if f.GetSubjectCompatibilityLevelContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetSubjectCompatibilityLevelContextFunc(ctx, subject)
+}
+
+// UpdateSubjectCompatibilityLevel implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) UpdateSubjectCompatibilityLevel(subject string, level lenses.CompatibilityLevel) (err error) {
+	return f.UpdateSubjectCompatibilityLevelContext(context.Background(), subject, level)
+}
+
+// UpdateSubjectCompatibilityLevelContext implements the `lenses.SchemaRegistryAPI`.
+func (f *FakeSchemaRegistryAPI) UpdateSubjectCompatibilityLevelContext(ctx context.Context, subject string, level lenses.CompatibilityLevel) (err error) {
+	if f.UpdateSubjectCompatibilityLevelContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.UpdateSubjectCompatibilityLevelContextFunc(ctx, subject, level)
+}
+
+// FakeACLAPI is a fake implementation of the `lenses.ACLAPI`.
+// Each method calls its function field, i.e the `GetACLsContextFunc`,
+// if that field is nil then it returns the zero values, the error result is the `ErrNotImplemented`.
+// The methods that have a "Context" pair call that pair with the context.Background.
+type FakeACLAPI struct {
+	GetACLsContextFunc           func(ctx context.Context) ([]lenses.ACL, error)
+	CreateOrUpdateACLContextFunc func(ctx context.Context, acl lenses.ACL) error
+	DeleteACLContextFunc         func(ctx context.Context, acl lenses.ACL) error
+}
+
+var _ lenses.ACLAPI = (*FakeACLAPI)(nil)
+
+// GetACLs implements the `lenses.ACLAPI`.
+func (f *FakeACLAPI) GetACLs() (r0 []lenses.ACL, err error) {
+	return f.GetACLsContext(context.Background())
+}
+
+// GetACLsContext implements the `lenses.ACLAPI`.
+func (f *FakeACLAPI) GetACLsContext(ctx context.Context) (r0 []lenses.ACL, err error) {
+	if f.GetACLsContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetACLsContextFunc(ctx)
+}
+
+// CreateOrUpdateACL implements the `lenses.ACLAPI`.
+func (f *FakeACLAPI) CreateOrUpdateACL(acl lenses.ACL) (err error) {
+	return f.CreateOrUpdateACLContext(context.Background(), acl)
+}
+
+// CreateOrUpdateACLContext implements the `lenses.ACLAPI`.
+func (f *FakeACLAPI) CreateOrUpdateACLContext(ctx context.Context, acl lenses.ACL) (err error) {
+	if f.CreateOrUpdateACLContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.CreateOrUpdateACLContextFunc(ctx, acl)
+}
+
+// DeleteACL implements the `lenses.ACLAPI`.
+func (f *FakeACLAPI) DeleteACL(acl lenses.ACL) (err error) {
+	return f.DeleteACLContext(context.Background(), acl)
+}
+
+// DeleteACLContext implements the `lenses.ACLAPI`.
+func (f *FakeACLAPI) DeleteACLContext(ctx context.Context, acl lenses.ACL) (err error) {
+	if f.DeleteACLContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.DeleteACLContextFunc(ctx, acl)
+}
+
+// FakeQuotaAPI is a fake implementation of the `lenses.QuotaAPI`.
+// Each method calls its function field, i.e the `GetQuotasContextFunc`,
+// if that field is nil then it returns the zero values, the error result is the `ErrNotImplemented`.
+// The methods that have a "Context" pair call that pair with the context.Background.
+type FakeQuotaAPI struct {
+	GetQuotasContextFunc                            func(ctx context.Context) ([]lenses.Quota, error)
+	CreateOrUpdateQuotaForAllUsersContextFunc       func(ctx context.Context, config lenses.QuotaConfig) error
+	DeleteQuotaForAllUsersContextFunc               func(ctx context.Context, propertiesToRemove ...string) error
+	CreateOrUpdateQuotaForUserContextFunc           func(ctx context.Context, user string, config lenses.QuotaConfig) error
+	DeleteQuotaForUserContextFunc                   func(ctx context.Context, user string, propertiesToRemove ...string) error
+	CreateOrUpdateQuotaForUserAllClientsContextFunc func(ctx context.Context, user string, config lenses.QuotaConfig) error
+	DeleteQuotaForUserAllClientsContextFunc         func(ctx context.Context, user string, propertiesToRemove ...string) error
+	CreateOrUpdateQuotaForUserClientContextFunc     func(ctx context.Context, user string, clientID string, config lenses.QuotaConfig) error
+	DeleteQuotaForUserClientContextFunc             func(ctx context.Context, user string, clientID string, propertiesToRemove ...string) error
+	CreateOrUpdateQuotaForAllClientsContextFunc     func(ctx context.Context, config lenses.QuotaConfig) error
+	DeleteQuotaForAllClientsContextFunc             func(ctx context.Context, propertiesToRemove ...string) error
+	CreateOrUpdateQuotaForClientContextFunc         func(ctx context.Context, clientID string, config lenses.QuotaConfig) error
+	DeleteQuotaForClientContextFunc                 func(ctx context.Context, clientID string, propertiesToRemove ...string) error
+}
+
+var _ lenses.QuotaAPI = (*FakeQuotaAPI)(nil)
+
+// GetQuotas implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) GetQuotas() (r0 []lenses.Quota, err error) {
+	return f.GetQuotasContext(context.Background())
+}
+
+// GetQuotasContext implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) GetQuotasContext(ctx context.Context) (r0 []lenses.Quota, err error) {
+	if f.GetQuotasContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetQuotasContextFunc(ctx)
+}
+
+// CreateOrUpdateQuotaForAllUsers implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) CreateOrUpdateQuotaForAllUsers(config lenses.QuotaConfig) (err error) {
+	return f.CreateOrUpdateQuotaForAllUsersContext(context.Background(), config)
+}
+
+// CreateOrUpdateQuotaForAllUsersContext implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) CreateOrUpdateQuotaForAllUsersContext(ctx context.Context, config lenses.QuotaConfig) (err error) {
+	if f.CreateOrUpdateQuotaForAllUsersContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.CreateOrUpdateQuotaForAllUsersContextFunc(ctx, config)
+}
+
+// DeleteQuotaForAllUsers implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) DeleteQuotaForAllUsers(propertiesToRemove ...string) (err error) {
+	return f.DeleteQuotaForAllUsersContext(context.Background(), propertiesToRemove...)
+}
+
+// DeleteQuotaForAllUsersContext implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) DeleteQuotaForAllUsersContext(ctx context.Context, propertiesToRemove ...string) (err error) {
+	if f.DeleteQuotaForAllUsersContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.DeleteQuotaForAllUsersContextFunc(ctx, propertiesToRemove...)
+}
+
+// CreateOrUpdateQuotaForUser implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) CreateOrUpdateQuotaForUser(user string, config lenses.QuotaConfig) (err error) {
+	return f.CreateOrUpdateQuotaForUserContext(context.Background(), user, config)
+}
+
+// CreateOrUpdateQuotaForUserContext implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) CreateOrUpdateQuotaForUserContext(ctx context.Context, user string, config lenses.QuotaConfig) (err error) {
+	if f.CreateOrUpdateQuotaForUserContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.CreateOrUpdateQuotaForUserContextFunc(ctx, user, config)
+}
+
+// DeleteQuotaForUser implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) DeleteQuotaForUser(user string, propertiesToRemove ...string) (err error) {
+	return f.DeleteQuotaForUserContext(context.Background(), user, propertiesToRemove...)
+}
+
+// DeleteQuotaForUserContext implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) DeleteQuotaForUserContext(ctx context.Context, user string, propertiesToRemove ...string) (err error) {
+	if f.DeleteQuotaForUserContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.DeleteQuotaForUserContextFunc(ctx, user, propertiesToRemove...)
+}
+
+// CreateOrUpdateQuotaForUserAllClients implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) CreateOrUpdateQuotaForUserAllClients(user string, config lenses.QuotaConfig) (err error) {
+	return f.CreateOrUpdateQuotaForUserAllClientsContext(context.Background(), user, config)
+}
+
+// CreateOrUpdateQuotaForUserAllClientsContext implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) CreateOrUpdateQuotaForUserAllClientsContext(ctx context.Context, user string, config lenses.QuotaConfig) (err error) {
+	if f.CreateOrUpdateQuotaForUserAllClientsContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.CreateOrUpdateQuotaForUserAllClientsContextFunc(ctx, user, config)
+}
+
+// DeleteQuotaForUserAllClients implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) DeleteQuotaForUserAllClients(user string, propertiesToRemove ...string) (err error) {
+	return f.DeleteQuotaForUserAllClientsContext(context.Background(), user, propertiesToRemove...)
+}
+
+// DeleteQuotaForUserAllClientsContext implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) DeleteQuotaForUserAllClientsContext(ctx context.Context, user string, propertiesToRemove ...string) (err error) {
+	if f.DeleteQuotaForUserAllClientsContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.DeleteQuotaForUserAllClientsContextFunc(ctx, user, propertiesToRemove...)
+}
+
+// CreateOrUpdateQuotaForUserClient implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) CreateOrUpdateQuotaForUserClient(user string, clientID string, config lenses.QuotaConfig) (err error) {
+	return f.CreateOrUpdateQuotaForUserClientContext(context.Background(), user, clientID, config)
+}
+
+// CreateOrUpdateQuotaForUserClientContext implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) CreateOrUpdateQuotaForUserClientContext(ctx context.Context, user string, clientID string, config lenses.QuotaConfig) (err error) {
+	if f.CreateOrUpdateQuotaForUserClientContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.CreateOrUpdateQuotaForUserClientContextFunc(ctx, user, clientID, config)
+}
+
+// DeleteQuotaForUserClient implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) DeleteQuotaForUserClient(user string, clientID string, propertiesToRemove ...string) (err error) {
+	return f.DeleteQuotaForUserClientContext(context.Background(), user, clientID, propertiesToRemove...)
+}
+
+// DeleteQuotaForUserClientContext implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) DeleteQuotaForUserClientContext(ctx context.Context, user string, clientID string, propertiesToRemove ...string) (err error) {
+	if f.DeleteQuotaForUserClientContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.DeleteQuotaForUserClientContextFunc(ctx, user, clientID, propertiesToRemove...)
+}
+
+// CreateOrUpdateQuotaForAllClients implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) CreateOrUpdateQuotaForAllClients(config lenses.QuotaConfig) (err error) {
+	return f.CreateOrUpdateQuotaForAllClientsContext(context.Background(), config)
+}
+
+// CreateOrUpdateQuotaForAllClientsContext implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) CreateOrUpdateQuotaForAllClientsContext(ctx context.Context, config lenses.QuotaConfig) (err error) {
+	if f.CreateOrUpdateQuotaForAllClientsContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.CreateOrUpdateQuotaForAllClientsContextFunc(ctx, config)
+}
+
+// DeleteQuotaForAllClients implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) DeleteQuotaForAllClients(propertiesToRemove ...string) (err error) {
+	return f.DeleteQuotaForAllClientsContext(context.Background(), propertiesToRemove...)
+}
+
+// DeleteQuotaForAllClientsContext implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) DeleteQuotaForAllClientsContext(ctx context.Context, propertiesToRemove ...string) (err error) {
+	if f.DeleteQuotaForAllClientsContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.DeleteQuotaForAllClientsContextFunc(ctx, propertiesToRemove...)
+}
+
+// CreateOrUpdateQuotaForClient implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) CreateOrUpdateQuotaForClient(clientID string, config lenses.QuotaConfig) (err error) {
+	return f.CreateOrUpdateQuotaForClientContext(context.Background(), clientID, config)
+}
+
+// CreateOrUpdateQuotaForClientContext implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) CreateOrUpdateQuotaForClientContext(ctx context.Context, clientID string, config lenses.QuotaConfig) (err error) {
+	if f.CreateOrUpdateQuotaForClientContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.CreateOrUpdateQuotaForClientContextFunc(ctx, clientID, config)
+}
+
+// DeleteQuotaForClient implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) DeleteQuotaForClient(clientID string, propertiesToRemove ...string) (err error) {
+	return f.DeleteQuotaForClientContext(context.Background(), clientID, propertiesToRemove...)
+}
+
+// DeleteQuotaForClientContext implements the `lenses.QuotaAPI`.
+func (f *FakeQuotaAPI) DeleteQuotaForClientContext(ctx context.Context, clientID string, propertiesToRemove ...string) (err error) {
+	if f.DeleteQuotaForClientContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.DeleteQuotaForClientContextFunc(ctx, clientID, propertiesToRemove...)
+}
+
+// FakeAlertsAPI is a fake implementation of the `lenses.AlertsAPI`.
+// Each method calls its function field, i.e the `GetAlertsContextFunc`,
+// if that field is nil then it returns the zero values, the error result is the `ErrNotImplemented`.
+// The methods that have a "Context" pair call that pair with the context.Background.
+type FakeAlertsAPI struct {
+	GetAlertsContextFunc                           func(ctx context.Context) ([]lenses.Alert, error)
+	RegisterAlertContextFunc                       func(ctx context.Context, alert lenses.Alert) error
+	GetAlertsLiveContextFunc                       func(ctx context.Context, handler lenses.AlertHandler) error
+	GetAlertSettingsContextFunc                    func(ctx context.Context) (lenses.AlertSettings, error)
+	GetAlertSettingContextFunc                     func(ctx context.Context, id int) (lenses.AlertSetting, error)
+	EnableAlertSettingContextFunc                  func(ctx context.Context, id int) error
+	GetAlertSettingConditionsContextFunc           func(ctx context.Context, id int) (lenses.AlertSettingConditions, error)
+	CreateOrUpdateAlertSettingConditionContextFunc func(ctx context.Context, alertSettingID int, condition string) error
+	DeleteAlertSettingConditionContextFunc         func(ctx context.Context, alertSettingID int, conditionUUID string) error
+}
+
+var _ lenses.AlertsAPI = (*FakeAlertsAPI)(nil)
+
+// GetAlerts implements the `lenses.AlertsAPI`.
+func (f *FakeAlertsAPI) GetAlerts() (alerts []lenses.Alert, err error) {
+	return f.GetAlertsContext(context.Background())
+}
+
+// GetAlertsContext implements the `lenses.AlertsAPI`.
+func (f *FakeAlertsAPI) GetAlertsContext(ctx context.Context) (alerts []lenses.Alert, err error) {
+	if f.GetAlertsContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetAlertsContextFunc(ctx)
+}
+
+// RegisterAlert implements the `lenses.AlertsAPI`.
+func (f *FakeAlertsAPI) RegisterAlert(alert lenses.Alert) (err error) {
+	return f.RegisterAlertContext(context.Background(), alert)
+}
+
+// RegisterAlertContext implements the `lenses.AlertsAPI`.
+func (f *FakeAlertsAPI) RegisterAlertContext(ctx context.Context, alert lenses.Alert) (err error) {
+	if f.RegisterAlertContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.RegisterAlertContextFunc(ctx, alert)
+}
+
+// GetAlertsLive implements the `lenses.AlertsAPI`.
+func (f *FakeAlertsAPI) GetAlertsLive(handler lenses.AlertHandler) (err error) {
+	return f.GetAlertsLiveContext(context.Background(), handler)
+}
+
+// GetAlertsLiveContext implements the `lenses.AlertsAPI`.
+func (f *FakeAlertsAPI) GetAlertsLiveContext(ctx context.Context, handler lenses.AlertHandler) (err error) {
+	if f.GetAlertsLiveContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetAlertsLiveContextFunc(ctx, handler)
+}
+
+// GetAlertSettings implements the `lenses.AlertsAPI`.
+func (f *FakeAlertsAPI) GetAlertSettings() (r0 lenses.AlertSettings, err error) {
+	return f.GetAlertSettingsContext(context.Background())
+}
+
+// GetAlertSettingsContext implements the `lenses.AlertsAPI`.
+func (f *FakeAlertsAPI) GetAlertSettingsContext(ctx context.Context) (r0 lenses.AlertSettings, err error) {
+	if f.GetAlertSettingsContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetAlertSettingsContextFunc(ctx)
+}
+
+// GetAlertSetting implements the `lenses.AlertsAPI`.
+func (f *FakeAlertsAPI) GetAlertSetting(id int) (setting lenses.AlertSetting, err error) {
+	return f.GetAlertSettingContext(context.Background(), id)
+}
+
+// GetAlertSettingContext implements the `lenses.AlertsAPI`.
+func (f *FakeAlertsAPI) GetAlertSettingContext(ctx context.Context, id int) (setting lenses.AlertSetting, err error) {
+	if f.GetAlertSettingContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetAlertSettingContextFunc(ctx, id)
+}
+
+// EnableAlertSetting implements the `lenses.AlertsAPI`.
+func (f *FakeAlertsAPI) EnableAlertSetting(id int) (err error) {
+	return f.EnableAlertSettingContext(context.Background(), id)
+}
+
+// EnableAlertSettingContext implements the `lenses.AlertsAPI`.
+func (f *FakeAlertsAPI) EnableAlertSettingContext(ctx context.Context, id int) (err error) {
+	if f.EnableAlertSettingContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.EnableAlertSettingContextFunc(ctx, id)
+}
+
+// GetAlertSettingConditions implements the `lenses.AlertsAPI`.
+func (f *FakeAlertsAPI) GetAlertSettingConditions(id int) (r0 lenses.AlertSettingConditions, err error) {
+	return f.GetAlertSettingConditionsContext(context.Background(), id)
+}
+
+// GetAlertSettingConditionsContext implements the `lenses.AlertsAPI`.
+func (f *FakeAlertsAPI) GetAlertSettingConditionsContext(ctx context.Context, id int) (r0 lenses.AlertSettingConditions, err error) {
+	if f.GetAlertSettingConditionsContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.GetAlertSettingConditionsContextFunc(ctx, id)
+}
+
+// CreateOrUpdateAlertSettingCondition implements the `lenses.AlertsAPI`.
+func (f *FakeAlertsAPI) CreateOrUpdateAlertSettingCondition(alertSettingID int, condition string) (err error) {
+	return f.CreateOrUpdateAlertSettingConditionContext(context.Background(), alertSettingID, condition)
+}
+
+// CreateOrUpdateAlertSettingConditionContext implements the `lenses.AlertsAPI`.
+func (f *FakeAlertsAPI) CreateOrUpdateAlertSettingConditionContext(ctx context.Context, alertSettingID int, condition string) (err error) {
+	if f.CreateOrUpdateAlertSettingConditionContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.CreateOrUpdateAlertSettingConditionContextFunc(ctx, alertSettingID, condition)
+}
+
+// DeleteAlertSettingCondition implements the `lenses.AlertsAPI`.
+func (f *FakeAlertsAPI) DeleteAlertSettingCondition(alertSettingID int, conditionUUID string) (err error) {
+	return f.DeleteAlertSettingConditionContext(context.Background(), alertSettingID, conditionUUID)
+}
+
+// DeleteAlertSettingConditionContext implements the `lenses.AlertsAPI`.
+func (f *FakeAlertsAPI) DeleteAlertSettingConditionContext(ctx context.Context, alertSettingID int, conditionUUID string) (err error) {
+	if f.DeleteAlertSettingConditionContextFunc == nil {
+		err = ErrNotImplemented
+		return
+	}
+
+	return f.DeleteAlertSettingConditionContextFunc(ctx, alertSettingID, conditionUUID)
+}
+
+// FakeAPI is a fake implementation of the `lenses.API`, it's composed by the domain fakes,
+// the behavior of each method can be set through the function fields of the embedded fakes.
+type FakeAPI struct {
+	FakeSessionAPI
+	FakeConfigAPI
+	FakeLSQLAPI
+	FakeTopicsAPI
+	FakeProcessorsAPI
+	FakeConnectorsAPI
+	FakeSchemaRegistryAPI
+	FakeACLAPI
+	FakeQuotaAPI
+	FakeAlertsAPI
+}
+
+var _ lenses.API = (*FakeAPI)(nil)
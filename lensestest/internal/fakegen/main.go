@@ -0,0 +1,284 @@
+// Command fakegen generates the fake implementations of the lenses domain interfaces, i.e `lenses.TopicsAPI`,
+// it's used by the `go generate` of the lensestest package.
+//
+// Usage:
+// go run ./internal/fakegen -src ../api.go -out fakes_generated.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"strings"
+)
+
+const (
+	lensesPkgName = "lenses"
+	lensesPkgPath = "github.com/landoop/lenses-go"
+	contextSuffix = "Context"
+)
+
+func main() {
+	var (
+		src = flag.String("src", "../api.go", "the file that contains the lenses interfaces")
+		out = flag.String("out", "fakes_generated.go", "the file to write the fakes to")
+		pkg = flag.String("pkg", "lensestest", "the package name of the generated file")
+	)
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, *src, nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	g := &generator{fset: fset, buf: new(bytes.Buffer)}
+	g.generate(file, *pkg)
+
+	b, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		log.Fatalf("%v\n%s", err, g.buf.String())
+	}
+
+	if err = ioutil.WriteFile(*out, b, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type generator struct {
+	fset *token.FileSet
+	buf  *bytes.Buffer
+	body bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.body, format, args...)
+}
+
+func (g *generator) generate(file *ast.File, pkg string) {
+	var composites []*ast.TypeSpec
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			typ := spec.(*ast.TypeSpec)
+			iface, ok := typ.Type.(*ast.InterfaceType)
+			if !ok {
+				continue
+			}
+
+			if isComposite(iface) {
+				composites = append(composites, typ)
+				continue
+			}
+
+			g.generateFake(typ.Name.Name, iface)
+		}
+	}
+
+	for _, typ := range composites {
+		g.generateComposite(typ.Name.Name, typ.Type.(*ast.InterfaceType))
+	}
+
+	body := g.body.String()
+
+	fmt.Fprintf(g.buf, "// Code generated by fakegen; DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkg)
+	for _, imp := range []string{"context", "time"} {
+		if strings.Contains(body, imp+".") {
+			fmt.Fprintf(g.buf, "%q\n", imp)
+		}
+	}
+	fmt.Fprintf(g.buf, "\n%q\n)\n", lensesPkgPath)
+	g.buf.WriteString(body)
+}
+
+// isComposite reports whether the interface only embeds other interfaces, i.e the `lenses.API`.
+func isComposite(iface *ast.InterfaceType) bool {
+	for _, m := range iface.Methods.List {
+		if len(m.Names) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (g *generator) generateComposite(name string, iface *ast.InterfaceType) {
+	g.printf("\n// Fake%s is a fake implementation of the `lenses.%s`, it's composed by the domain fakes,\n", name, name)
+	g.printf("// the behavior of each method can be set through the function fields of the embedded fakes.\n")
+	g.printf("type Fake%s struct {\n", name)
+	for _, m := range iface.Methods.List {
+		g.printf("Fake%s\n", m.Type.(*ast.Ident).Name)
+	}
+	g.printf("}\n\nvar _ lenses.%s = (*Fake%s)(nil)\n", name, name)
+}
+
+type method struct {
+	name    string
+	params  []param
+	results []param
+}
+
+type param struct {
+	name     string
+	typ      string
+	variadic bool
+}
+
+func (g *generator) generateFake(name string, iface *ast.InterfaceType) {
+	var methods []method
+	names := make(map[string]bool)
+
+	for _, field := range iface.Methods.List {
+		fn := field.Type.(*ast.FuncType)
+		m := method{name: field.Names[0].Name}
+		m.params = g.fields(fn.Params, "p")
+		m.results = g.fields(fn.Results, "r")
+		methods = append(methods, m)
+		names[m.name] = true
+	}
+
+	fakeName := "Fake" + name
+
+	g.printf("\n// %s is a fake implementation of the `lenses.%s`.\n", fakeName, name)
+	g.printf("// Each method calls its function field, i.e the `%s`,\n", funcFieldName(methods[0], names))
+	g.printf("// if that field is nil then it returns the zero values, the error result is the `ErrNotImplemented`.\n")
+	g.printf("// The methods that have a \"Context\" pair call that pair with the context.Background.\n")
+	g.printf("type %s struct {\n", fakeName)
+	for _, m := range methods {
+		if hasContextPair(m, names) {
+			continue
+		}
+
+		g.printf("%sFunc func(%s) (%s)\n", m.name, joinParams(m.params, true), joinParams(m.results, false))
+	}
+	g.printf("}\n\nvar _ lenses.%s = (*%s)(nil)\n", name, fakeName)
+
+	for _, m := range methods {
+		g.printf("\n// %s implements the `lenses.%s`.\n", m.name, name)
+		g.printf("func (f *%s) %s(%s) (%s) {\n", fakeName, m.name, joinParams(m.params, true), joinParams(m.results, true))
+
+		if hasContextPair(m, names) {
+			args := append([]string{"context.Background()"}, callArgs(m.params)...)
+			g.printf("return f.%s%s(%s)\n}\n", m.name, contextSuffix, strings.Join(args, ", "))
+			continue
+		}
+
+		g.printf("if f.%sFunc == nil {\n", m.name)
+		for _, r := range m.results {
+			if r.typ == "error" {
+				g.printf("%s = ErrNotImplemented\n", r.name)
+			}
+		}
+		g.printf("return\n}\n\n")
+		g.printf("return f.%sFunc(%s)\n}\n", m.name, strings.Join(callArgs(m.params), ", "))
+	}
+}
+
+// hasContextPair reports whether the method "m" has a "Context" pair which accepts a context.Context, i.e `GetTopicsContext`.
+func hasContextPair(m method, names map[string]bool) bool {
+	return !strings.HasSuffix(m.name, contextSuffix) && names[m.name+contextSuffix]
+}
+
+func funcFieldName(m method, names map[string]bool) string {
+	if hasContextPair(m, names) {
+		return m.name + contextSuffix + "Func"
+	}
+
+	return m.name + "Func"
+}
+
+// fields returns the parameters or the results of a method,
+// the unnamed ones are named by the "prefix" and their index, the unnamed error is named "err".
+func (g *generator) fields(list *ast.FieldList, prefix string) (params []param) {
+	if list == nil {
+		return
+	}
+
+	for _, field := range list.List {
+		p := param{typ: g.expr(field.Type)}
+		if ellipsis, ok := field.Type.(*ast.Ellipsis); ok {
+			p.variadic = true
+			p.typ = g.expr(ellipsis.Elt)
+		}
+
+		if len(field.Names) == 0 {
+			p.name = fmt.Sprintf("%s%d", prefix, len(params))
+			if p.typ == "error" {
+				p.name = "err"
+			}
+
+			params = append(params, p)
+			continue
+		}
+
+		for _, ident := range field.Names {
+			p.name = ident.Name
+			params = append(params, p)
+		}
+	}
+
+	return
+}
+
+// expr prints the type expression, the exported identifiers of the lenses package are qualified, i.e `lenses.Topic`.
+func (g *generator) expr(e ast.Expr) string {
+	ast.Inspect(e, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			return false // already qualified, i.e context.Context.
+		case *ast.Ident:
+			if ast.IsExported(n.Name) && !strings.HasPrefix(n.Name, lensesPkgName+".") {
+				n.Name = lensesPkgName + "." + n.Name
+			}
+		}
+
+		return true
+	})
+
+	var b bytes.Buffer
+	printer.Fprint(&b, g.fset, e)
+	return b.String()
+}
+
+func joinParams(params []param, withNames bool) string {
+	s := make([]string, len(params))
+	for i, p := range params {
+		typ := p.typ
+		if p.variadic {
+			typ = "..." + typ
+		}
+
+		if withNames {
+			s[i] = p.name + " " + typ
+		} else {
+			s[i] = typ
+		}
+	}
+
+	return strings.Join(s, ", ")
+}
+
+func callArgs(params []param) []string {
+	args := make([]string, 0, len(params))
+	for _, p := range params {
+		arg := p.name
+		if p.variadic {
+			arg += "..."
+		}
+		args = append(args, arg)
+	}
+
+	return args
+}
@@ -0,0 +1,276 @@
+package lensestest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/landoop/lenses-go"
+)
+
+// SetStreamDelay sets the time to wait before sending each record of an LSQL query,
+// it's useful to test cancellations and deadlines against long running queries.
+func (s *Server) SetStreamDelay(d time.Duration) {
+	s.mu.Lock()
+	s.streamDelay = d
+	s.mu.Unlock()
+}
+
+type runningQuery struct {
+	lenses.LSQLRunningQuery
+	stop chan struct{}
+}
+
+var (
+	lsqlSelectRegexp = regexp.MustCompile("(?is)^\\s*SELECT\\s+.+?\\s+FROM\\s+(`[^`]+`|[\\w.\\-]+)")
+	lsqlLimitRegexp  = regexp.MustCompile(`(?i)\bLIMIT\s+(\d+)`)
+	// lsqlOffsetRegexp matches the predicates that the `lenses.LSQLCheckpoint` adds to resume a query.
+	lsqlOffsetRegexp = regexp.MustCompile(`(?i)\b_partition\s*=\s*(\d+)\s+AND\s+_offset\s*>\s*(\d+)`)
+)
+
+// parseLSQLOffsets returns the last read offset by partition of a query that is resumed by a `lenses.LSQLCheckpoint`,
+// the records of those partitions up to these offsets are skipped, the rest of the partitions are read from their start.
+func parseLSQLOffsets(sql string) map[int]int64 {
+	matches := lsqlOffsetRegexp.FindAllStringSubmatch(sql, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	offsets := make(map[int]int64, len(matches))
+	for _, m := range matches {
+		partition, _ := strconv.Atoi(m[1])
+		offset, _ := strconv.ParseInt(m[2], 10, 64)
+		offsets[partition] = offset
+	}
+
+	return offsets
+}
+
+// parseLSQL returns the topic and the limit of a simple "SELECT ... FROM topic ... [LIMIT n]" query,
+// the limit is -1 if missing.
+func parseLSQL(sql string) (topic string, limit int, v lenses.LSQLValidation) {
+	limit = -1
+
+	matches := lsqlSelectRegexp.FindStringSubmatch(sql)
+	if len(matches) < 2 {
+		v.Line, v.Column = errorPosition(sql)
+		v.Message = fmt.Sprintf("Invalid syntax.Encountered %q at line %d, column %d.\nWas expecting one of:\n    \"SELECT\" ... ",
+			firstWord(sql), v.Line, v.Column)
+		return
+	}
+
+	topic = strings.Trim(matches[1], "`")
+	if m := lsqlLimitRegexp.FindStringSubmatch(sql); len(m) == 2 {
+		limit, _ = strconv.Atoi(m[1])
+	}
+
+	v.IsValid = true
+	return
+}
+
+// errorPosition returns the line and the column of the first non-space character of the "sql".
+func errorPosition(sql string) (line, column int) {
+	line, column = 1, 1
+	for _, ch := range sql {
+		switch ch {
+		case '\n':
+			line++
+			column = 1
+		case ' ', '\t', '\r':
+			column++
+		default:
+			return
+		}
+	}
+
+	return
+}
+
+func firstWord(sql string) string {
+	if fields := strings.Fields(sql); len(fields) > 0 {
+		return fields[0]
+	}
+
+	return "<EOF>"
+}
+
+// sql serves the "api/sql/validation", "api/sql/data" and "api/sql/queries".
+func (s *Server) sql(w http.ResponseWriter, r *http.Request, rest []string) {
+	switch {
+	case len(rest) == 1 && rest[0] == "validation" && r.Method == http.MethodGet:
+		_, _, v := parseLSQL(r.URL.Query().Get("sql"))
+		writeJSON(w, http.StatusOK, v)
+	case len(rest) == 1 && rest[0] == "data" && r.Method == http.MethodGet:
+		s.lsqlStream(w, r)
+	case len(rest) == 1 && rest[0] == "queries" && r.Method == http.MethodGet:
+		s.mu.Lock()
+		queries := make([]lenses.LSQLRunningQuery, 0, len(s.queries))
+		for _, q := range s.queries {
+			queries = append(queries, q.LSQLRunningQuery)
+		}
+		s.mu.Unlock()
+
+		sort.Slice(queries, func(i, j int) bool { return queries[i].ID < queries[j].ID })
+		writeJSON(w, http.StatusOK, queries)
+	case len(rest) == 2 && rest[0] == "queries" && r.Method == http.MethodDelete:
+		id, err := strconv.ParseInt(rest[1], 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid query id")
+			return
+		}
+
+		s.mu.Lock()
+		q, ok := s.queries[id]
+		if ok {
+			delete(s.queries, id)
+			close(q.stop)
+		}
+		s.mu.Unlock()
+
+		writeJSON(w, http.StatusOK, ok)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// lsqlStream streams the query's results using the "data:<type><payload>" framing of the LSQL SSE endpoint.
+func (s *Server) lsqlStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	query := r.URL.Query()
+	sql := query.Get("sql")
+	withOffsets := query.Get("offsets") == "true"
+	withStats := query.Get("stats") != ""
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(payloadType byte, v interface{}) {
+		b, _ := json.Marshal(v)
+		fmt.Fprintf(w, "data:%c%s\n", payloadType, b)
+		flusher.Flush()
+	}
+
+	fmt.Fprint(w, "data:0\n") // heartbeat.
+	flusher.Flush()
+
+	topicName, limit, v := parseLSQL(sql)
+	if !v.IsValid {
+		send('3', lenses.LSQLError{FromLine: v.Line, ToLine: v.Line, FromColumn: v.Column, ToColumn: v.Column, Message: v.Message})
+		return
+	}
+
+	s.mu.Lock()
+	_, exists := s.topics[topicName]
+	records := append([]lenses.LSQLRecord(nil), s.records[topicName]...)
+	delay := s.streamDelay
+
+	s.queryID++
+	q := &runningQuery{
+		LSQLRunningQuery: lenses.LSQLRunningQuery{
+			ID:        s.queryID,
+			SQL:       sql,
+			User:      s.User,
+			Timestamp: time.Now().Unix() * 1000,
+		},
+		stop: make(chan struct{}),
+	}
+	s.queries[q.ID] = q
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.queries, q.ID)
+		s.mu.Unlock()
+	}()
+
+	if !exists {
+		send('3', lenses.LSQLError{FromLine: 1, ToLine: 1, Message: fmt.Sprintf("Topic '%s' does not exist", topicName)})
+		return
+	}
+
+	stop := lenses.LSQLStop{IsTimeRemaining: true, RecordsLimit: limit}
+	offsets := make(map[int]*lenses.LSQLOffset)
+
+	if skip := parseLSQLOffsets(sql); skip != nil {
+		var unread []lenses.LSQLRecord
+		for _, record := range records {
+			if last, ok := skip[record.Partition]; !ok || record.Offset64() > last {
+				unread = append(unread, record)
+			}
+		}
+		records = unread
+	}
+
+	for _, record := range records {
+		if limit >= 0 && stop.TotalRecords >= limit {
+			break
+		}
+
+		var wait <-chan time.Time
+		if delay > 0 {
+			wait = time.After(delay)
+		} else {
+			ready := make(chan time.Time)
+			close(ready)
+			wait = ready
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.closed:
+			return
+		case <-q.stop:
+			stop.IsStopped = true
+		case <-wait:
+		}
+
+		if stop.IsStopped {
+			break
+		}
+
+		send('1', record)
+
+		size := int64(len(record.Key) + len(record.Value))
+		stop.TotalRecords++
+		stop.Size += size
+		stop.TotalSizeRead += size
+
+		if o, ok := offsets[record.Partition]; ok {
+			o.Max = record.Offset64()
+		} else {
+			offsets[record.Partition] = &lenses.LSQLOffset{Partition: record.Partition, Min: record.Offset64(), Max: record.Offset64()}
+		}
+	}
+
+	stop.IsTopicEnd = !stop.IsStopped && stop.TotalRecords == len(records)
+
+	if withStats {
+		send('4', lenses.LSQLStats{
+			TotalRecords: stop.TotalRecords,
+			RecordsLimit: limit,
+			TotalBytes:   stop.TotalSizeRead,
+			CurrentSize:  stop.Size,
+		})
+	}
+
+	if withOffsets {
+		stop.Offsets = make([]lenses.LSQLOffset, 0, len(offsets))
+		for _, o := range offsets {
+			stop.Offsets = append(stop.Offsets, *o)
+		}
+		sort.Slice(stop.Offsets, func(i, j int) bool { return stop.Offsets[i].Partition < stop.Offsets[j].Partition })
+	}
+
+	send('2', stop)
+}
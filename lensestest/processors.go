@@ -0,0 +1,193 @@
+package lensestest
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/landoop/lenses-go"
+)
+
+const (
+	processorStateRunning = "RUNNING"
+	processorStatePaused  = "PAUSED"
+)
+
+var processorToTopicRegexp = regexp.MustCompile("(?i)INSERT\\s+INTO\\s+(`[^`]+`|[\\w.\\-]+)")
+
+// newProcessorID returns the identifier of a new processor based on the execution mode,
+// it's the "pipeline_N" on IN_PROC and CONNECT and the "cluster.namespace.name" on KUBERNETES.
+func (s *Server) newProcessorID(payload lenses.CreateProcessorPayload) (string, error) {
+	if s.executionMode == lenses.ExecutionModeKubernetes {
+		if payload.ClusterName == "" || payload.Namespace == "" {
+			return "", fmt.Errorf("clusterName and namespace are required in KUBERNETES execution mode")
+		}
+
+		return fmt.Sprintf("%s.%s.%s", payload.ClusterName, payload.Namespace, payload.Name), nil
+	}
+
+	s.processorID++
+	return fmt.Sprintf("%s_%d", payload.Pipeline, s.processorID), nil
+}
+
+// processorsAPI serves the "api/streams" and "api/streams/{id}[/pause|/resume|/scale/{runners}]".
+func (s *Server) processorsAPI(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			s.getProcessors(w)
+		case http.MethodPost:
+			s.createProcessor(w, r)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := rest[0]
+	processor, ok := s.processors[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Processor '%s' does not exist", id))
+		return
+	}
+
+	switch {
+	case len(rest) == 1 && r.Method == http.MethodDelete:
+		delete(s.processors, id)
+	case len(rest) == 2 && rest[1] == "pause" && r.Method == http.MethodPut:
+		processor.DeploymentState = processorStatePaused
+		processor.StopTimestamp = time.Now().Unix() * 1000
+	case len(rest) == 2 && rest[1] == "resume" && r.Method == http.MethodPut:
+		processor.DeploymentState = processorStateRunning
+		processor.StopTimestamp = 0
+	case len(rest) == 3 && rest[1] == "scale" && r.Method == http.MethodPut:
+		runners, err := strconv.Atoi(rest[2])
+		if err != nil || runners <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid number of runners")
+			return
+		}
+
+		processor.Runners = runners
+		processor.RunnerState = runnerStates(processor.ID, runners, processor.DeploymentState)
+	default:
+		methodNotAllowed(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) getProcessors(w http.ResponseWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := lenses.ProcessorsResult{
+		Targets: make([]lenses.ProcessorTarget, 0),
+		Streams: make([]lenses.ProcessorStream, 0, len(s.processors)),
+	}
+
+	namespaces := make(map[string][]string)
+	for _, processor := range s.processors {
+		result.Streams = append(result.Streams, *processor)
+		if s.executionMode == lenses.ExecutionModeKubernetes {
+			namespaces[processor.ClusterName] = appendUnique(namespaces[processor.ClusterName], processor.Namespace)
+		}
+	}
+
+	sort.Slice(result.Streams, func(i, j int) bool { return result.Streams[i].ID < result.Streams[j].ID })
+
+	for cluster, ns := range namespaces {
+		sort.Strings(ns)
+		result.Targets = append(result.Targets, lenses.ProcessorTarget{Cluster: cluster, Namespaces: ns})
+	}
+	sort.Slice(result.Targets, func(i, j int) bool { return result.Targets[i].Cluster < result.Targets[j].Cluster })
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) createProcessor(w http.ResponseWriter, r *http.Request) {
+	var payload lenses.CreateProcessorPayload
+	if !readJSON(w, r, &payload) {
+		return
+	}
+
+	if payload.Name == "" || payload.SQL == "" {
+		writeError(w, http.StatusBadRequest, "name and sql are required")
+		return
+	}
+
+	if payload.Runners <= 0 {
+		payload.Runners = 1
+	}
+
+	if payload.Pipeline == "" {
+		payload.Pipeline = payload.Name
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, processor := range s.processors {
+		if processor.Name == payload.Name && processor.ClusterName == payload.ClusterName && processor.Namespace == payload.Namespace {
+			writeError(w, http.StatusConflict, fmt.Sprintf("Processor '%s' already exists", payload.Name))
+			return
+		}
+	}
+
+	id, err := s.newProcessorID(payload)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var toTopic string
+	if m := processorToTopicRegexp.FindStringSubmatch(payload.SQL); len(m) == 2 {
+		toTopic = strings.Trim(m[1], "`")
+	}
+
+	now := time.Now().Unix() * 1000
+	s.processors[id] = &lenses.ProcessorStream{
+		ID:                id,
+		Name:              payload.Name,
+		ClusterName:       payload.ClusterName,
+		User:              s.User,
+		Namespace:         payload.Namespace,
+		SQL:               payload.SQL,
+		Runners:           payload.Runners,
+		DeploymentState:   processorStateRunning,
+		TopicValueDecoder: "JSON",
+		Pipeline:          payload.Pipeline,
+		StartTimestamp:    now,
+		ToTopic:           toTopic,
+		RunnerState:       runnerStates(id, payload.Runners, processorStateRunning),
+	}
+
+	writeJSON(w, http.StatusCreated, id)
+}
+
+func runnerStates(processorID string, runners int, state string) map[string]lenses.ProcessorRunnerState {
+	states := make(map[string]lenses.ProcessorRunnerState, runners)
+	for i := 0; i < runners; i++ {
+		id := fmt.Sprintf("%s-%d", processorID, i)
+		states[id] = lenses.ProcessorRunnerState{ID: id, Worker: "lensestest", State: state}
+	}
+
+	return states
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
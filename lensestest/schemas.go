@@ -0,0 +1,235 @@
+package lensestest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/landoop/lenses-go"
+)
+
+// schemaRegistryAPI serves the "api/proxy-sr/subjects[/...]", "api/proxy-sr/schemas/ids/{id}"
+// and "api/proxy-sr/config[/{subject}]", the errors are in the form of the Schema Registry ones.
+func (s *Server) schemaRegistryAPI(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) == 0 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	switch rest[0] {
+	case "subjects":
+		s.subjectsAPI(w, r, rest[1:])
+	case "schemas":
+		if len(rest) != 3 || rest[1] != "ids" || r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+
+		id, err := strconv.Atoi(rest[2])
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid schema id")
+			return
+		}
+
+		s.mu.Lock()
+		avroSchema, ok := s.schemaIDs[id]
+		s.mu.Unlock()
+
+		if !ok {
+			writeError(w, http.StatusNotFound, "Schema not found")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]string{"schema": avroSchema})
+	case "config":
+		s.compatibilityAPI(w, r, rest[1:])
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) subjectsAPI(w http.ResponseWriter, r *http.Request, rest []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(rest) == 0 {
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+
+		subjects := make([]string, 0, len(s.subjects))
+		for subject := range s.subjects {
+			subjects = append(subjects, subject)
+		}
+		sort.Strings(subjects)
+
+		writeJSON(w, http.StatusOK, subjects)
+		return
+	}
+
+	subject := rest[0]
+	schemas := s.subjects[subject]
+
+	// POST /subjects/{subject}/versions registers a new subject as well.
+	if len(rest) == 2 && rest[1] == "versions" && r.Method == http.MethodPost {
+		var payload = struct {
+			Schema string `json:"schema"`
+		}{}
+
+		if !readJSON(w, r, &payload) {
+			return
+		}
+
+		if payload.Schema == "" {
+			writeError(w, http.StatusUnprocessableEntity, "Schema is required")
+			return
+		}
+
+		for _, schema := range schemas {
+			if schema.AvroSchema == payload.Schema {
+				writeJSON(w, http.StatusOK, map[string]int{"id": schema.ID})
+				return
+			}
+		}
+
+		id := s.lookupSchemaID(payload.Schema)
+		version := 1
+		if n := len(schemas); n > 0 {
+			version = schemas[n-1].Version + 1
+		}
+
+		s.subjects[subject] = append(schemas, lenses.Schema{ID: id, Name: subject, Version: version, AvroSchema: payload.Schema})
+		writeJSON(w, http.StatusOK, map[string]int{"id": id})
+		return
+	}
+
+	if len(schemas) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Subject '%s' not found.", subject))
+		return
+	}
+
+	switch {
+	case len(rest) == 1 && r.Method == http.MethodDelete:
+		versions := make([]int, len(schemas))
+		for i, schema := range schemas {
+			versions[i] = schema.Version
+		}
+		delete(s.subjects, subject)
+
+		writeJSON(w, http.StatusOK, versions)
+	case len(rest) == 2 && rest[1] == "versions" && r.Method == http.MethodGet:
+		versions := make([]int, len(schemas))
+		for i, schema := range schemas {
+			versions[i] = schema.Version
+		}
+
+		writeJSON(w, http.StatusOK, versions)
+	case len(rest) == 3 && rest[1] == "versions":
+		idx := len(schemas) - 1
+		if rest[2] != lenses.SchemaLatestVersion {
+			idx = -1
+			version, _ := strconv.Atoi(rest[2])
+			for i, schema := range schemas {
+				if schema.Version == version {
+					idx = i
+					break
+				}
+			}
+		}
+
+		if idx == -1 {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Version '%s' not found.", rest[2]))
+			return
+		}
+
+		schema := schemas[idx]
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, schema)
+		case http.MethodDelete:
+			s.subjects[subject] = append(schemas[:idx:idx], schemas[idx+1:]...)
+			if len(s.subjects[subject]) == 0 {
+				delete(s.subjects, subject)
+			}
+
+			writeJSON(w, http.StatusOK, schema.Version)
+		default:
+			methodNotAllowed(w)
+		}
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// lookupSchemaID returns the id of an already registered schema, under any subject, or a new one.
+func (s *Server) lookupSchemaID(avroSchema string) int {
+	for id, existing := range s.schemaIDs {
+		if existing == avroSchema {
+			return id
+		}
+	}
+
+	s.schemaID++
+	s.schemaIDs[s.schemaID] = avroSchema
+	return s.schemaID
+}
+
+func (s *Server) compatibilityAPI(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) > 1 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		level := s.compatibility
+		if len(rest) == 1 {
+			subjectLevel, ok := s.subjectCompatibility[rest[0]]
+			if !ok {
+				writeError(w, http.StatusNotFound, fmt.Sprintf("Subject '%s' not found.", rest[0]))
+				return
+			}
+			level = subjectLevel
+		}
+
+		writeJSON(w, http.StatusOK, map[string]string{"compatibilityLevel": string(level)})
+	case http.MethodPut:
+		var payload = struct {
+			Compatibility lenses.CompatibilityLevel `json:"compatibility"`
+		}{}
+
+		if !readJSON(w, r, &payload) {
+			return
+		}
+
+		if !isValidCompatibilityLevel(payload.Compatibility) {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid compatibility level")
+			return
+		}
+
+		if len(rest) == 1 {
+			s.subjectCompatibility[rest[0]] = payload.Compatibility
+		} else {
+			s.compatibility = payload.Compatibility
+		}
+
+		writeJSON(w, http.StatusOK, map[string]string{"compatibility": string(payload.Compatibility)})
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func isValidCompatibilityLevel(level lenses.CompatibilityLevel) bool {
+	for _, valid := range lenses.ValidCompatibilityLevels {
+		if level == valid {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,500 @@
+// Package lensestest provides an in-memory, fake, Lenses server for testing code that is built on top of the `lenses.Client`.
+//
+// The server is based on the `net/http/httptest` package, it keeps its state in memory
+// and it implements the login, topics, processors, connectors (through the "api/proxy-connect"),
+// schemas (through the "api/proxy-sr"), ACLs, quotas, alerts and the LSQL and alerts streams.
+//
+// Usage:
+// srv := lensestest.NewServer()
+// defer srv.Close()
+//
+// client, err := lenses.OpenConnection(srv.Configuration())
+// [...]
+//
+// Failures can be simulated using the `InjectFault`.
+package lensestest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/landoop/lenses-go"
+)
+
+const (
+	// DefaultUser is the user that the server accepts on login, see `Server#User`.
+	DefaultUser = "admin"
+	// DefaultPassword is the password that the server accepts on login, see `Server#Password`.
+	DefaultPassword = "admin"
+	// DefaultConnectCluster is the name of the connect cluster that the server starts with.
+	DefaultConnectCluster = "dev"
+)
+
+const tokenHeaderKey = "X-Kafka-Lenses-Token"
+
+// Server is the fake Lenses server.
+// It's safe for concurrent use, the state can be modified by the API calls
+// and by the methods of the `Server` itself, i.e `AddRecords`.
+type Server struct {
+	*httptest.Server
+
+	// User and Password are the credentials that the login accepts.
+	// Defaults to the `DefaultUser` and `DefaultPassword`.
+	User     string
+	Password string
+
+	mu sync.Mutex
+	// closed is closed on `Close` in order to stop the streams.
+	closed chan struct{}
+
+	tokens map[string]struct{}
+	faults []*Fault
+
+	executionMode   lenses.ExecutionMode
+	connectClusters []string
+
+	topics  map[string]*lenses.Topic
+	records map[string][]lenses.LSQLRecord
+	queries map[int64]*runningQuery
+	queryID int64
+
+	streamDelay time.Duration
+
+	processors  map[string]*lenses.ProcessorStream
+	processorID int
+
+	connectors map[string]map[string]*connector // cluster -> name -> connector.
+
+	subjects             map[string][]lenses.Schema
+	schemaIDs            map[int]string
+	schemaID             int
+	compatibility        lenses.CompatibilityLevel
+	subjectCompatibility map[string]lenses.CompatibilityLevel
+
+	acls   []lenses.ACL
+	quotas []lenses.Quota
+
+	alertSettings   []lenses.AlertSetting
+	alerts          []lenses.Alert
+	alertListeners  map[chan lenses.Alert]struct{}
+	conditionsCount int
+}
+
+// NewServer starts and returns a new fake Lenses server, the caller should call the `Close` when finished.
+//
+// The server starts with the IN_PROC execution mode, the `DefaultConnectCluster` and a couple of alert settings.
+func NewServer() *Server {
+	s := newServer()
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// NewTLSServer same as `NewServer` but it starts the server with TLS,
+// the `Server#Certificate` can be used to trust the server, i.e as a `lenses.Configuration#CAFile`.
+func NewTLSServer() *Server {
+	s := newServer()
+	s.Server = httptest.NewTLSServer(s)
+	return s
+}
+
+func newServer() *Server {
+	return &Server{
+		User:                 DefaultUser,
+		Password:             DefaultPassword,
+		closed:               make(chan struct{}),
+		tokens:               make(map[string]struct{}),
+		executionMode:        lenses.ExecutionModeInProcess,
+		connectClusters:      []string{DefaultConnectCluster},
+		topics:               make(map[string]*lenses.Topic),
+		records:              make(map[string][]lenses.LSQLRecord),
+		queries:              make(map[int64]*runningQuery),
+		processors:           make(map[string]*lenses.ProcessorStream),
+		connectors:           map[string]map[string]*connector{DefaultConnectCluster: {}},
+		subjects:             make(map[string][]lenses.Schema),
+		schemaIDs:            make(map[int]string),
+		compatibility:        lenses.CompatibilityLevelBackward,
+		subjectCompatibility: make(map[string]lenses.CompatibilityLevel),
+		alertSettings:        defaultAlertSettings(),
+		alertListeners:       make(map[chan lenses.Alert]struct{}),
+	}
+}
+
+// Close stops the streams and shuts down the server.
+func (s *Server) Close() {
+	s.mu.Lock()
+	select {
+	case <-s.closed:
+	default:
+		close(s.closed)
+	}
+	s.mu.Unlock()
+
+	s.Server.Close()
+}
+
+// Configuration returns a `lenses.Configuration` which can be used to connect to this server.
+func (s *Server) Configuration() lenses.Configuration {
+	return lenses.Configuration{
+		Host:     s.URL,
+		User:     s.User,
+		Password: s.Password,
+	}
+}
+
+// IssueToken generates and returns a new valid token, it can be used
+// to connect without login, i.e `lenses.Configuration#Token`.
+func (s *Server) IssueToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	token := hex.EncodeToString(b)
+
+	s.mu.Lock()
+	s.tokens[token] = struct{}{}
+	s.mu.Unlock()
+
+	return token
+}
+
+// ExpireTokens invalidates all the issued tokens, the next calls will fail with 401 until a new login.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	s.tokens = make(map[string]struct{})
+	s.mu.Unlock()
+}
+
+// SetExecutionMode sets the execution mode that the "api/config" reports,
+// the processors are identified based on that mode.
+func (s *Server) SetExecutionMode(mode lenses.ExecutionMode) {
+	s.mu.Lock()
+	s.executionMode = mode
+	s.mu.Unlock()
+}
+
+// AddConnectCluster registers a new connect cluster, if not already exists.
+func (s *Server) AddConnectCluster(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.connectors[name]; ok {
+		return
+	}
+
+	s.connectClusters = append(s.connectClusters, name)
+	s.connectors[name] = make(map[string]*connector)
+}
+
+// Fault describes a failure that the server simulates instead of the normal response.
+type Fault struct {
+	// Method is the HTTP method that the fault applies to, empty matches all methods.
+	Method string
+	// Path is the prefix of the request URL's path that the fault applies to, i.e "/api/topics",
+	// empty matches all paths.
+	Path string
+
+	// Delay is the time to wait before responding,
+	// if `StatusCode` is zero and `CloseConnection` is false then the request is served normally after that.
+	Delay time.Duration
+	// StatusCode is the status code to respond with, i.e 503.
+	StatusCode int
+	// Body is the response body, used when `StatusCode` is not zero.
+	Body string
+	// CloseConnection closes the connection without a response.
+	CloseConnection bool
+
+	// Times is the number of the requests that the fault applies to, zero means for ever.
+	Times int
+}
+
+// InjectFault registers a new fault, the faults are matched in order of registration.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	s.faults = append(s.faults, &f)
+	s.mu.Unlock()
+}
+
+// ClearFaults removes all the registered faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	s.faults = nil
+	s.mu.Unlock()
+}
+
+func (s *Server) matchFault(r *http.Request) (Fault, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+
+		if !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+
+		return *f, true
+	}
+
+	return Fault{}, false
+}
+
+// applyFault reports whether the request was handled by a fault.
+func (s *Server) applyFault(w http.ResponseWriter, r *http.Request) bool {
+	f, ok := s.matchFault(r)
+	if !ok {
+		return false
+	}
+
+	if f.Delay > 0 {
+		select {
+		case <-time.After(f.Delay):
+		case <-r.Context().Done():
+			return true
+		}
+	}
+
+	if f.CloseConnection {
+		if hj, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hj.Hijack(); err == nil {
+				conn.Close()
+				return true
+			}
+		}
+
+		panic(http.ErrAbortHandler)
+	}
+
+	if f.StatusCode == 0 {
+		return false
+	}
+
+	w.WriteHeader(f.StatusCode)
+	fmt.Fprint(w, f.Body)
+	return true
+}
+
+// ServeHTTP implements the `http.Handler`, it is the fake Lenses API.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.applyFault(w, r) {
+		return
+	}
+
+	path := strings.Trim(r.URL.Path, "/")
+	switch path {
+	case "api/login":
+		s.login(w, r)
+		return
+	case "api/logout":
+		s.logout(w, r)
+		return
+	}
+
+	if !s.isAuthorized(r) {
+		writeError(w, http.StatusUnauthorized, "invalid or expired token")
+		return
+	}
+
+	segments := strings.Split(path, "/")
+	if len(segments) < 2 || segments[0] != "api" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	// the rest of the path after the resource, i.e ["reddit_posts"] on "api/topics/reddit_posts".
+	rest := segments[2:]
+
+	switch segments[1] {
+	case "license":
+		s.license(w, r)
+	case "config":
+		s.config(w, r)
+	case "sql":
+		s.sql(w, r, rest)
+	case "topics":
+		s.topicsAPI(w, r, rest)
+	case "streams":
+		s.processorsAPI(w, r, rest)
+	case "proxy-connect":
+		s.connectAPI(w, r, rest)
+	case "proxy-sr":
+		s.schemaRegistryAPI(w, r, rest)
+	case "acl":
+		s.aclAPI(w, r)
+	case "quotas":
+		s.quotasAPI(w, r, rest)
+	case "alerts":
+		s.alertsAPI(w, r, rest)
+	case "sse":
+		if len(rest) == 1 && rest[0] == "alerts" {
+			s.alertsStream(w, r)
+			return
+		}
+		writeError(w, http.StatusNotFound, "not found")
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) isAuthorized(r *http.Request) bool {
+	token := r.Header.Get(tokenHeaderKey)
+	if token == "" {
+		return false
+	}
+
+	s.mu.Lock()
+	_, ok := s.tokens[token]
+	s.mu.Unlock()
+	return ok
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var credentials = struct {
+		User     string `json:"user"`
+		Password string `json:"password"`
+	}{}
+
+	if !readJSON(w, r, &credentials) {
+		return
+	}
+
+	if credentials.User != s.User || credentials.Password != s.Password {
+		writeError(w, http.StatusUnauthorized, "invalid credentials")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"token":   s.IssueToken(),
+		"user": lenses.User{
+			ID:    credentials.User,
+			Name:  credentials.User,
+			Email: credentials.User + "@lensestest",
+			Roles: []string{"admin", "read", "write", "nodata"},
+		},
+		"schemaRegistryDelete": true,
+	})
+}
+
+func (s *Server) logout(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+
+	s.mu.Lock()
+	_, ok := s.tokens[token]
+	delete(s.tokens, token)
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid or expired token")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) license(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, lenses.LicenseInfo{
+		ClientID:    "lensestest",
+		IsRespected: true,
+		MaxBrokers:  10,
+		Expiry:      time.Now().AddDate(1, 0, 0).Unix() * 1000,
+	})
+}
+
+func (s *Server) config(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	s.mu.Lock()
+	clusters := make([]lenses.ConnectCluster, len(s.connectClusters))
+	for i, name := range s.connectClusters {
+		clusters[i] = lenses.ConnectCluster{
+			Name:     name,
+			URL:      "http://" + name + ":8083",
+			Statuses: "connect-statuses",
+			Config:   "connect-configs",
+			Offsets:  "connect-offsets",
+		}
+	}
+
+	config := map[string]interface{}{
+		"lenses.sql.execution.mode": string(s.executionMode),
+		"lenses.connect.clusters":   clusters,
+		"lenses.version":            lenses.Version,
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, config)
+}
+
+// helpers.
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write(b)
+}
+
+// writeError writes the error in the form that Lenses and the proxied services respond with.
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	b, _ := json.Marshal(map[string]interface{}{
+		"success":    false,
+		"error_code": statusCode,
+		"message":    message,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write(b)
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, outPtr interface{}) bool {
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	if err = json.Unmarshal(b, outPtr); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json: "+err.Error())
+		return false
+	}
+
+	return true
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+}
@@ -0,0 +1,292 @@
+package lensestest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/landoop/lenses-go"
+	"github.com/landoop/lenses-go/lensestest"
+)
+
+func openTestConnection(t *testing.T, srv *lensestest.Server, options ...lenses.ConnectionOption) *lenses.Client {
+	client, err := lenses.OpenConnection(srv.Configuration(), options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+func TestServerResources(t *testing.T) {
+	srv := lensestest.NewServer()
+	defer srv.Close()
+
+	client := openTestConnection(t, srv)
+
+	if err := client.CreateTopic("reddit_posts", 1, 3, lenses.KV{"cleanup.policy": "compact"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.CreateTopic("reddit_posts", 1, 3, nil); !lensesErrorIs(err, http.StatusConflict) {
+		t.Fatalf("expected a conflict error but got: %v", err)
+	}
+
+	topic, err := client.GetTopic("reddit_posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := 3, topic.Partitions; expected != got {
+		t.Fatalf("expected %d partitions but got %d", expected, got)
+	}
+
+	if err = client.DeleteTopic("reddit_posts"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = client.GetTopic("reddit_posts"); !lensesErrorIs(err, http.StatusNotFound) {
+		t.Fatalf("expected a not found error but got: %v", err)
+	}
+
+	// connectors.
+	if _, err = client.CreateConnector(lensestest.DefaultConnectCluster, "file-sink", lenses.ConnectorConfig{
+		"connector.class": "org.apache.kafka.connect.file.FileStreamSinkConnector",
+		"tasks.max":       "2",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = client.PauseConnector(lensestest.DefaultConnectCluster, "file-sink"); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := client.GetConnectorStatus(lensestest.DefaultConnectCluster, "file-sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := string(lenses.PAUSED), status.Connector.State; expected != got {
+		t.Fatalf("expected connector state %s but got %s", expected, got)
+	}
+
+	if expected, got := 2, len(status.Tasks); expected != got {
+		t.Fatalf("expected %d tasks but got %d", expected, got)
+	}
+
+	// schemas.
+	const avroSchema = `{"type":"record","name":"post","fields":[{"name":"id","type":"string"}]}`
+	id, err := client.RegisterSchema("reddit_posts-value", avroSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := client.GetLatestSchema("reddit_posts-value")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if schema.ID != id || schema.Version != 1 || schema.AvroSchema != avroSchema {
+		t.Fatalf("unexpected schema: %#+v", schema)
+	}
+
+	// quotas.
+	if err = client.CreateOrUpdateQuotaForUser("john", lenses.QuotaConfig{ProducerByteRate: "100000"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = client.DeleteQuotaForUser("john"); err != nil {
+		t.Fatal(err)
+	}
+
+	quotas, err := client.GetQuotas()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(quotas) != 0 {
+		t.Fatalf("expected all quotas to be removed but got: %#+v", quotas)
+	}
+}
+
+func TestServerProcessorsExecutionMode(t *testing.T) {
+	srv := lensestest.NewServer()
+	defer srv.Close()
+
+	srv.SetExecutionMode(lenses.ExecutionModeKubernetes)
+	client := openTestConnection(t, srv)
+
+	if err := client.CreateProcessor("avg", "INSERT INTO avg_out SELECT * FROM reddit_posts", 1, "", "", ""); !lensesErrorIs(err, http.StatusBadRequest) {
+		t.Fatalf("expected a bad request error without cluster and namespace but got: %v", err)
+	}
+
+	if err := client.CreateProcessor("avg", "INSERT INTO avg_out SELECT * FROM reddit_posts", 1, "k8s", "dev", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := client.LookupProcessorIdentifier("", "avg", "k8s", "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = client.UpdateProcessorRunners(id, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := client.GetProcessors()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Streams) != 1 {
+		t.Fatalf("expected one processor but got %d", len(result.Streams))
+	}
+
+	if p := result.Streams[0]; p.ID != "k8s.dev.avg" || p.Runners != 3 || p.ToTopic != "avg_out" {
+		t.Fatalf("unexpected processor: %#+v", p)
+	}
+}
+
+func TestServerLSQL(t *testing.T) {
+	srv := lensestest.NewServer()
+	defer srv.Close()
+
+	srv.AddRecords("reddit_posts",
+		lenses.LSQLRecord{Key: "1", Value: `{"title":"first"}`},
+		lenses.LSQLRecord{Key: "2", Value: `{"title":"second"}`},
+		lenses.LSQLRecord{Key: "3", Value: `{"title":"third"}`},
+	)
+
+	client := openTestConnection(t, srv)
+
+	records, _, stop, err := client.LSQLWait("SELECT * FROM reddit_posts LIMIT 2", true, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := 2, len(records); expected != got {
+		t.Fatalf("expected %d records but got %d", expected, got)
+	}
+
+	if records[1].Offset != 1 || records[1].Key != "2" || records[1].Topic != "reddit_posts" {
+		t.Fatalf("unexpected record: %#+v", records[1])
+	}
+
+	if stop.TotalRecords != 2 || stop.IsTopicEnd || len(stop.Offsets) != 1 || stop.Offsets[0].Max != 1 {
+		t.Fatalf("unexpected stop: %#+v", stop)
+	}
+
+	v, err := client.ValidateLSQL("SELEC * FROM reddit_posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v.IsValid || v.Line != 1 || v.Column != 1 {
+		t.Fatalf("expected an invalid query at 1:1 but got: %#+v", v)
+	}
+
+	// cancel a running query.
+	srv.SetStreamDelay(time.Second)
+
+	stopped := make(chan lenses.LSQLStop, 1)
+	go client.LSQL("SELECT * FROM reddit_posts", false, 0,
+		func(lenses.LSQLRecord) error { return nil },
+		func(s lenses.LSQLStop) error { stopped <- s; return nil }, nil, nil)
+
+	var queries []lenses.LSQLRunningQuery
+	for i := 0; i < 50 && len(queries) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+		if queries, err = client.GetRunningQueries(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(queries) != 1 {
+		t.Fatalf("expected one running query but got %d", len(queries))
+	}
+
+	if canceled, err := client.CancelQuery(queries[0].ID); err != nil || !canceled {
+		t.Fatalf("expected the query to be canceled but got: %v: %v", canceled, err)
+	}
+
+	select {
+	case s := <-stopped:
+		if !s.IsStopped {
+			t.Fatalf("expected a stopped query but got: %#+v", s)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the stop record")
+	}
+}
+
+func TestServerAlertsStream(t *testing.T) {
+	srv := lensestest.NewServer()
+	defer srv.Close()
+
+	client := openTestConnection(t, srv)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan lenses.Alert, 1)
+	go client.GetAlertsLiveContext(ctx, func(alert lenses.Alert) error {
+		received <- alert
+		return nil
+	})
+
+	alert := lenses.Alert{AlertID: 1000, Labels: lenses.AlertLabels{Severity: "HIGH"}, Annotations: lenses.AlertAnnotations{Summary: "broker down"}}
+
+	// the stream may not be connected yet, push until received.
+	for i := 0; ; i++ {
+		if err := client.RegisterAlert(alert); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case got := <-received:
+			if got.AlertID != alert.AlertID || got.Annotations.Summary != alert.Annotations.Summary {
+				t.Fatalf("unexpected alert: %#+v", got)
+			}
+			return
+		case <-time.After(50 * time.Millisecond):
+			if i == 50 {
+				t.Fatal("timed out waiting for the alert")
+			}
+		}
+	}
+}
+
+func TestServerFaultsAndTokenExpiry(t *testing.T) {
+	srv := lensestest.NewServer()
+	defer srv.Close()
+
+	client := openTestConnection(t, srv)
+
+	srv.InjectFault(lensestest.Fault{Method: http.MethodGet, Path: "/api/topics", StatusCode: http.StatusServiceUnavailable, Times: 1})
+
+	if _, err := client.GetTopics(); !lensesErrorIs(err, http.StatusServiceUnavailable) {
+		t.Fatalf("expected the injected fault but got: %v", err)
+	}
+
+	if _, err := client.GetTopics(); err != nil {
+		t.Fatalf("expected the fault to be applied only once but got: %v", err)
+	}
+
+	// the client should login again and replay the request.
+	srv.ExpireTokens()
+	oldToken := client.GetAccessToken()
+
+	if _, err := client.GetTopics(); err != nil {
+		t.Fatal(err)
+	}
+
+	if client.GetAccessToken() == oldToken {
+		t.Fatal("expected a new token after re-authentication")
+	}
+}
+
+func lensesErrorIs(err error, statusCode int) bool {
+	apiErr, ok := err.(*lenses.APIError)
+	return ok && apiErr.StatusCode == statusCode
+}
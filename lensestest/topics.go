@@ -0,0 +1,189 @@
+package lensestest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/landoop/lenses-go"
+)
+
+// AddRecords appends records to a topic, the topic is created with one partition if it does not exist.
+// The records' offsets are generated per partition and the topic name and the timestamp are filled if empty,
+// they are the data that the LSQL queries read from.
+func (s *Server) AddRecords(topicName string, records ...lenses.LSQLRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	topic, ok := s.topics[topicName]
+	if !ok {
+		topic = newTopic(lenses.CreateTopicPayload{TopicName: topicName, Replication: 1, Partitions: 1})
+		s.topics[topicName] = topic
+	}
+
+	for _, record := range records {
+		if record.Partition < 0 || record.Partition >= topic.Partitions {
+			record.Partition = 0
+		}
+
+		partition := &topic.MessagesPerPartition[record.Partition]
+		record.Topic = topicName
+		record.Offset = int(partition.End)
+		if record.Timestamp == 0 {
+			record.Timestamp = time.Now().Unix() * 1000
+		}
+
+		partition.End++
+		partition.Messages++
+		topic.TotalMessages++
+
+		s.records[topicName] = append(s.records[topicName], record)
+	}
+}
+
+// Records returns a copy of the topic's records.
+func (s *Server) Records(topicName string) []lenses.LSQLRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]lenses.LSQLRecord(nil), s.records[topicName]...)
+}
+
+func newTopic(payload lenses.CreateTopicPayload) *lenses.Topic {
+	if payload.Partitions <= 0 {
+		payload.Partitions = 1
+	}
+
+	if payload.Replication <= 0 {
+		payload.Replication = 1
+	}
+
+	topic := &lenses.Topic{
+		TopicName:            payload.TopicName,
+		KeyType:              "BYTES",
+		ValueType:            "BYTES",
+		Partitions:           payload.Partitions,
+		Replication:          payload.Replication,
+		Timestamp:            time.Now().Unix() * 1000,
+		Config:               make([]lenses.KV, 0, len(payload.Configs)),
+		ConsumersGroup:       make([]lenses.ConsumersGroup, 0),
+		MessagesPerPartition: make([]lenses.PartitionMessage, payload.Partitions),
+	}
+
+	for i := range topic.MessagesPerPartition {
+		topic.MessagesPerPartition[i].Partition = i
+	}
+
+	for key, value := range payload.Configs {
+		topic.Config = append(topic.Config, lenses.KV{"key": key, "value": fmt.Sprintf("%v", value)})
+	}
+	sortConfig(topic.Config)
+
+	return topic
+}
+
+func sortConfig(config []lenses.KV) {
+	sort.Slice(config, func(i, j int) bool {
+		return fmt.Sprintf("%v", config[i]["key"]) < fmt.Sprintf("%v", config[j]["key"])
+	})
+}
+
+// topicsAPI serves the "api/topics", "api/topics/{name}" and "api/topics/config/{name}".
+func (s *Server) topicsAPI(w http.ResponseWriter, r *http.Request, rest []string) {
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodGet:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		topics := make([]lenses.Topic, 0, len(s.topics))
+		for _, topic := range s.topics {
+			topics = append(topics, *topic)
+		}
+
+		sort.Slice(topics, func(i, j int) bool { return topics[i].TopicName < topics[j].TopicName })
+		writeJSON(w, http.StatusOK, topics)
+	case len(rest) == 0 && r.Method == http.MethodPost:
+		var payload lenses.CreateTopicPayload
+		if !readJSON(w, r, &payload) {
+			return
+		}
+
+		if payload.TopicName == "" {
+			writeError(w, http.StatusBadRequest, "topicName is required")
+			return
+		}
+
+		s.mu.Lock()
+		_, exists := s.topics[payload.TopicName]
+		if !exists {
+			s.topics[payload.TopicName] = newTopic(payload)
+		}
+		s.mu.Unlock()
+
+		if exists {
+			writeError(w, http.StatusConflict, fmt.Sprintf("Topic '%s' already exists", payload.TopicName))
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, fmt.Sprintf("Topic '%s' created", payload.TopicName))
+	case len(rest) == 1 && r.Method == http.MethodGet:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		topic, ok := s.topics[rest[0]]
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Topic '%s' does not exist", rest[0]))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, topic)
+	case len(rest) == 1 && r.Method == http.MethodDelete:
+		s.mu.Lock()
+		_, ok := s.topics[rest[0]]
+		delete(s.topics, rest[0])
+		delete(s.records, rest[0])
+		s.mu.Unlock()
+
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Topic '%s' does not exist", rest[0]))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, fmt.Sprintf("Topic '%s' marked for deletion", rest[0]))
+	case len(rest) == 2 && rest[0] == "config" && r.Method == http.MethodPut:
+		var payload lenses.UpdateTopicPayload
+		if !readJSON(w, r, &payload) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		topic, ok := s.topics[rest[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Topic '%s' does not exist", rest[1]))
+			return
+		}
+
+		for _, kv := range payload.Configs {
+			updated := false
+			for _, existing := range topic.Config {
+				if existing["key"] == kv["key"] {
+					existing["value"] = fmt.Sprintf("%v", kv["value"])
+					updated = true
+					break
+				}
+			}
+
+			if !updated {
+				topic.Config = append(topic.Config, lenses.KV{"key": kv["key"], "value": fmt.Sprintf("%v", kv["value"])})
+			}
+		}
+		sortConfig(topic.Config)
+
+		writeJSON(w, http.StatusOK, fmt.Sprintf("Topic '%s' updated", rest[1]))
+	default:
+		methodNotAllowed(w)
+	}
+}
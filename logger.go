@@ -0,0 +1,151 @@
+package lenses
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kataras/golog"
+)
+
+// Field is a key-value pair which gives context to a log message.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F returns a new log `Field` based on the "key" and "value".
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Logger describes the logger that the `Client` and the `LiveConnection` use to log their messages.
+// Each message comes with zero or more fields, i.e the request's method and url.
+//
+// Secrets like tokens and passwords are redacted before they reach the logger,
+// see `UsingUnredactedLogs` and `LiveConfiguration#UnredactedLogs` to opt-out.
+//
+// Look `UsingLogger` and `NewGologLogger` for more.
+type Logger interface {
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+}
+
+type gologLogger struct {
+	logger *golog.Logger
+}
+
+// NewGologLogger returns a `Logger` which writes to the "logger" golog instance.
+// It's the default logger, the `Client` and the `LiveConnection` create a new golog instance
+// of their own so the process-wide `golog.Default` is never modified,
+// its level is "debug" if the configuration's `Debug` is true otherwise "info".
+func NewGologLogger(logger *golog.Logger) Logger {
+	return &gologLogger{logger: logger}
+}
+
+func newDefaultLogger(debug bool) Logger {
+	logger := golog.New()
+	if debug {
+		logger.SetLevel("debug")
+	}
+
+	return NewGologLogger(logger)
+}
+
+func (l *gologLogger) Debug(msg string, fields ...Field) {
+	l.logger.Debugf("%s%s", msg, formatFields(fields))
+}
+
+func (l *gologLogger) Info(msg string, fields ...Field) {
+	l.logger.Infof("%s%s", msg, formatFields(fields))
+}
+
+func (l *gologLogger) Warn(msg string, fields ...Field) {
+	l.logger.Warnf("%s%s", msg, formatFields(fields))
+}
+
+func (l *gologLogger) Error(msg string, fields ...Field) {
+	l.logger.Errorf("%s%s", msg, formatFields(fields))
+}
+
+// formatFields formats the fields as "\n\tkey: value" lines.
+func formatFields(fields []Field) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	b := new(bytes.Buffer)
+	for _, f := range fields {
+		fmt.Fprintf(b, "\n\t%s: %v", f.Key, f.Value)
+	}
+
+	return b.String()
+}
+
+// UsingLogger sets the logger that the client uses to log its messages,
+// including the requests and the responses when the configuration's `Debug` is true.
+func UsingLogger(logger Logger) ConnectionOption {
+	return func(c *Client) {
+		if logger == nil {
+			return
+		}
+
+		c.logger = logger
+	}
+}
+
+// UsingUnredactedLogs disables the redaction of the token, the password
+// and the login payload from the client's log messages.
+//
+// It may be useful for debugging but the logs should be treated as secrets.
+func UsingUnredactedLogs() ConnectionOption {
+	return func(c *Client) {
+		c.unredacted = true
+	}
+}
+
+const redactedValue = "[REDACTED]"
+
+// unredacted reports whether the secrets are shown in the log messages,
+// the zero value redacts them.
+type unredacted bool
+
+// redact returns the "v" as it's if redaction is disabled, otherwise a static replacement.
+func (u unredacted) redact(v string) string {
+	if u || v == "" {
+		return v
+	}
+
+	return redactedValue
+}
+
+// redactHeader returns a copy of the "header" without the token's value.
+func (u unredacted) redactHeader(header http.Header) http.Header {
+	if u || header.Get(xKafkaLensesTokenHeaderKey) == "" {
+		return header
+	}
+
+	h := make(http.Header, len(header))
+	for k, v := range header {
+		h[k] = v
+	}
+	h.Set(xKafkaLensesTokenHeaderKey, redactedValue)
+
+	return h
+}
+
+// redactURI hides the token which is part of the logout's query.
+func (u unredacted) redactURI(uri string) string {
+	if u {
+		return uri
+	}
+
+	if idx := strings.Index(uri, logoutPath); idx != -1 {
+		return uri[:idx+len(logoutPath)] + redactedValue
+	}
+
+	return uri
+}
@@ -0,0 +1,231 @@
+package lenses
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LSQLCheckpoint keeps the last read offset of each partition of a query,
+// so the next run of the same query continues from where the previous one left off,
+// useful for incremental extracts that should not re-read the whole topic.
+//
+// The query is resumed by adding `_partition` and `_offset` predicates to its WHERE, see `Rewrite`,
+// the partitions without an offset, i.e new ones, are read from their start.
+//
+// Usage:
+// checkpoint, err := lenses.LoadLSQLCheckpoint("reddit_posts.checkpoint.json", "SELECT * FROM reddit_posts")
+// rows, err := client.Query(ctx, checkpoint.SQL, lenses.QueryOptions{Checkpoint: checkpoint})
+// for rows.Next() { [...] }
+// err = checkpoint.Save("reddit_posts.checkpoint.json")
+type LSQLCheckpoint struct {
+	// SQL is the query as given by the caller, not the rewritten one.
+	SQL string `json:"sql"`
+	// Offsets are the last read offsets by partition.
+	Offsets map[int]int64 `json:"offsets"`
+}
+
+// NewLSQLCheckpoint returns an empty checkpoint of the "sql", its first run reads the whole topic.
+func NewLSQLCheckpoint(sql string) *LSQLCheckpoint {
+	return &LSQLCheckpoint{SQL: strings.TrimSpace(sql), Offsets: make(map[int]int64)}
+}
+
+// LoadLSQLCheckpoint reads the checkpoint of the "sql" from the "filename",
+// if the file does not exist then it returns an empty checkpoint.
+// It fails if the file contains the checkpoint of a different query, its offsets would be meaningless.
+func LoadLSQLCheckpoint(filename string, sql string) (*LSQLCheckpoint, error) {
+	checkpoint := NewLSQLCheckpoint(sql)
+
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return checkpoint, nil
+		}
+		return nil, err
+	}
+
+	var saved LSQLCheckpoint
+	if err = json.Unmarshal(b, &saved); err != nil {
+		return nil, fmt.Errorf("client: checkpoint: %s: %v", filename, err)
+	}
+
+	if strings.TrimSpace(saved.SQL) != checkpoint.SQL {
+		return nil, fmt.Errorf("client: checkpoint: %s belongs to a different query: %s", filename, saved.SQL)
+	}
+
+	for partition, offset := range saved.Offsets {
+		checkpoint.Offsets[partition] = offset
+	}
+
+	return checkpoint, nil
+}
+
+// Save writes the checkpoint to the "filename", the file is replaced at once,
+// so an interrupted save does not leave a broken checkpoint behind.
+func (c *LSQLCheckpoint) Save(filename string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(filename), filepath.Base(filename)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err = f.Write(append(b, '\n')); err == nil {
+		err = f.Sync()
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(f.Name(), filename)
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+	}
+
+	return err
+}
+
+// Track records the record's offset as the last read of its partition,
+// it should be called after the record is processed.
+func (c *LSQLCheckpoint) Track(record LSQLRecord) {
+	if c.Offsets == nil {
+		c.Offsets = make(map[int]int64)
+	}
+
+	offset := record.Offset64()
+	if last, ok := c.Offsets[record.Partition]; !ok || offset > last {
+		c.Offsets[record.Partition] = offset
+	}
+}
+
+// Rewrite returns the checkpoint's query with the `_partition` and `_offset` predicates
+// that skip the already read records, i.e
+// "SELECT * FROM reddit_posts WHERE score > 10 LIMIT 100" with the offset 41 of the partition 0 becomes
+// "SELECT * FROM reddit_posts WHERE ((_partition = 0 AND _offset > 41) OR (_partition != 0)) AND (score > 10) LIMIT 100".
+//
+// Only the SELECT queries of a single topic are supported, the query is returned as it is if the checkpoint is empty.
+func (c *LSQLCheckpoint) Rewrite() (string, error) {
+	sql := c.SQL
+	if len(c.Offsets) == 0 {
+		return sql, nil
+	}
+
+	tokens, end := lsqlStatementWords(sql)
+	if len(tokens) == 0 || !strings.EqualFold(tokens[0].word, "SELECT") {
+		return "", fmt.Errorf("client: checkpoint: only SELECT queries can be resumed")
+	}
+
+	var where, clause *lsqlWord
+	for i := range tokens {
+		t := &tokens[i]
+		switch strings.ToUpper(t.word) {
+		case "JOIN":
+			return "", fmt.Errorf("client: checkpoint: queries with JOIN can not be resumed")
+		case "WHERE":
+			if where == nil && clause == nil {
+				where = t
+			}
+		case "GROUP", "ORDER", "HAVING", "LIMIT", "WINDOW", "EMIT":
+			if clause == nil {
+				clause = t
+			}
+		}
+	}
+
+	predicate := c.predicate()
+
+	// the rest of the statement, the clauses after the WHERE or the trailing ';'.
+	rest, restStart := sql[end:], end
+	if clause != nil {
+		rest, restStart = " "+sql[clause.start:], clause.start
+	}
+
+	if where == nil {
+		return strings.TrimRight(sql[:restStart], " \t\r\n") + " WHERE " + predicate + rest, nil
+	}
+
+	condition := strings.TrimSpace(sql[where.end:restStart])
+	return sql[:where.end] + " " + predicate + " AND (" + condition + ")" + rest, nil
+}
+
+// predicate returns the condition that matches the records after the checkpoint's offsets.
+func (c *LSQLCheckpoint) predicate() string {
+	partitions := make([]int, 0, len(c.Offsets))
+	for partition := range c.Offsets {
+		partitions = append(partitions, partition)
+	}
+	sort.Ints(partitions)
+
+	conditions := make([]string, 0, len(partitions)+1)
+	others := make([]string, 0, len(partitions))
+	for _, partition := range partitions {
+		conditions = append(conditions, fmt.Sprintf("(_partition = %d AND _offset > %d)", partition, c.Offsets[partition]))
+		others = append(others, fmt.Sprintf("_partition != %d", partition))
+	}
+
+	// the partitions that were not read yet.
+	conditions = append(conditions, "("+strings.Join(others, " AND ")+")")
+	return "(" + strings.Join(conditions, " OR ") + ")"
+}
+
+type lsqlWord struct {
+	word       string
+	start, end int
+}
+
+// lsqlStatementWords returns the top-level words, outside of quotes and parentheses, of the last statement of the "sql",
+// the statements are separated by ';', and the position that the statement ends.
+func lsqlStatementWords(sql string) (words []lsqlWord, end int) {
+	var (
+		quote     rune
+		depth     int
+		wordStart = -1
+	)
+
+	end = len(strings.TrimRight(sql, " \t\r\n"))
+	if end > 0 && sql[end-1] == ';' {
+		end--
+	}
+
+	for i, ch := range sql[:end] {
+		isWordChar := ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+		if wordStart >= 0 && !isWordChar {
+			words = append(words, lsqlWord{word: sql[wordStart:i], start: wordStart, end: i})
+			wordStart = -1
+		}
+
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+		case ch == ';' && depth == 0:
+			words = nil // a new statement starts.
+		case isWordChar && depth == 0 && wordStart < 0:
+			wordStart = i
+		}
+	}
+
+	if wordStart >= 0 {
+		words = append(words, lsqlWord{word: sql[wordStart:end], start: wordStart, end: end})
+	}
+
+	return
+}
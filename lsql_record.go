@@ -0,0 +1,270 @@
+package lenses
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Offset64 returns the record's offset as int64, the `Offset` may be truncated on 32-bit builds,
+// the received one is kept, see `UnmarshalJSON`.
+func (r LSQLRecord) Offset64() int64 {
+	if r.offset != 0 && int(r.offset) == r.Offset { // the `Offset` is not changed after the decoding.
+		return r.offset
+	}
+
+	return int64(r.Offset)
+}
+
+// lsqlRecord is the `LSQLRecord` without its json methods.
+type lsqlRecord LSQLRecord
+
+// UnmarshalJSON decodes the record, its offset is decoded as int64, see `Offset64`.
+func (r *LSQLRecord) UnmarshalJSON(b []byte) error {
+	var record struct {
+		lsqlRecord
+		Offset int64 `json:"offset"`
+	}
+
+	if err := json.Unmarshal(b, &record); err != nil {
+		return err
+	}
+
+	*r = LSQLRecord(record.lsqlRecord)
+	r.Offset, r.offset = int(record.Offset), record.Offset
+	return nil
+}
+
+// MarshalJSON encodes the record with its int64 offset, see `Offset64`.
+func (r LSQLRecord) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		lsqlRecord
+		Offset int64 `json:"offset"`
+	}{lsqlRecord(r), r.Offset64()})
+}
+
+// DecodeValue decodes the record's value, which is a json object in raw string, to the "v".
+// The numbers are decoded as `json.Number` when the "v" is or contains an interface{},
+// so the big integers and the decimals are not losing their precision.
+func (r LSQLRecord) DecodeValue(v interface{}) error {
+	return decodeJSONNumber([]byte(r.Value), v)
+}
+
+// DecodeKey decodes the record's key to the "v", same as the `DecodeValue`.
+// If the key is not a json, i.e a plain string, and the "v" is a *string then the key is set as it is.
+func (r LSQLRecord) DecodeKey(v interface{}) error {
+	err := decodeJSONNumber([]byte(r.Key), v)
+	if err != nil {
+		if s, ok := v.(*string); ok {
+			*s = r.Key
+			return nil
+		}
+	}
+
+	return err
+}
+
+// LSQLRawRecord is the `LSQLRecord` with its key and value as `json.RawMessage`,
+// useful to embed them as they are to another json, see `LSQLRecord#Raw`.
+type LSQLRawRecord struct {
+	Timestamp int64           `json:"timestamp"`
+	Partition int             `json:"partition"`
+	Key       json.RawMessage `json:"key"`
+	Offset    int64           `json:"offset"`
+	Topic     string          `json:"topic"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// Raw returns the `LSQLRawRecord` of the record.
+// The key or the value that is not a valid json, i.e a plain string key, is quoted.
+func (r LSQLRecord) Raw() LSQLRawRecord {
+	return LSQLRawRecord{
+		Timestamp: r.Timestamp,
+		Partition: r.Partition,
+		Key:       rawJSON(r.Key),
+		Offset:    r.Offset64(),
+		Topic:     r.Topic,
+		Value:     rawJSON(r.Value),
+	}
+}
+
+func rawJSON(s string) json.RawMessage {
+	if s == "" {
+		return json.RawMessage("null")
+	}
+
+	if b := []byte(s); json.Valid(b) {
+		return json.RawMessage(b)
+	}
+
+	b, _ := json.Marshal(s)
+	return json.RawMessage(b)
+}
+
+func decodeJSONNumber(b []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// lensesTagKey is the struct tag that the `LSQLRecord#Scan` reads.
+const lensesTagKey = "lenses"
+
+// The `lenses` struct tag values that map the record's information instead of the value's fields.
+const (
+	lensesTagRecordKey = "_key"
+	lensesTagTopic     = "_topic"
+	lensesTagPartition = "_partition"
+	lensesTagOffset    = "_offset"
+	lensesTagTimestamp = "_ts"
+	lensesTagValue     = "_value"
+)
+
+const (
+	lensesTagPathSep = "."
+	lensesTagIgnore  = "-"
+)
+
+// Scan maps the record to the "v" which should be a pointer to a struct.
+//
+// The value is decoded to the "v" as json first, same as the `DecodeValue`, the fields with the `lenses` struct tag
+// are skipped by the json decoding and they are set afterwards:
+// "_key", "_topic", "_partition", "_offset" and "_ts" (timestamp) for the record's information,
+// "_value" for the whole value and any other name is the path of a value's field, i.e "author.name".
+//
+// Usage:
+// var p struct { ID string `lenses:"_key"`; Offset int64 `lenses:"_offset"`; Title string `json:"title"`; Author string `lenses:"author.name"` }
+// err := record.Scan(&p)
+func (r LSQLRecord) Scan(v interface{}) error {
+	ptr := reflect.ValueOf(v)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("client: scan: expected a non-nil pointer to a struct but got %T", v)
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if r.Value != "" {
+		if err := decodeJSONNumber([]byte(r.Value), &fields); err != nil {
+			return err
+		}
+	}
+
+	elem := ptr.Elem()
+	typ := elem.Type()
+
+	// the fields that are tagged with `lenses` are skipped by the json decoding,
+	// otherwise the json would try to set the value's field with the same name, i.e "author" to the "Author".
+	valueFields := make(map[string]json.RawMessage, len(fields))
+	for name, raw := range fields {
+		if !isLensesTaggedField(typ, name) {
+			valueFields[name] = raw
+		}
+	}
+
+	if len(valueFields) > 0 {
+		b, err := json.Marshal(valueFields)
+		if err != nil {
+			return err
+		}
+
+		if err = decodeJSONNumber(b, v); err != nil {
+			return err
+		}
+	}
+
+	for i, n := 0, typ.NumField(); i < n; i++ {
+		field := typ.Field(i)
+		tag, ok := field.Tag.Lookup(lensesTagKey)
+		if !ok || tag == lensesTagIgnore || field.PkgPath != "" { // not tagged or unexported.
+			continue
+		}
+
+		target := elem.Field(i).Addr().Interface()
+
+		var err error
+		switch tag {
+		case lensesTagRecordKey:
+			err = r.DecodeKey(target)
+		case lensesTagTopic:
+			err = setJSON(target, r.Topic)
+		case lensesTagPartition:
+			err = setJSON(target, r.Partition)
+		case lensesTagOffset:
+			err = setJSON(target, r.Offset64())
+		case lensesTagTimestamp:
+			err = setJSON(target, r.Timestamp)
+		case lensesTagValue:
+			err = decodeJSONNumber(rawJSON(r.Value), target)
+		default:
+			raw, found := lookupJSONPath(fields, strings.Split(tag, lensesTagPathSep))
+			if !found {
+				continue
+			}
+
+			err = decodeJSONNumber(raw, target)
+		}
+
+		if err != nil {
+			return fmt.Errorf("client: scan: field %s: %v", field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// isLensesTaggedField reports whether the json "name" would be decoded to a struct field that has the `lenses` tag.
+func isLensesTaggedField(typ reflect.Type, name string) bool {
+	for i, n := 0, typ.NumField(); i < n; i++ {
+		field := typ.Field(i)
+		if _, ok := field.Tag.Lookup(lensesTagKey); !ok {
+			continue
+		}
+
+		jsonName := field.Name
+		if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag != "" {
+			jsonName = tag
+		}
+
+		if strings.EqualFold(jsonName, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setJSON sets the "value" to the "target" through json,
+// so the target can be any compatible type, i.e an int64 offset to a json.Number or a string.
+func setJSON(target interface{}, value interface{}) error {
+	if s, ok := target.(*string); ok {
+		*s = fmt.Sprintf("%v", value)
+		return nil
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return decodeJSONNumber(b, target)
+}
+
+func lookupJSONPath(fields map[string]json.RawMessage, path []string) (json.RawMessage, bool) {
+	raw, ok := fields[path[0]]
+	if !ok || len(path) == 1 {
+		return raw, ok
+	}
+
+	var inner map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &inner); err != nil {
+		return nil, false
+	}
+
+	return lookupJSONPath(inner, path[1:])
+}
+
+// Scan maps the current record to the "v", see `LSQLRecord#Scan`.
+func (r *Rows) Scan(v interface{}) error {
+	return r.record.Scan(v)
+}
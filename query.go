@@ -0,0 +1,389 @@
+package lenses
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// QueryOptions are the options that the `Query` accepts.
+type QueryOptions struct {
+	// WithOffsets requests the topic's offsets to be part of the `LSQLStop`.
+	WithOffsets bool
+	// StatsEvery is the interval that the server sends the query's stats, see `Rows#Stats`.
+	// The minimum is two seconds, smaller values disable the stats.
+	StatsEvery time.Duration
+	// MaxRecords stops the query after the given number of records, zero means no limit.
+	MaxRecords int
+	// MaxBytes stops the query after the given number of bytes of records' keys and values, zero means no limit.
+	MaxBytes int64
+	// Checkpoint resumes the query from the checkpoint's offsets, the "sql" must be the checkpoint's one,
+	// and tracks the offsets of the records that the caller processed, see `LSQLCheckpoint`.
+	// A record is tracked by the next `Rows#Next` call, so the current one, if the caller
+	// stops before it's processed, is read again by the next run.
+	Checkpoint *LSQLCheckpoint
+}
+
+// ErrQueryLimitExceeded is fired when a query is stopped because of the `QueryOptions#MaxRecords` or `QueryOptions#MaxBytes`.
+var ErrQueryLimitExceeded = errors.New("client: query stopped: max records or max bytes limit exceeded")
+
+const (
+	defaultLSQLWaitMaxRecords = 100000
+	defaultLSQLWaitMaxBytes   = 64 << 20 // 64MB.
+)
+
+// UsingLSQLWaitLimits sets the maximum number of records and the maximum bytes of records' keys and values
+// that the `LSQLWait` keeps in memory, a zero or negative value means no limit.
+// Defaults to 100000 records and 64MB.
+//
+// Usage:
+// lenses.OpenConnection(config, lenses.UsingLSQLWaitLimits(1000, 1<<20))
+func UsingLSQLWaitLimits(maxRecords int, maxBytes int64) ConnectionOption {
+	return func(c *Client) {
+		c.lsqlWaitMaxRecords = maxRecords
+		c.lsqlWaitMaxBytes = maxBytes
+	}
+}
+
+// Rows is the result of a query, see `Query`.
+// Its records are read one by one from the server using the `Next`.
+//
+// Usage:
+// rows, err := client.Query(ctx, "SELECT * FROM reddit_posts LIMIT 50", lenses.QueryOptions{})
+// if err != nil { [...] }
+// defer rows.Close()
+//
+//	for rows.Next() {
+//	    record := rows.Record()
+//	    [...]
+//	}
+//
+// if err = rows.Err(); err != nil { [...] }
+// stop := rows.Stop()
+type Rows struct {
+	c    *Client
+	sql  string
+	opts QueryOptions
+	// started is the time that the query was sent, it's used to match the query
+	// against the running ones when it's canceled, the server does not return the query's ID.
+	started time.Time
+	ctx     context.Context
+	cancel  context.CancelFunc
+	stream  *sseStream
+	onStats func(LSQLStats) error // used by the `LSQL` to fire its stats handler.
+
+	record  LSQLRecord
+	stats   LSQLStats
+	stop    LSQLStop
+	records int
+	bytes   int64
+	done    bool
+	// untracked reports whether the current record is not tracked by the `QueryOptions#Checkpoint` yet.
+	untracked bool
+	// ended reports whether the server finished the query, with a stop or an error or by closing the stream.
+	ended bool
+	// withStop reports whether the stop information is received.
+	withStop bool
+	err      error
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Query runs a lenses query and returns its rows, the caller should call the `Rows#Close` when finished.
+// The "ctx" applies to the whole lifetime of the rows.
+func (c *Client) Query(ctx context.Context, sql string, opts QueryOptions) (*Rows, error) {
+	if sql == "" {
+		return nil, errSQLEmpty
+	}
+
+	if cp := opts.Checkpoint; cp != nil {
+		if strings.TrimSpace(sql) != strings.TrimSpace(cp.SQL) {
+			return nil, fmt.Errorf("client: checkpoint: the query does not match the checkpoint's one: %s", cp.SQL)
+		}
+
+		rewritten, err := cp.Rewrite()
+		if err != nil {
+			return nil, err
+		}
+		sql = rewritten
+	}
+
+	path := lsqlPath + url.QueryEscape(sql)
+	// no need to use the url package for these, remember: we have already the ? on the `lsqlPath`.
+	if opts.WithOffsets {
+		path += "&offsets=true"
+	}
+
+	if statsEverySeconds := int(opts.StatsEvery.Seconds()); statsEverySeconds > 1 {
+		path += fmt.Sprintf("&stats=%d", statsEverySeconds)
+	} else {
+		opts.StatsEvery = 0
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	started := time.Now()
+
+	stream, err := c.openSSE(ctx, "LSQL", path, "")
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	stream.lineEvents = true
+
+	return &Rows{
+		c:       c,
+		sql:     sql,
+		opts:    opts,
+		started: started,
+		ctx:     ctx,
+		cancel:  cancel,
+		stream:  stream,
+	}, nil
+}
+
+// Next reads the next record, it returns false when the query is finished or failed,
+// the `Err` should be checked afterwards.
+func (r *Rows) Next() bool {
+	// the caller asks for the next one, so the current record is processed.
+	if r.untracked {
+		r.opts.Checkpoint.Track(r.record)
+		r.untracked = false
+	}
+
+	if r.done {
+		return false
+	}
+
+	for {
+		// the transport aborts the body read when the context is done,
+		// check it here too so a fast stream stops between the events as well.
+		if err := r.ctx.Err(); err != nil {
+			return r.fail(err)
+		}
+
+		event, err := r.stream.Next()
+		if err != nil {
+			if ctxErr := r.ctx.Err(); ctxErr != nil {
+				return r.fail(ctxErr) // canceled by the caller, report that instead of the read error.
+			}
+			if err == io.EOF {
+				r.ended = true
+				return r.fail(nil) // we read until the the end, exit with no error here.
+			}
+			return r.fail(err) // exit on first failure.
+		}
+
+		if len(event.Data) == 0 {
+			// empty message, we don't care, we ignore them at any way.
+			continue
+		}
+
+		messageType := event.Data[0]
+		message := event.Data[1:]
+
+		switch messageType {
+		case heartBeatPayloadType:
+			continue
+		case recordPayloadType:
+			record := LSQLRecord{}
+			if err = json.Unmarshal(message, &record); err != nil {
+				return r.fail(err)
+			}
+
+			r.records++
+			r.bytes += int64(len(record.Key) + len(record.Value))
+			if (r.opts.MaxRecords > 0 && r.records > r.opts.MaxRecords) || (r.opts.MaxBytes > 0 && r.bytes > r.opts.MaxBytes) {
+				return r.fail(ErrQueryLimitExceeded)
+			}
+
+			r.record = record
+			r.untracked = r.opts.Checkpoint != nil
+			return true
+		case stopPayloadType:
+			r.ended = true
+			if err = json.Unmarshal(message, &r.stop); err != nil {
+				return r.fail(err)
+			}
+
+			r.withStop = true
+			// And STOP.
+			return r.fail(nil)
+		case errPayloadType:
+			r.ended = true
+			errMessage := LSQLError{}
+			if err = json.Unmarshal(message, &errMessage); err != nil {
+				return r.fail(err)
+			}
+
+			// And STOP.
+			return r.fail(errMessage)
+		case statsPayloadType:
+			// the server sends those stats only if requested,
+			// ignore them otherwise to prevent any future surprises if back-end change.
+			if r.opts.StatsEvery == 0 {
+				continue
+			}
+
+			if err = json.Unmarshal(message, &r.stats); err != nil {
+				return r.fail(err)
+			}
+
+			if r.onStats != nil {
+				if err = r.onStats(r.stats); err != nil {
+					return r.fail(err)
+				}
+			}
+		default:
+			return r.fail(fmt.Errorf("client: sse: unknown event received: %s", string(event.Data)))
+		}
+	}
+}
+
+// fail marks the rows as finished and always returns false, the "err" can be nil.
+func (r *Rows) fail(err error) bool {
+	r.done = true
+	r.err = err
+	r.record = LSQLRecord{}
+	return false
+}
+
+// Record returns the current record, the one that the last `Next` read.
+func (r *Rows) Record() LSQLRecord {
+	return r.record
+}
+
+// Stats returns the latest stats that the server sent, if `QueryOptions#StatsEvery` is set.
+func (r *Rows) Stats() LSQLStats {
+	return r.stats
+}
+
+// Stop returns the information that the server sends when the query is finished,
+// it's filled after the `Next` returned false without an error.
+func (r *Rows) Stop() LSQLStop {
+	return r.stop
+}
+
+// Err returns the error, if any, that stopped the `Next`,
+// the query's own failures are type of `LSQLError`.
+func (r *Rows) Err() error {
+	return r.err
+}
+
+// queryCancelTimeout is the time that the `Rows#Close` waits for the server to cancel a running query.
+const queryCancelTimeout = 5 * time.Second
+
+// ErrRunningQueryNotFound is returned by the `Rows#Close` when the query did not finish
+// and it did not match any of the running queries on the server, so it could not be canceled
+// and it may keep running until its max.time.
+var ErrRunningQueryNotFound = errors.New("client: running query not found, its id is unknown")
+
+// Close stops reading the records and, if the query did not finish yet,
+// it cancels the running query on the server side too. It's safe to call more than once.
+//
+// It returns the `ErrRunningQueryNotFound` if the running query could not be matched.
+func (r *Rows) Close() error {
+	r.closeOnce.Do(func() {
+		r.closeStream()
+
+		if r.ended {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), queryCancelTimeout)
+		defer cancel()
+		canceled, err := r.c.cancelRunningQuery(ctx, r.sql, r.started)
+		if err == nil && !canceled {
+			err = ErrRunningQueryNotFound
+		}
+		r.closeErr = err
+	})
+
+	return r.closeErr
+}
+
+// closeStream stops reading the records without canceling the query on the server side.
+func (r *Rows) closeStream() {
+	if !r.done {
+		r.fail(nil)
+	}
+
+	r.cancel()
+	r.stream.Close()
+}
+
+// CancelRunningQuery cancels a running query based on its sql, it's useful when the query's ID is unknown,
+// i.e for queries that were started by the `LSQL` or the `Query`.
+//
+// The query is matched through the `GetRunningQueries`, by its sql and by the current user, if any.
+// If more than one matches then the latest one is canceled.
+// It returns false if no query matched.
+func (c *Client) CancelRunningQuery(sql string) (bool, error) {
+	return c.CancelRunningQueryContext(context.Background(), sql)
+}
+
+// CancelRunningQueryContext same as `CancelRunningQuery` but it accepts a context.Context for cancellation and deadlines.
+func (c *Client) CancelRunningQueryContext(ctx context.Context, sql string) (bool, error) {
+	return c.cancelRunningQuery(ctx, sql, time.Time{})
+}
+
+// cancelRunningQuery cancels the running query that matches the sql and the current user,
+// if "started" is not zero then the one that the server started closest to that time is canceled, otherwise the latest one.
+// The clocks of the client and the server may differ, so the time is not an exact filter.
+func (c *Client) cancelRunningQuery(ctx context.Context, sql string, started time.Time) (bool, error) {
+	queries, err := c.GetRunningQueriesContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	user := c.User()
+	sql = strings.TrimSpace(sql)
+
+	var match *LSQLRunningQuery
+	for i, q := range queries {
+		if strings.TrimSpace(q.SQL) != sql {
+			continue
+		}
+
+		if user.ID != "" && q.User != "" && q.User != user.ID && q.User != user.Name {
+			continue
+		}
+
+		if match == nil || isCloserRunningQuery(q, *match, started) {
+			match = &queries[i]
+		}
+	}
+
+	if match == nil {
+		return false, nil
+	}
+
+	return c.CancelQueryContext(ctx, match.ID)
+}
+
+// isCloserRunningQuery reports whether the "q" matches the "started" time better than the "other",
+// the latest one, by ID, wins if the time is zero or the timestamps are equal.
+func isCloserRunningQuery(q, other LSQLRunningQuery, started time.Time) bool {
+	if !started.IsZero() && q.Timestamp > 0 && other.Timestamp > 0 {
+		startedMillis := started.UnixNano() / int64(time.Millisecond)
+		if d, otherD := absInt64(q.Timestamp-startedMillis), absInt64(other.Timestamp-startedMillis); d != otherD {
+			return d < otherD
+		}
+	}
+
+	return q.ID > other.ID
+}
+
+func absInt64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
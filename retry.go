@@ -0,0 +1,214 @@
+package lenses
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// RetryPredicate describes the form of the function which decides if a failed request should be sent again.
+// The "resp" is nil when the "err" is not nil, i.e on connection failures.
+type RetryPredicate func(method string, resp *http.Response, err error) bool
+
+// RetryPolicy describes how the client should retry requests that failed because of transient failures,
+// i.e connection resets, timeouts or 502, 503 and 504 from the Lenses proxy endpoints.
+//
+// Look `UsingRetryPolicy` for more.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times that a request is sent, including the first one.
+	// Defaults to 3.
+	MaxAttempts int
+	// MinBackoff is the wait time before the first retry, it is doubled on each retry.
+	// Defaults to 200 milliseconds.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum wait time between two attempts,
+	// a larger server's "Retry-After" is capped to it too.
+	// Defaults to 5 seconds.
+	MaxBackoff time.Duration
+	// Retryable reports whether a request should be retried.
+	// Defaults to the `IsRetryable`.
+	Retryable RetryPredicate
+}
+
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryMinBackoff  = 200 * time.Millisecond
+	defaultRetryMaxBackoff  = 5 * time.Second
+)
+
+// UsingRetryPolicy sets the retry policy that is used to send again
+// the requests that failed because of transient failures.
+// The policy's empty fields are filled with the defaults.
+//
+// Usage:
+// lenses.OpenConnection(config, lenses.UsingRetryPolicy(lenses.RetryPolicy{MaxAttempts: 5}))
+func UsingRetryPolicy(policy RetryPolicy) ConnectionOption {
+	return func(c *Client) {
+		if policy.MaxAttempts <= 0 {
+			policy.MaxAttempts = defaultRetryMaxAttempts
+		}
+
+		if policy.MinBackoff <= 0 {
+			policy.MinBackoff = defaultRetryMinBackoff
+		}
+
+		if policy.MaxBackoff < policy.MinBackoff {
+			policy.MaxBackoff = defaultRetryMaxBackoff
+			if policy.MaxBackoff < policy.MinBackoff {
+				policy.MaxBackoff = policy.MinBackoff
+			}
+		}
+
+		if policy.Retryable == nil {
+			policy.Retryable = IsRetryable
+		}
+
+		c.retryPolicy = &policy
+	}
+}
+
+// IsRetryable is the default `RetryPredicate`.
+// It reports true for idempotent methods (GET, HEAD, OPTIONS, PUT and DELETE)
+// that failed with timeout, connection reset or a 502, 503 or 504 status code.
+func IsRetryable(method string, resp *http.Response, err error) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+
+	if err != nil {
+		return isTimeout(err) || isConnectionReset(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
+
+func isConnectionReset(err error) bool {
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+
+	if opErr, ok := err.(*net.OpError); ok {
+		err = opErr.Err
+	}
+
+	if sysErr, ok := err.(*os.SyscallError); ok {
+		err = sysErr.Err
+	}
+
+	return err == syscall.ECONNRESET
+}
+
+// backoff returns the wait time before the next attempt,
+// the server's "Retry-After" header has priority, up to the `MaxBackoff`, if missing then
+// it is an exponential backoff, based on the attempt, with jitter.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if wait > p.MaxBackoff {
+				wait = p.MaxBackoff // i.e a proxy's "Retry-After: 3600" should not stall the call for an hour.
+			}
+			return wait
+		}
+	}
+
+	wait := p.MinBackoff << uint(attempt-1)
+	if wait > p.MaxBackoff || wait <= 0 { // <= 0 on overflow.
+		wait = p.MaxBackoff
+	}
+
+	// keep the half and add a random part of the other half so concurrent clients don't retry at the same time.
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter parses the "Retry-After" header's value,
+// which can be either the seconds to wait or an HTTP date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(v); err == nil {
+		wait := time.Until(at)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+// send sends a new request based on the `Client#do`'s input to the active host, it may send it more than once
+// based on the retry policy, if any, and it fails over to the next healthy host, if any.
+// It returns the response and the token of the last attempt.
+func (c *Client) send(ctx context.Context, op, method, path, contentType string, send []byte, options ...requestOption) (*http.Response, string, error) {
+	failovers := 0
+
+	for attempt := 1; ; {
+		hostIdx := atomic.LoadInt32(&c.activeHostIdx)
+		uri := c.hosts[hostIdx] + "/" + path
+
+		req, token, err := c.newRequest(ctx, method, path, uri, contentType, send, options...)
+		if err != nil {
+			return nil, "", err
+		}
+
+		resp, err := c.roundTrip(op, req)
+
+		// try the next healthy host, each one once, before the retry policy.
+		if failovers < len(c.hosts)-1 && c.shouldFailover(ctx, method, resp, err) && c.failover(ctx, hostIdx) {
+			failovers++
+			if resp != nil {
+				resp.Body.Close()
+			}
+			continue
+		}
+
+		p := c.retryPolicy
+		if p == nil || attempt >= p.MaxAttempts || ctx.Err() != nil || !p.Retryable(method, resp, err) {
+			return resp, token, err
+		}
+
+		wait := p.backoff(attempt, resp)
+		if resp != nil {
+			c.logger.Debug("Client#send: request failed, retrying",
+				F("uri", method+":"+c.unredacted.redactURI(uri)), F("status", resp.StatusCode), F("wait", wait))
+			resp.Body.Close()
+		} else {
+			c.logger.Debug("Client#send: request failed, retrying",
+				F("uri", method+":"+c.unredacted.redactURI(uri)), F("error", err), F("wait", wait))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, "", ctx.Err()
+		case <-time.After(wait):
+		}
+
+		attempt++
+	}
+}
@@ -0,0 +1,354 @@
+package lenses
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// SSEOptions describes how the client reads the server-sent events streams,
+// i.e the `Query` and the `GetAlertsLive`.
+//
+// Look `UsingSSEOptions` for more.
+type SSEOptions struct {
+	// MaxLineLength is the maximum size of a line of the stream, the longer lines fail with the `ErrSSELineTooLong`.
+	// Defaults to 16MB.
+	MaxLineLength int
+	// HeartbeatTimeout is the maximum time to wait for any data, including the server's heartbeats,
+	// the stream fails with the `ErrSSEHeartbeatTimeout` when it's exceeded.
+	// Defaults to zero, no timeout.
+	HeartbeatTimeout time.Duration
+	// RetryInterval is the wait time before reconnecting, the server can change it with the "retry:" field.
+	// Defaults to 3 seconds.
+	RetryInterval time.Duration
+	// MaxReconnects is the number of the consecutive reconnections of the `GetAlertsLive`
+	// after the server closed the stream or the connection failed, the reconnection sends the "Last-Event-ID" of the last event received.
+	// Defaults to zero, no reconnection, a negative value means that it reconnects until the context is canceled.
+	//
+	// The `Query` does not reconnect, a query can not be resumed.
+	MaxReconnects int
+}
+
+const (
+	defaultSSEMaxLineLength = 16 << 20 // 16MB.
+	defaultSSERetryInterval = 3 * time.Second
+)
+
+// UsingSSEOptions sets the options of the server-sent events streams.
+// The options' empty fields are filled with the defaults.
+//
+// Usage:
+// lenses.OpenConnection(config, lenses.UsingSSEOptions(lenses.SSEOptions{HeartbeatTimeout: time.Minute, MaxReconnects: -1}))
+func UsingSSEOptions(opts SSEOptions) ConnectionOption {
+	return func(c *Client) {
+		c.sseOpts = opts
+	}
+}
+
+func (c *Client) sseOptions() SSEOptions {
+	opts := c.sseOpts
+	if opts.MaxLineLength <= 0 {
+		opts.MaxLineLength = defaultSSEMaxLineLength
+	}
+
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = defaultSSERetryInterval
+	}
+
+	return opts
+}
+
+var (
+	// ErrSSELineTooLong is fired when a line of a server-sent events stream exceeds the `SSEOptions#MaxLineLength`.
+	ErrSSELineTooLong = errors.New("client: sse: line too long")
+	// ErrSSEHeartbeatTimeout is fired when nothing is received from a server-sent events stream for the `SSEOptions#HeartbeatTimeout`.
+	ErrSSEHeartbeatTimeout = errors.New("client: sse: heartbeat timeout")
+)
+
+// sseEvent is a server-sent event, see https://html.spec.whatwg.org/multipage/server-sent-events.html#event-stream-interpretation.
+type sseEvent struct {
+	ID    string
+	Event string
+	// Data are the event's "data:" lines joined with a new line.
+	Data []byte
+}
+
+// sseReader parses the events of a server-sent events stream,
+// the lines can end with "\n", "\r\n" or "\r" and the events are separated by an empty line.
+type sseReader struct {
+	scanner *bufio.Scanner
+	started bool
+	// lineEvents dispatches an event per "data:" line, without waiting for the empty line,
+	// the LSQL streams frame each payload, record or stats, with a single new line.
+	lineEvents bool
+
+	// lastEventID is the last "id:" field received, it's kept across the events.
+	lastEventID string
+	// retry is the reconnection time that the server sent, zero if none.
+	retry time.Duration
+
+	event string
+	data  bytes.Buffer
+}
+
+func newSSEReader(r io.Reader, maxLineLength int) *sseReader {
+	scanner := bufio.NewScanner(r)
+	initialSize := 4096
+	if maxLineLength < initialSize {
+		initialSize = maxLineLength
+	}
+	scanner.Buffer(make([]byte, initialSize), maxLineLength)
+	scanner.Split(scanSSELines)
+
+	return &sseReader{scanner: scanner}
+}
+
+// scanSSELines is the `bufio.SplitFunc` of the server-sent events lines.
+func scanSSELines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+
+		if i+1 < len(data) {
+			if data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+			return i + 1, data[:i], nil
+		}
+
+		if atEOF {
+			return i + 1, data[:i], nil
+		}
+		// wait for the next byte, it may be the "\n" of a "\r\n".
+	}
+
+	// an incomplete line at the end of the stream is discarded, as the incomplete event.
+	return 0, nil, nil
+}
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Next returns the next event, it returns io.EOF when the stream ends.
+func (r *sseReader) Next() (sseEvent, error) {
+	for r.scanner.Scan() {
+		line := r.scanner.Bytes()
+		if !r.started {
+			r.started = true
+			line = bytes.TrimPrefix(line, utf8BOM)
+		}
+
+		if len(line) == 0 {
+			// dispatch the event, if any.
+			if r.data.Len() == 0 {
+				r.event = ""
+				continue
+			}
+
+			data := r.data.Bytes()
+			event := sseEvent{
+				ID:    r.lastEventID,
+				Event: r.event,
+				Data:  append([]byte(nil), data[:len(data)-1]...), // without the last new line.
+			}
+
+			r.event = ""
+			r.data.Reset()
+			return event, nil
+		}
+
+		if line[0] == ':' {
+			continue // a comment, usually a heartbeat.
+		}
+
+		field, value := line, []byte(nil)
+		if i := bytes.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], line[i+1:]
+			if len(value) > 0 && value[0] == ' ' {
+				value = value[1:]
+			}
+		}
+
+		switch string(field) {
+		case "event":
+			r.event = string(value)
+		case "data":
+			if r.lineEvents {
+				event := sseEvent{ID: r.lastEventID, Event: r.event, Data: append([]byte(nil), value...)}
+				r.event = ""
+				return event, nil
+			}
+
+			r.data.Write(value)
+			r.data.WriteByte('\n')
+		case "id":
+			if bytes.IndexByte(value, 0) == -1 {
+				r.lastEventID = string(value)
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(string(value), 10, 63); err == nil {
+				r.retry = time.Duration(ms) * time.Millisecond
+			}
+		default:
+			// unknown fields are ignored.
+		}
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		if err == bufio.ErrTooLong {
+			return sseEvent{}, ErrSSELineTooLong
+		}
+		return sseEvent{}, err
+	}
+
+	return sseEvent{}, io.EOF
+}
+
+// sseHeartbeat closes the response body when nothing is read for the `SSEOptions#HeartbeatTimeout`,
+// so the blocked read fails instead of waiting forever on a dead connection.
+type sseHeartbeat struct {
+	body    io.ReadCloser
+	timeout time.Duration
+	timer   *time.Timer
+	expired int32 // atomic.
+}
+
+func newSSEHeartbeat(body io.ReadCloser, timeout time.Duration) *sseHeartbeat {
+	h := &sseHeartbeat{body: body, timeout: timeout}
+	if timeout > 0 {
+		h.timer = time.AfterFunc(timeout, func() {
+			atomic.StoreInt32(&h.expired, 1)
+			body.Close()
+		})
+	}
+
+	return h
+}
+
+func (h *sseHeartbeat) Read(p []byte) (int, error) {
+	n, err := h.body.Read(p)
+	if n > 0 && h.timer != nil {
+		h.timer.Reset(h.timeout)
+	}
+
+	if err != nil && h.isExpired() {
+		err = ErrSSEHeartbeatTimeout
+	}
+
+	return n, err
+}
+
+func (h *sseHeartbeat) isExpired() bool {
+	return atomic.LoadInt32(&h.expired) == 1
+}
+
+func (h *sseHeartbeat) Close() error {
+	if h.timer != nil {
+		h.timer.Stop()
+	}
+
+	return h.body.Close()
+}
+
+// sseStream is a connected server-sent events stream.
+type sseStream struct {
+	*sseReader
+	body      io.ReadCloser
+	heartbeat *sseHeartbeat
+}
+
+// Next returns the next event, it returns io.EOF when the server closed the stream.
+func (s *sseStream) Next() (sseEvent, error) {
+	event, err := s.sseReader.Next()
+	if err != nil && s.heartbeat.isExpired() {
+		err = ErrSSEHeartbeatTimeout
+	}
+
+	return event, err
+}
+
+func (s *sseStream) Close() error {
+	return s.body.Close()
+}
+
+const lastEventIDHeaderKey = "Last-Event-ID"
+
+// openSSE connects to a server-sent events endpoint, the "lastEventID" is sent on reconnection.
+func (c *Client) openSSE(ctx context.Context, op, path, lastEventID string) (*sseStream, error) {
+	opts := c.sseOptions()
+
+	resp, err := c.do(ctx, op, http.MethodGet, path, contentTypeJSON, nil, func(r *http.Request) {
+		r.Header.Add(acceptHeaderKey, "application/json, text/event-stream")
+		if lastEventID != "" {
+			r.Header.Set(lastEventIDHeaderKey, lastEventID)
+		}
+	}, schemaAPIOption)
+	if err != nil {
+		return nil, err
+	}
+
+	// the heartbeat wraps the raw body, so its timer never closes the gzip reader in the middle of a read.
+	heartbeat := newSSEHeartbeat(resp.Body, opts.HeartbeatTimeout)
+	resp.Body = heartbeat
+
+	body, err := c.acquireResponseBodyStream(resp)
+	if err != nil {
+		heartbeat.Close()
+		return nil, err
+	}
+
+	return &sseStream{
+		sseReader: newSSEReader(body, opts.MaxLineLength),
+		body:      body,
+		heartbeat: heartbeat,
+	}, nil
+}
+
+// reconnectSSE waits for the retry interval and connects to the stream again with the last event's id,
+// the "attempts" are the consecutive reconnections so far, it returns the "lastErr" when the `SSEOptions#MaxReconnects` are exhausted.
+func (c *Client) reconnectSSE(ctx context.Context, op, path string, previous *sseStream, lastErr error, attempts *int) (*sseStream, error) {
+	opts := c.sseOptions()
+
+	wait := opts.RetryInterval
+	if previous.retry > 0 {
+		wait = previous.retry
+	}
+
+	for {
+		if opts.MaxReconnects >= 0 && *attempts >= opts.MaxReconnects {
+			return nil, lastErr
+		}
+		*attempts++
+
+		c.logger.Debug("Client#sse: reconnecting", F("op", op), F("attempt", *attempts), F("wait", wait), F("error", lastErr))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		stream, err := c.openSSE(ctx, op, path, previous.lastEventID)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+
+			lastErr = err
+			continue
+		}
+
+		// the last event id and the retry interval are kept until the server sends new ones.
+		stream.lastEventID = previous.lastEventID
+		stream.retry = previous.retry
+		return stream, nil
+	}
+}
@@ -1,9 +1,11 @@
 package lenses
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"strings"
@@ -12,7 +14,6 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
-	"github.com/kataras/golog"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -120,23 +121,70 @@ type (
 		// TLSClientConfig specifies the TLS configuration to use with tls.Client.
 		// If nil, the default configuration is used.
 		TLSClientConfig *tls.Config
+
+		// Logger is used to log the connection's messages.
+		// If nil, a new golog instance is used, its level is "debug" if `Debug` is true.
+		Logger Logger `json:"-" yaml:"-" toml:"-"`
+		// UnredactedLogs disables the redaction of the auth token and the login's payload from the log messages.
+		// It may be useful for debugging but the logs should be treated as secrets.
+		UnredactedLogs bool `json:"-" yaml:"-" toml:"-"`
+
+		// Reconnect enables the automatic reconnection when the connection is lost or the heartbeats are missed,
+		// the login is sent again and the SUBSCRIBE requests are replayed, see `OnLifecycle` to follow the reconnections.
+		Reconnect bool
+		// MaxReconnects is the maximum number of the consecutive reconnection attempts,
+		// zero or negative means no limit.
+		MaxReconnects int
+		// ReconnectMinBackoff is the wait time before the first reconnection attempt, it is doubled on each attempt.
+		// Defaults to 500 milliseconds.
+		ReconnectMinBackoff time.Duration
+		// ReconnectMaxBackoff is the maximum wait time between two reconnection attempts.
+		// Defaults to 30 seconds.
+		ReconnectMaxBackoff time.Duration
+		// HeartbeatTimeout is the maximum time to wait for any message, including the server's HEARTBEATs,
+		// the connection is considered lost when it's exceeded.
+		// Defaults to zero, no timeout.
+		HeartbeatTimeout time.Duration
+		// RequestTimeout is the maximum time to wait for the response of a typed request, i.e `Subscribe`.
+		// Defaults to 30 seconds.
+		RequestTimeout time.Duration
 	}
 
 	// LiveConnection is the websocket connection.
 	LiveConnection struct {
+		// correlationID is the last allocated correlation id of the typed requests, it's first for the 64-bit alignment.
+		correlationID int64 // atomic.
+
 		conn   *websocket.Conn
 		config LiveConfiguration
 
 		receiveStop chan struct{}
 		closed      uint32
+		// closeErr is the failure that closed the connection, i.e the reconnection gave up, see `Wait`.
+		closeErr error
 
-		authToken string // generated by the login and `OnSuccess` internal listener.
+		authToken string // generated by the login, see `resolveResponse`.
 		endpoint  string // generated by the config's host and the client id.
 
-		listeners map[ResponseType][]LiveListener
-		mu        sync.RWMutex
+		// connMu protects the conn, the auth token and the subscriptions, the conn and the token are replaced on reconnection,
+		// and it serializes the writes, the websocket connection supports one writer at a time.
+		connMu sync.Mutex
+		// subscriptions are the SUBSCRIBE requests, not rejected by the server, that are replayed on reconnection.
+		subscriptions []liveSubscription
+
+		listeners          map[ResponseType][]LiveListener
+		lifecycleListeners []LiveLifecycleListener
+		mu                 sync.RWMutex
 
-		errors chan error // error comes from reader.
+		// futures are the pending typed requests by correlation id, see `expect`.
+		futures     map[int64]*LiveFuture
+		futuresMu   sync.Mutex
+		loginFuture *LiveFuture
+
+		errors chan error // error comes from reader, see `Err`.
+
+		logger     Logger
+		unredacted unredacted
 	}
 )
 
@@ -164,15 +212,14 @@ type (
 // })
 //
 // c.OnSuccess(func(cub lenses.LivePublisher, response lenses.LiveResponse) error{
-//    pub.Publish(lenses.SubscribeRequest, 2, `{"sqls": ["SELECT * FROM reddit_posts LIMIT 3"]}`)
+//    [...]
 // }) also OnKafkaMessage, OnError, OnHeartbeat, OnInvalidRequest.
 //
+// resp, err := c.Subscribe(ctx, "SELECT * FROM reddit_posts LIMIT 3").Wait()
+// also Unsubscribe, PublishMessage and Commit, or the raw `Publish`.
+//
 // If at least one listener returned an error then the communication is terminated.
 func OpenLiveConnection(config LiveConfiguration) (*LiveConnection, error) {
-	if config.Debug {
-		golog.SetLevel("debug")
-	}
-
 	if config.ClientID == "" {
 		config.ClientID = uuid.Must(uuid.NewV4()).String()
 	}
@@ -181,71 +228,99 @@ func OpenLiveConnection(config LiveConfiguration) (*LiveConnection, error) {
 		config.HandshakeTimeout = 45 * time.Second
 	}
 
+	if config.ReconnectMinBackoff <= 0 {
+		config.ReconnectMinBackoff = 500 * time.Millisecond
+	}
+
+	if config.ReconnectMaxBackoff < config.ReconnectMinBackoff {
+		config.ReconnectMaxBackoff = 30 * time.Second
+		if config.ReconnectMaxBackoff < config.ReconnectMinBackoff {
+			config.ReconnectMaxBackoff = config.ReconnectMinBackoff
+		}
+	}
+
+	if config.RequestTimeout <= 0 {
+		config.RequestTimeout = defaultLiveRequestTimeout
+	}
+
 	config.Host = strings.Replace(config.Host, "https://", "wss://", 1)
 	config.Host = strings.Replace(config.Host, "http://", "ws://", 1)
 
 	c := &LiveConnection{
-		config:      config,
-		endpoint:    fmt.Sprintf("%s/api/kafka/ws/%s", config.Host, config.ClientID),
-		receiveStop: make(chan struct{}),
-		listeners:   make(map[ResponseType][]LiveListener),
-		errors:      make(chan error),
+		correlationID: liveFirstCorrelationID - 1,
+		config:        config,
+		endpoint:      fmt.Sprintf("%s/api/kafka/ws/%s", config.Host, config.ClientID),
+		receiveStop:   make(chan struct{}),
+		listeners:     make(map[ResponseType][]LiveListener),
+		futures:       make(map[int64]*LiveFuture),
+		errors:        make(chan error, liveErrorsBuffer),
+		logger:        config.Logger,
+		unredacted:    unredacted(config.UnredactedLogs),
+	}
+
+	if c.logger == nil {
+		c.logger = newDefaultLogger(config.Debug)
 	}
 
 	return c, c.start()
 }
 
-func (c *LiveConnection) start() error {
-	// first connect, handshake with the websocket server for upgradation.
+// dial handshakes with the websocket server for upgradation.
+func (c *LiveConnection) dial() (*websocket.Conn, error) {
 	dialer := websocket.Dialer{
 		Proxy:            http.ProxyFromEnvironment,
 		HandshakeTimeout: c.config.HandshakeTimeout,
 		ReadBufferSize:   c.config.ReadBufferSize,
 		WriteBufferSize:  c.config.WriteBufferSize,
+		TLSClientConfig:  c.config.TLSClientConfig,
 	}
 
 	conn, _, err := dialer.Dial(c.endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("connect failure for '%s': %v", c.config.Host, err)
+	}
 
+	return conn, nil
+}
+
+func (c *LiveConnection) start() error {
+	// first connect.
+	conn, err := c.dial()
 	if err != nil {
-		err = fmt.Errorf("connect failure for '%s': %v", c.config.Host, err)
-		golog.Debug(err)
+		c.logger.Debug(err.Error())
 		return err
 	}
 	// set the websocket connection.
 	c.conn = conn
 
-	// register the internal listener in order to check
-	// for the login and set the generated authentication token
-	// which should be used to send messages to the websocket server.
-	c.OnSuccess(func(_ LivePublisher, resp LiveResponse) error {
-		if resp.CorrelationID == 1 {
-			// the login, otherwise it sends the topic name for example after the subscribe.
-			err := json.Unmarshal(resp.Content, &c.authToken)
-			if err == nil {
-				golog.Debugf("login succeed, auth token: %s", c.authToken)
-			}
-			return err
-		}
-		return nil
-	})
+	// the login's response sets the generated authentication token
+	// which should be used to send messages to the websocket server,
+	// the typed requests wait for it.
+	c.loginFuture = c.expect(context.Background(), liveLoginCorrelationID)
 
 	go c.readLoop()
 
 	// then login.
 	if err := c.login(); err != nil {
 		err = fmt.Errorf("login failure: %v", err)
-		golog.Debug(err)
+		c.logger.Debug(err.Error())
 		return err
 	}
 
 	return nil
 }
 
-// Wait waits until interruptSignal fires, if it's nil then it waits for ever.
+// Wait waits until interruptSignal fires or the connection is closed, i.e the reconnection gave up,
+// if it's nil then it waits until the connection is closed.
+// It returns the failure that closed the connection, if any.
 func (c *LiveConnection) Wait(interruptSignal <-chan os.Signal) error {
 	select {
 	case <-interruptSignal:
 		return c.Close()
+	case <-c.receiveStop:
+		c.connMu.Lock()
+		defer c.connMu.Unlock()
+		return c.closeErr
 	}
 }
 
@@ -270,54 +345,403 @@ func makeLoginContent(user, password string) string {
 func (c *LiveConnection) login() error {
 	req := LiveRequest{
 		Type:          LoginRequest,
-		CorrelationID: 1,
+		CorrelationID: liveLoginCorrelationID,
 		Content:       makeLoginContent(c.config.User, c.config.Password),
 	}
 
+	c.logger.Debug("login", F("request", fmt.Sprintf("%#+v", c.redactRequest(req))))
+
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
 	return c.conn.WriteJSON(req)
 }
 
+// redactRequest returns a copy of the "req" without the auth token and the login's credentials.
+func (c *LiveConnection) redactRequest(req LiveRequest) LiveRequest {
+	req.AuthToken = c.unredacted.redact(req.AuthToken)
+	if req.Type == LoginRequest {
+		req.Content = c.unredacted.redact(req.Content)
+	}
+
+	return req
+}
+
+// redactResponseContent returns the "resp"'s content without the auth token of the login's response.
+func (c *LiveConnection) redactResponseContent(resp LiveResponse) string {
+	if resp.Type == SuccessResponse && resp.CorrelationID == liveLoginCorrelationID {
+		return c.unredacted.redact(string(resp.Content))
+	}
+
+	return string(resp.Content)
+}
+
+// liveErrorsBuffer is the number of the errors that the `Err` keeps until they are received,
+// the next ones are dropped, so the reader never blocks.
+const liveErrorsBuffer = 16
+
 // Err can be used to receive the errors coming from the communication,
 // the listeners' errors are sending to that channel too.
+// The errors are dropped when nobody receives them, see the logger's debug messages for all of them.
 func (c *LiveConnection) Err() <-chan error {
 	return c.errors
 }
 
 func (c *LiveConnection) sendErr(err error) {
-	golog.Debug(err)
-	c.errors <- err
+	c.logger.Debug(err.Error())
+
+	select {
+	case c.errors <- err:
+	default:
+		// nobody receives the errors, drop it instead of blocking the reader.
+	}
+}
+
+func (c *LiveConnection) isClosed() bool {
+	return atomic.LoadUint32(&c.closed) > 0
 }
 
 func (c *LiveConnection) readLoop() {
 	defer c.Close() // close on any errors or loop break.
+
 	for {
+		c.connMu.Lock()
+		conn := c.conn
+		c.connMu.Unlock()
+
+		if c.config.HeartbeatTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(c.config.HeartbeatTimeout))
+		}
+
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if c.isClosed() {
+				// golog.Debugf("stop receiving by close")
+				return
+			}
+
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				err = fmt.Errorf("live: no message received for %s, heartbeats missed", c.config.HeartbeatTimeout)
+			} else {
+				err = fmt.Errorf("live: read: %v", err)
+			}
+			c.sendErr(err)
+
+			// the connection can't be used after a read failure, reconnect or stop.
+			if c.config.Reconnect && c.reconnect(err) {
+				continue
+			}
+
+			c.connMu.Lock()
+			if c.closeErr == nil && !c.isClosed() {
+				c.closeErr = err
+			}
+			c.connMu.Unlock()
+			return
+		}
+
+		resp := LiveResponse{}
+		if err = json.Unmarshal(message, &resp); err != nil {
+			c.sendErr(fmt.Errorf("live: read json: %v", err))
+			continue
+		}
+
+		c.logger.Debug("read", F("type", resp.Type), F("correlationId", resp.CorrelationID), F("content", c.redactResponseContent(resp)))
+
+		c.trackResponse(resp)
+
+		// the futures are resolved before the listeners fire, a listener may send a typed request after the login.
+		c.resolveResponse(resp)
+
+		// fire.
+		c.mu.RLock()
+		callbacks, ok := c.listeners[resp.Type]
+		c.mu.RUnlock()
+
+		if ok {
+			for _, cb := range callbacks {
+				if err := cb(c, resp); err != nil {
+					// return err // break and exit the loop on first failure.
+					c.sendErr(err) // don't break, just add the error.
+				}
+			}
+		}
+	}
+}
+
+// reconnect connects again, with backoff, logs in and replays the subscriptions,
+// it returns false if the connection is closed or the `LiveConfiguration#MaxReconnects` are exhausted.
+func (c *LiveConnection) reconnect(cause error) bool {
+	c.connMu.Lock()
+	c.conn.Close()
+	c.connMu.Unlock()
+
+	policy := RetryPolicy{MinBackoff: c.config.ReconnectMinBackoff, MaxBackoff: c.config.ReconnectMaxBackoff}
+
+	for attempt := 1; ; attempt++ {
+		if max := c.config.MaxReconnects; max > 0 && attempt > max {
+			c.connMu.Lock()
+			c.closeErr = fmt.Errorf("live: gave up after %d reconnection attempts: %v", attempt-1, cause)
+			c.connMu.Unlock()
+
+			c.fireLifecycle(LiveLifecycleEvent{Type: LiveGaveUp, Attempt: attempt - 1, Err: cause})
+			return false
+		}
+
+		c.fireLifecycle(LiveLifecycleEvent{Type: LiveReconnecting, Attempt: attempt, Err: cause})
+
+		timer := time.NewTimer(policy.backoff(attempt, nil))
 		select {
 		case <-c.receiveStop:
-			// golog.Debugf("stop receiving by signal")
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+
+		if err := c.relogin(); err != nil {
+			c.logger.Debug(err.Error())
+			cause = err
+			continue
+		}
+
+		c.fireLifecycle(LiveLifecycleEvent{Type: LiveConnected, Attempt: attempt})
+
+		n, err := c.resubscribe()
+		if err != nil {
+			c.logger.Debug(err.Error())
+			cause = err
+
+			// the connection can't be used after a write failure, close it before the next attempt dials a new one.
+			c.connMu.Lock()
+			c.conn.Close()
+			c.connMu.Unlock()
+			continue
+		}
+
+		if n > 0 {
+			c.fireLifecycle(LiveLifecycleEvent{Type: LiveResubscribed, Attempt: attempt, Subscriptions: n})
+		}
+
+		return true
+	}
+}
+
+// relogin dials a new connection and logs in, the login's response is not fired to the listeners,
+// the listeners that subscribe on the login's success would subscribe twice, the subscriptions are replayed instead.
+func (c *LiveConnection) relogin() error {
+	conn, err := c.dial()
+	if err != nil {
+		return err
+	}
+
+	req := LiveRequest{
+		Type:          LoginRequest,
+		CorrelationID: liveLoginCorrelationID,
+		Content:       makeLoginContent(c.config.User, c.config.Password),
+	}
+
+	c.logger.Debug("login", F("request", fmt.Sprintf("%#+v", c.redactRequest(req))))
+
+	deadline := time.Now().Add(c.config.HandshakeTimeout)
+	conn.SetWriteDeadline(deadline)
+	conn.SetReadDeadline(deadline)
+
+	if err = conn.WriteJSON(req); err != nil {
+		conn.Close()
+		return fmt.Errorf("login failure: %v", err)
+	}
+
+	var authToken string
+	for {
+		resp := LiveResponse{}
+		if err = conn.ReadJSON(&resp); err != nil {
+			conn.Close()
+			return fmt.Errorf("login failure: %v", err)
+		}
+
+		// there are no subscriptions yet, anything else than the login's response is a heartbeat.
+		if resp.CorrelationID != liveLoginCorrelationID || resp.Type == HeartbeatResponse {
+			continue
+		}
+
+		if resp.Type != SuccessResponse {
+			conn.Close()
+			return fmt.Errorf("login failure: %s: %s", resp.Type, string(resp.Content))
+		}
+
+		if err = json.Unmarshal(resp.Content, &authToken); err != nil {
+			conn.Close()
+			return fmt.Errorf("login failure: %v", err)
+		}
+
+		conn.SetWriteDeadline(time.Time{})
+		conn.SetReadDeadline(time.Time{})
+		break
+	}
+
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+
+	if c.isClosed() {
+		conn.Close()
+		return fmt.Errorf("live: connection closed")
+	}
+
+	c.conn.Close() // the previous one, it's closed already, the close is a no-op then.
+	c.conn = conn
+	c.authToken = authToken
+	c.logger.Debug("login succeed", F("authToken", c.unredacted.redact(authToken)))
+	return nil
+}
+
+// liveSubscription is a SUBSCRIBE request that is replayed on reconnection,
+// the topics are filled by its SUCCESS response, they are used to remove it on UNSUBSCRIBE.
+type liveSubscription struct {
+	request LiveRequest
+	topics  []string
+}
+
+// trackRequest keeps the SUBSCRIBE requests and removes the ones that their topics are unsubscribed,
+// the UNSUBSCRIBE's content is in the form of {"topics": ["reddit_posts"]}.
+func (c *LiveConnection) trackRequest(req LiveRequest) {
+	switch req.Type {
+	case SubscribeRequest:
+		c.subscriptions = append(c.subscriptions, liveSubscription{request: req})
+	case UnsubscribeRequest:
+		var content struct {
+			Topics []string `json:"topics"`
+		}
+		if err := json.Unmarshal([]byte(req.Content), &content); err != nil {
 			return
-		default:
-			resp := LiveResponse{}
-			if err := c.conn.ReadJSON(&resp); err != nil {
-				c.sendErr(fmt.Errorf("live: read json: %v", err))
-				continue
+		}
+
+		unsubscribed := make(map[string]bool, len(content.Topics))
+		for _, topic := range content.Topics {
+			unsubscribed[topic] = true
+		}
+
+		subscriptions := c.subscriptions[:0]
+		for _, sub := range c.subscriptions {
+			keep := len(sub.topics) == 0
+			for _, topic := range sub.topics {
+				if !unsubscribed[topic] {
+					keep = true
+					break
+				}
+			}
+
+			if keep {
+				subscriptions = append(subscriptions, sub)
 			}
+		}
+		c.subscriptions = subscriptions
+	}
+}
 
-			golog.Debugf("read: %#+v", resp)
+// trackResponse sets the topics of the subscription that the SUCCESS response belongs to,
+// the content is the comma separated topics' names.
+// The subscription that the server rejected, with an ERROR or an INVALIDREQUEST response, is removed, it's not replayed.
+func (c *LiveConnection) trackResponse(resp LiveResponse) {
+	var topics string
+	switch resp.Type {
+	case SuccessResponse:
+		if err := json.Unmarshal(resp.Content, &topics); err != nil || topics == "" {
+			return
+		}
+	case ErrorResponse, InvalidRequestResponse:
+	default:
+		return
+	}
 
-			// fire.
-			c.mu.RLock()
-			callbacks, ok := c.listeners[resp.Type]
-			c.mu.RUnlock()
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
 
-			if ok {
-				for _, cb := range callbacks {
-					if err := cb(c, resp); err != nil {
-						// return err // break and exit the loop on first failure.
-						c.sendErr(err) // don't break, just add the error.
-					}
-				}
+	for i := len(c.subscriptions) - 1; i >= 0; i-- {
+		sub := &c.subscriptions[i]
+		if sub.request.CorrelationID != resp.CorrelationID {
+			continue
+		}
+
+		if resp.Type != SuccessResponse {
+			c.subscriptions = append(c.subscriptions[:i], c.subscriptions[i+1:]...)
+			return
+		}
+
+		if len(sub.topics) > 0 {
+			continue
+		}
+
+		for _, topic := range strings.Split(topics, ",") {
+			if topic = strings.TrimSpace(topic); topic != "" {
+				sub.topics = append(sub.topics, topic)
 			}
 		}
+		return
+	}
+}
+
+// resubscribe sends again the SUBSCRIBE requests, with the new auth token, it returns the number of them.
+func (c *LiveConnection) resubscribe() (int, error) {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+
+	for i := range c.subscriptions {
+		req := c.subscriptions[i].request
+		req.AuthToken = c.authToken
+
+		c.logger.Debug("resubscribe", F("request", fmt.Sprintf("%#+v", c.redactRequest(req))))
+		if err := c.conn.WriteJSON(req); err != nil {
+			return i, fmt.Errorf("live: resubscribe: %v", err)
+		}
+	}
+
+	return len(c.subscriptions), nil
+}
+
+// LiveLifecycleEventType is the type of the `LiveLifecycleEvent`.
+type LiveLifecycleEventType string
+
+const (
+	// LiveReconnecting is fired before each reconnection attempt, its `Err` is the failure that caused it.
+	LiveReconnecting LiveLifecycleEventType = "RECONNECTING"
+	// LiveConnected is fired when a reconnection attempt connected and logged in.
+	LiveConnected LiveLifecycleEventType = "CONNECTED"
+	// LiveResubscribed is fired when the SUBSCRIBE requests are sent again after a reconnection.
+	LiveResubscribed LiveLifecycleEventType = "RESUBSCRIBED"
+	// LiveGaveUp is fired when the `LiveConfiguration#MaxReconnects` are exhausted, the connection is closed afterwards.
+	LiveGaveUp LiveLifecycleEventType = "GAVEUP"
+)
+
+// LiveLifecycleEvent describes a change of the connection's state, see `OnLifecycle`.
+type LiveLifecycleEvent struct {
+	Type LiveLifecycleEventType
+	// Attempt is the reconnection attempt, starting from 1.
+	Attempt int
+	// Subscriptions is the number of the replayed SUBSCRIBE requests, on `LiveResubscribed`.
+	Subscriptions int
+	// Err is the failure, on `LiveReconnecting` and `LiveGaveUp`.
+	Err error
+}
+
+// LiveLifecycleListener is the declaration for the connection's lifecycle events listener.
+type LiveLifecycleListener func(LiveLifecycleEvent)
+
+// OnLifecycle adds a listener of the connection's lifecycle events, they are fired on reconnection,
+// see `LiveConfiguration#Reconnect`.
+func (c *LiveConnection) OnLifecycle(cb LiveLifecycleListener) {
+	c.mu.Lock()
+	c.lifecycleListeners = append(c.lifecycleListeners, cb)
+	c.mu.Unlock()
+}
+
+func (c *LiveConnection) fireLifecycle(event LiveLifecycleEvent) {
+	c.logger.Debug("lifecycle", F("type", event.Type), F("attempt", event.Attempt), F("error", event.Err))
+
+	c.mu.RLock()
+	listeners := c.lifecycleListeners
+	c.mu.RUnlock()
+
+	for _, cb := range listeners {
+		cb(event)
 	}
 }
 
@@ -332,7 +756,13 @@ type LivePublisher interface {
 
 // Publish sends a `LiveRequest` based on the input arguments
 // as JSON data to the websocket server.
+//
+// The SUBSCRIBE requests are kept, even if they failed to be sent, to be replayed on reconnection,
+// unless the server rejects them with an ERROR or an INVALIDREQUEST response.
 func (c *LiveConnection) Publish(typ RequestType, correlationID int64, content string) error {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+
 	req := LiveRequest{
 		AuthToken:     c.authToken,
 		Type:          typ,
@@ -340,7 +770,8 @@ func (c *LiveConnection) Publish(typ RequestType, correlationID int64, content s
 		Content:       content,
 	}
 
-	golog.Debugf("publish: %#+v", req)
+	c.trackRequest(req)
+	c.logger.Debug("publish", F("request", fmt.Sprintf("%#+v", c.redactRequest(req))))
 
 	return c.conn.WriteJSON(req)
 }
@@ -389,15 +820,18 @@ func (c *LiveConnection) OnSuccess(cb LiveListener) { c.On(SuccessResponse, cb)
 //
 // If `Close` called more than once then it will return nil and nothing will happen.
 func (c *LiveConnection) Close() error {
-	golog.Debugf("terminating websocket connection...")
+	c.logger.Debug("terminating websocket connection...")
 	// if we try to close a closed channel panic will occur,
 	// in order to prevent it we've added an atomic checkpoint.
-	if atomic.LoadUint32(&c.closed) > 0 {
+	if !atomic.CompareAndSwapUint32(&c.closed, 0, 1) {
 		// means already closed.
 		return nil
 	}
 
-	atomic.StoreUint32(&c.closed, 1)
 	close(c.receiveStop) // stop receiving, see `readLoop`.
+	c.closeFutures()
+
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
 	return c.conn.Close()
 }
@@ -0,0 +1,247 @@
+package lenses
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// liveLoginCorrelationID is the correlation id of the login request.
+	liveLoginCorrelationID int64 = 1
+	// liveFirstCorrelationID is the first correlation id that the typed requests, i.e `Subscribe`, allocate,
+	// the ids below it are left for the `Publish` callers.
+	liveFirstCorrelationID int64 = 100
+	// defaultLiveRequestTimeout is the default `LiveConfiguration#RequestTimeout`.
+	defaultLiveRequestTimeout = 30 * time.Second
+)
+
+// ErrLiveRequestTimeout is fired when the response of a typed request, i.e `Subscribe`,
+// is not received within the `LiveConfiguration#RequestTimeout`.
+var ErrLiveRequestTimeout = errors.New("live: request timeout")
+
+// errLiveConnectionClosed is fired to the pending requests when the connection is closed.
+var errLiveConnectionClosed = errors.New("live: connection closed")
+
+// LiveResponseError is the error of a request that the server rejected with an ERROR or an INVALIDREQUEST response.
+type LiveResponseError struct {
+	Response LiveResponse
+}
+
+func (err *LiveResponseError) Error() string {
+	var msg string
+	if json.Unmarshal(err.Response.Content, &msg) != nil {
+		msg = string(err.Response.Content)
+	}
+
+	return fmt.Sprintf("live: %s: %s", err.Response.Type, msg)
+}
+
+// LiveFuture is the pending response of a typed request, i.e `Subscribe`,
+// it is resolved by the response with the same correlation id, the `LiveConfiguration#RequestTimeout`,
+// the request's context or the connection's close, whatever comes first.
+//
+// The listeners, i.e `OnSuccess`, are still fired for the responses of the typed requests,
+// but a listener should not `Wait` as the responses are read by the same go routine that fires the listeners.
+type LiveFuture struct {
+	// CorrelationID is the allocated correlation id of the request.
+	CorrelationID int64
+
+	done chan struct{}
+	once sync.Once
+	resp LiveResponse
+	err  error
+}
+
+func newLiveFuture(correlationID int64) *LiveFuture {
+	return &LiveFuture{CorrelationID: correlationID, done: make(chan struct{})}
+}
+
+func (f *LiveFuture) resolve(resp LiveResponse, err error) {
+	f.once.Do(func() {
+		f.resp, f.err = resp, err
+		close(f.done)
+	})
+}
+
+// Done returns a channel that is closed when the future is resolved.
+func (f *LiveFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until the future is resolved and returns the SUCCESS response.
+// The error is a `*LiveResponseError` if the server responded with an ERROR or an INVALIDREQUEST,
+// the `ErrLiveRequestTimeout`, the context's error or the failure of sending the request.
+func (f *LiveFuture) Wait() (LiveResponse, error) {
+	<-f.done
+	return f.resp, f.err
+}
+
+// expect registers a future for the "correlationID", it is resolved by the reader, see `resolveResponse`,
+// or it fails when the "ctx" is done or the `LiveConfiguration#RequestTimeout` is exceeded.
+func (c *LiveConnection) expect(ctx context.Context, correlationID int64) *LiveFuture {
+	f := newLiveFuture(correlationID)
+
+	if err := ctx.Err(); err != nil {
+		f.resolve(LiveResponse{}, err)
+		return f
+	}
+
+	c.futuresMu.Lock()
+	if c.isClosed() {
+		c.futuresMu.Unlock()
+		f.resolve(LiveResponse{}, errLiveConnectionClosed)
+		return f
+	}
+	c.futures[correlationID] = f
+	c.futuresMu.Unlock()
+
+	go func() {
+		timer := time.NewTimer(c.config.RequestTimeout)
+		defer timer.Stop()
+
+		select {
+		case <-f.done:
+		case <-timer.C:
+			c.resolveFuture(correlationID, LiveResponse{}, ErrLiveRequestTimeout)
+		case <-ctx.Done():
+			c.resolveFuture(correlationID, LiveResponse{}, ctx.Err())
+		}
+	}()
+
+	return f
+}
+
+// resolveFuture resolves and removes the pending future of the "correlationID", if any.
+func (c *LiveConnection) resolveFuture(correlationID int64, resp LiveResponse, err error) {
+	c.futuresMu.Lock()
+	f, ok := c.futures[correlationID]
+	delete(c.futures, correlationID)
+	c.futuresMu.Unlock()
+
+	if ok {
+		f.resolve(resp, err)
+	}
+}
+
+// resolveResponse resolves the pending future of a SUCCESS, ERROR or INVALIDREQUEST response,
+// the login's success sets the auth token before that, so the typed requests that wait for the login can use it.
+func (c *LiveConnection) resolveResponse(resp LiveResponse) {
+	switch resp.Type {
+	case SuccessResponse:
+		if resp.CorrelationID == liveLoginCorrelationID {
+			var authToken string
+			if err := json.Unmarshal(resp.Content, &authToken); err != nil {
+				err = fmt.Errorf("live: login: %v", err)
+				c.sendErr(err)
+				c.resolveFuture(resp.CorrelationID, resp, err)
+				return
+			}
+
+			c.connMu.Lock()
+			c.authToken = authToken
+			c.connMu.Unlock()
+			c.logger.Debug("login succeed", F("authToken", c.unredacted.redact(authToken)))
+		}
+
+		c.resolveFuture(resp.CorrelationID, resp, nil)
+	case ErrorResponse, InvalidRequestResponse:
+		c.resolveFuture(resp.CorrelationID, resp, &LiveResponseError{Response: resp})
+	}
+}
+
+// closeFutures fails the pending futures, it's called on `Close`.
+func (c *LiveConnection) closeFutures() {
+	c.futuresMu.Lock()
+	futures := c.futures
+	c.futures = make(map[int64]*LiveFuture)
+	c.futuresMu.Unlock()
+
+	for _, f := range futures {
+		f.resolve(LiveResponse{}, errLiveConnectionClosed)
+	}
+}
+
+// request allocates a correlation id and sends the "typ" request with the "content" as JSON,
+// it waits for the login first, the request needs its auth token.
+func (c *LiveConnection) request(ctx context.Context, typ RequestType, content interface{}) *LiveFuture {
+	correlationID := atomic.AddInt64(&c.correlationID, 1)
+	f := c.expect(ctx, correlationID)
+
+	b, err := json.Marshal(content)
+	if err != nil {
+		c.resolveFuture(correlationID, LiveResponse{}, fmt.Errorf("live: %s: %v", typ, err))
+		return f
+	}
+
+	select {
+	case <-f.Done():
+		return f // closed, canceled or failed already, don't send it.
+	default:
+	}
+
+	select {
+	case <-c.loginFuture.Done():
+		if _, err = c.loginFuture.Wait(); err != nil {
+			c.resolveFuture(correlationID, LiveResponse{}, fmt.Errorf("live: login failure: %v", err))
+			return f
+		}
+	case <-f.Done():
+		return f // timed out or canceled while waiting for the login.
+	}
+
+	if err = c.Publish(typ, correlationID, string(b)); err != nil {
+		c.resolveFuture(correlationID, LiveResponse{}, fmt.Errorf("live: %s: %v", typ, err))
+	}
+
+	return f
+}
+
+// Subscribe sends a SUBSCRIBE request of the "sqls" queries,
+// the records are received by the `OnKafkaMessage` listeners.
+// The response's content is the comma separated names of the queries' topics.
+//
+// The subscription is replayed on reconnection, see `LiveConfiguration#Reconnect`.
+//
+// Usage:
+// resp, err := c.Subscribe(ctx, "SELECT * FROM reddit_posts LIMIT 3").Wait()
+func (c *LiveConnection) Subscribe(ctx context.Context, sqls ...string) *LiveFuture {
+	return c.request(ctx, SubscribeRequest, struct {
+		SQLs []string `json:"sqls"`
+	}{sqls})
+}
+
+// Unsubscribe sends an UNSUBSCRIBE request of the "topics", their subscriptions are not replayed on reconnection.
+func (c *LiveConnection) Unsubscribe(ctx context.Context, topics ...string) *LiveFuture {
+	return c.request(ctx, UnsubscribeRequest, struct {
+		Topics []string `json:"topics"`
+	}{topics})
+}
+
+// PublishMessage sends a PUBLISH request which writes a message with the "key" and the "value" to the "topic".
+func (c *LiveConnection) PublishMessage(ctx context.Context, topic, key, value string) *LiveFuture {
+	return c.request(ctx, PublishRequest, struct {
+		Topic string `json:"topic"`
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}{topic, key, value})
+}
+
+// LiveCommit is the offset of a topic's partition, see `Commit`.
+type LiveCommit struct {
+	Topic     string `json:"topic"`
+	Partition int    `json:"partition"`
+	Offset    int64  `json:"offset"`
+}
+
+// Commit sends a COMMIT request of the "offset" of the "topic"'s "partition",
+// the connection's consumer group, see `LiveConfiguration#ClientID`, continues after that offset.
+func (c *LiveConnection) Commit(ctx context.Context, topic string, partition int, offset int64) *LiveFuture {
+	return c.request(ctx, CommitRequest, struct {
+		Commits []LiveCommit `json:"commits"`
+	}{[]LiveCommit{{Topic: topic, Partition: partition, Offset: offset}}})
+}
@@ -0,0 +1,424 @@
+// Black-box testing for the websocket live connection against a local websocket server.
+package lenses_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/landoop/lenses-go"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestLiveConnectionReconnect(t *testing.T) {
+	var connections int32
+	requests := make(chan lenses.LiveRequest, 16)
+	upgrader := websocket.Upgrader{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&connections, 1)
+		if n > 2 {
+			http.Error(w, "restarting", http.StatusServiceUnavailable)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			var req lenses.LiveRequest
+			if err = conn.ReadJSON(&req); err != nil {
+				return
+			}
+			requests <- req
+
+			switch req.Type {
+			case lenses.LoginRequest:
+				if n == 1 {
+					// give time to the listeners to be registered.
+					time.Sleep(100 * time.Millisecond)
+				}
+				conn.WriteJSON(lenses.LiveResponse{Type: lenses.SuccessResponse, CorrelationID: 1, Content: json.RawMessage(fmt.Sprintf(`"token-%d"`, n))})
+			case lenses.SubscribeRequest:
+				conn.WriteJSON(lenses.LiveResponse{Type: lenses.SuccessResponse, CorrelationID: req.CorrelationID, Content: json.RawMessage(`"reddit_posts"`)})
+				conn.WriteJSON(lenses.LiveResponse{Type: lenses.KafkaMessageResponse, CorrelationID: req.CorrelationID, Content: json.RawMessage(`[{"key":"1","value":"{}"}]`)})
+				if n == 1 {
+					return // the connection is lost, i.e lenses restarts.
+				}
+				// the second connection stays silent, without heartbeats.
+			}
+		}
+	}))
+	defer srv.Close()
+
+	conn, err := lenses.OpenLiveConnection(lenses.LiveConfiguration{
+		Host:                srv.URL,
+		User:                "user",
+		Password:            "pass",
+		Reconnect:           true,
+		MaxReconnects:       2,
+		ReconnectMinBackoff: 10 * time.Millisecond,
+		ReconnectMaxBackoff: 20 * time.Millisecond,
+		HeartbeatTimeout:    300 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu               sync.Mutex
+		events           []string
+		logins, messages int
+	)
+
+	conn.OnLifecycle(func(event lenses.LiveLifecycleEvent) {
+		mu.Lock()
+		events = append(events, string(event.Type))
+		mu.Unlock()
+	})
+
+	conn.OnSuccess(func(pub lenses.LivePublisher, resp lenses.LiveResponse) error {
+		if resp.CorrelationID != 1 {
+			return nil
+		}
+
+		mu.Lock()
+		logins++
+		mu.Unlock()
+		return pub.Publish(lenses.SubscribeRequest, 2, `{"sqls": ["SELECT * FROM reddit_posts"]}`)
+	})
+
+	conn.OnKafkaMessage(func(lenses.LivePublisher, lenses.LiveResponse) error {
+		mu.Lock()
+		messages++
+		mu.Unlock()
+		return nil
+	})
+
+	// nobody receives the `Err`, the reader should not block.
+	if err = conn.Wait(nil); err == nil || !strings.Contains(err.Error(), "gave up after 2 reconnection attempts") {
+		t.Fatalf("expected the reconnection to give up but got: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if expected, got := "RECONNECTING,CONNECTED,RESUBSCRIBED,RECONNECTING,RECONNECTING,GAVEUP", strings.Join(events, ","); expected != got {
+		t.Fatalf("expected the lifecycle events %s but got %s", expected, got)
+	}
+
+	// the login's response of the reconnection is not fired, the subscription is replayed instead.
+	if logins != 1 || messages != 2 {
+		t.Fatalf("expected 1 login and 2 messages but got %d logins and %d messages", logins, messages)
+	}
+
+	close(requests)
+	var got []string
+	for req := range requests {
+		got = append(got, fmt.Sprintf("%s:%s", req.Type, req.AuthToken))
+	}
+
+	if expected := "LOGIN:,SUBSCRIBE:token-1,LOGIN:,SUBSCRIBE:token-2"; expected != strings.Join(got, ",") {
+		t.Fatalf("expected the requests %s but got %s", expected, strings.Join(got, ","))
+	}
+}
+
+func TestLiveConnectionRequests(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	upgrader := websocket.Upgrader{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			var req lenses.LiveRequest
+			if err = conn.ReadJSON(&req); err != nil {
+				return
+			}
+			mu.Lock()
+			requests = append(requests, fmt.Sprintf("%s:%d:%s:%s", req.Type, req.CorrelationID, req.AuthToken, req.Content))
+			mu.Unlock()
+
+			resp := lenses.LiveResponse{Type: lenses.SuccessResponse, CorrelationID: req.CorrelationID}
+			switch req.Type {
+			case lenses.LoginRequest:
+				// the typed requests should wait for the login.
+				time.Sleep(100 * time.Millisecond)
+				resp.Content = json.RawMessage(`"token"`)
+			case lenses.SubscribeRequest:
+				resp.Content = json.RawMessage(`"reddit_posts"`)
+			case lenses.UnsubscribeRequest:
+				resp.Type, resp.Content = lenses.ErrorResponse, json.RawMessage(`"not subscribed"`)
+			case lenses.PublishRequest:
+				resp.Type, resp.Content = lenses.InvalidRequestResponse, json.RawMessage(`"invalid topic"`)
+			case lenses.CommitRequest:
+				continue // no response.
+			}
+
+			conn.WriteJSON(resp)
+		}
+	}))
+	defer srv.Close()
+
+	conn, err := lenses.OpenLiveConnection(lenses.LiveConfiguration{
+		Host:           srv.URL,
+		User:           "user",
+		Password:       "pass",
+		RequestTimeout: 300 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+
+	resp, err := conn.Subscribe(ctx, `SELECT * FROM reddit_posts WHERE title = "a"`).Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := `"reddit_posts"`, string(resp.Content); expected != got {
+		t.Fatalf("expected the subscribe's response %s but got %s", expected, got)
+	}
+
+	_, err = conn.Unsubscribe(ctx, "reddit_posts").Wait()
+	if respErr, ok := err.(*lenses.LiveResponseError); !ok || respErr.Response.Type != lenses.ErrorResponse {
+		t.Fatalf("expected an ERROR response but got: %v", err)
+	}
+	if expected, got := "live: ERROR: not subscribed", err.Error(); expected != got {
+		t.Fatalf("expected the error %q but got %q", expected, got)
+	}
+
+	if _, err = conn.PublishMessage(ctx, "reddit_posts", "1", "{}").Wait(); err == nil || err.Error() != "live: INVALIDREQUEST: invalid topic" {
+		t.Fatalf("expected an INVALIDREQUEST response but got: %v", err)
+	}
+
+	if _, err = conn.Commit(ctx, "reddit_posts", 0, 41).Wait(); err != lenses.ErrLiveRequestTimeout {
+		t.Fatalf("expected the request timeout but got: %v", err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err = conn.Commit(canceled, "reddit_posts", 0, 42).Wait(); err != context.Canceled {
+		t.Fatalf("expected the context's error but got: %v", err)
+	}
+
+	conn.Close()
+	if _, err = conn.Subscribe(ctx, "SELECT * FROM reddit_posts").Wait(); err == nil {
+		t.Fatalf("expected the closed connection to fail the request")
+	}
+
+	mu.Lock()
+	got := requests
+	mu.Unlock()
+
+	expected := []string{
+		`LOGIN:1::{"user": "user", "password": "pass"}`,
+		`SUBSCRIBE:100:token:{"sqls":["SELECT * FROM reddit_posts WHERE title = \"a\""]}`,
+		`UNSUBSCRIBE:101:token:{"topics":["reddit_posts"]}`,
+		`PUBLISH:102:token:{"topic":"reddit_posts","key":"1","value":"{}"}`,
+		`COMMIT:103:token:{"commits":[{"topic":"reddit_posts","partition":0,"offset":41}]}`,
+	}
+	if strings.Join(expected, "\n") != strings.Join(got, "\n") {
+		t.Fatalf("expected the requests:\n%s\nbut got:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestLiveConnectionLoggerRedaction(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			var req lenses.LiveRequest
+			if err = conn.ReadJSON(&req); err != nil {
+				return
+			}
+
+			resp := lenses.LiveResponse{Type: lenses.SuccessResponse, CorrelationID: req.CorrelationID, Content: json.RawMessage(`"reddit_posts"`)}
+			if req.Type == lenses.LoginRequest {
+				resp.Content = json.RawMessage(`"secrettoken"`)
+			}
+			conn.WriteJSON(resp)
+		}
+	}))
+	defer srv.Close()
+
+	for _, unredacted := range []bool{false, true} {
+		logger := new(testLogger)
+		conn, err := lenses.OpenLiveConnection(lenses.LiveConfiguration{
+			Host:           srv.URL,
+			User:           "admin",
+			Password:       "secretpass",
+			Logger:         logger,
+			UnredactedLogs: unredacted,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the subscribe waits for the login's response and it sends the auth token.
+		if _, err = conn.Subscribe(context.Background(), "SELECT * FROM reddit_posts").Wait(); err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+
+		logger.mu.Lock()
+		logs := fmt.Sprintf("%v", logger.messages)
+		logger.mu.Unlock()
+
+		for _, secret := range []string{"secrettoken", "secretpass"} {
+			if got := strings.Contains(logs, secret); got != unredacted {
+				t.Fatalf("[unredacted: %v] expected secret '%s' to be logged: %v but got: %v\n%s", unredacted, secret, unredacted, got, logs)
+			}
+		}
+	}
+}
+
+func TestLiveConnectionResubscribe(t *testing.T) {
+	var (
+		connections int32
+		handlers    sync.WaitGroup
+		resets      = make(chan struct{}, 2)
+		replayed    = make(chan string, 4)
+	)
+	upgrader := websocket.Upgrader{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlers.Add(1)
+		defer handlers.Done()
+
+		n := atomic.AddInt32(&connections, 1)
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			var req lenses.LiveRequest
+			if err = conn.ReadJSON(&req); err != nil {
+				return
+			}
+
+			switch req.Type {
+			case lenses.LoginRequest:
+				conn.WriteJSON(lenses.LiveResponse{Type: lenses.SuccessResponse, CorrelationID: req.CorrelationID, Content: json.RawMessage(`"token"`)})
+				if n == 2 || n == 3 {
+					// reset the connection after the login, the resubscribe fails to write.
+					time.Sleep(50 * time.Millisecond)
+					conn.UnderlyingConn().(*net.TCPConn).SetLinger(0)
+					conn.Close()
+					resets <- struct{}{}
+					return
+				}
+			case lenses.SubscribeRequest:
+				if n > 1 {
+					replayed <- req.Content
+				}
+
+				if strings.Contains(req.Content, "invalid") {
+					conn.WriteJSON(lenses.LiveResponse{Type: lenses.ErrorResponse, CorrelationID: req.CorrelationID, Content: json.RawMessage(`"invalid query"`)})
+					continue
+				}
+
+				conn.WriteJSON(lenses.LiveResponse{Type: lenses.SuccessResponse, CorrelationID: req.CorrelationID, Content: json.RawMessage(`"reddit_posts"`)})
+				if n == 1 {
+					return // the connection is lost after the subscriptions.
+				}
+			}
+		}
+	}))
+	defer srv.Close()
+
+	openFiles := func() int {
+		files, err := ioutil.ReadDir("/proc/self/fd")
+		if err != nil {
+			return -1
+		}
+		return len(files)
+	}
+	filesBefore := openFiles()
+
+	conn, err := lenses.OpenLiveConnection(lenses.LiveConfiguration{
+		Host:                srv.URL,
+		Reconnect:           true,
+		MaxReconnects:       5,
+		ReconnectMinBackoff: 10 * time.Millisecond,
+		ReconnectMaxBackoff: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := make(chan lenses.LiveLifecycleEvent, 16)
+	conn.OnLifecycle(func(event lenses.LiveLifecycleEvent) {
+		if event.Type == lenses.LiveConnected && atomic.LoadInt32(&connections) <= 3 {
+			// wait for the server to reset the connection before the resubscribe.
+			<-resets
+			time.Sleep(50 * time.Millisecond)
+		}
+		events <- event
+	})
+
+	ctx := context.Background()
+	if _, err = conn.Subscribe(ctx, "SELECT * FROM invalid").Wait(); err == nil {
+		t.Fatalf("expected the subscription to be rejected")
+	}
+
+	if _, err = conn.Subscribe(ctx, "SELECT * FROM reddit_posts").Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for event := range events {
+		got = append(got, fmt.Sprintf("%s:%d:%d", event.Type, event.Attempt, event.Subscriptions))
+		if event.Type == lenses.LiveResubscribed || event.Type == lenses.LiveGaveUp {
+			break
+		}
+	}
+
+	expected := "RECONNECTING:1:0,CONNECTED:1:0,RECONNECTING:2:0,CONNECTED:2:0,RECONNECTING:3:0,CONNECTED:3:0,RESUBSCRIBED:3:1"
+	if expected != strings.Join(got, ",") {
+		t.Fatalf("expected the lifecycle events %s but got %s", expected, strings.Join(got, ","))
+	}
+
+	// the rejected subscription is not replayed.
+	if expected, got := `{"sqls":["SELECT * FROM reddit_posts"]}`, <-replayed; expected != got {
+		t.Fatalf("expected the replayed subscription %s but got %s", expected, got)
+	}
+
+	conn.Close()
+	handlers.Wait()
+
+	// the connections of the failed resubscribes are closed too.
+	if filesBefore >= 0 {
+		if filesAfter := openFiles(); filesAfter > filesBefore {
+			t.Fatalf("expected the connections to be closed but %d files are still open", filesAfter-filesBefore)
+		}
+	}
+}